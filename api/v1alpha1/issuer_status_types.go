@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type IssuerStatus struct {
@@ -27,4 +28,24 @@ type IssuerStatus struct {
 	// +listMapKey=type
 	// +optional
 	Conditions []cmapi.IssuerCondition `json:"conditions,omitempty"`
+
+	// LastCheckError contains the full error that was returned by the last
+	// failed check of the Issuer and the time at which it was observed.
+	// It is cleared once the check succeeds.
+	// +optional
+	LastCheckError *IssuerCheckError `json:"lastCheckError,omitempty"`
+
+	// LastCheckTime is the timestamp of the last successful check of the
+	// Issuer. It can be used to alert on issuers that have not been checked
+	// successfully for a while.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+}
+
+type IssuerCheckError struct {
+	// Message is the full error message returned by the check.
+	Message string `json:"message"`
+
+	// Time is the timestamp at which the error was observed.
+	Time metav1.Time `json:"time"`
 }
@@ -22,6 +22,11 @@ const (
 	// reconciles a CertificateRequest which does not already have a Ready
 	// condition.
 	CertificateRequestConditionReasonInitializing = "Initializing"
+
+	// CertificateRequestReasonRequestDeadlineExceeded is the reason of the
+	// event that is emitted when a CertificateRequest is failed because the
+	// deadline set using the CertificateRequestMaxWaitAnnotation has passed.
+	CertificateRequestReasonRequestDeadlineExceeded = "RequestDeadlineExceeded"
 )
 
 const (
@@ -37,3 +42,140 @@ const (
 
 	IssuerConditionReasonFailed = "Failed"
 )
+
+const (
+	// CertificateRequestConditionIncompleteChain is the type of the condition
+	// that is set when the certificate chain returned by the signer can't be
+	// verified up to a self-signed root certificate in the returned bundle.
+	// This condition is only set if the chain completeness check is enabled.
+	CertificateRequestConditionIncompleteChain = "IncompleteChain"
+
+	CertificateRequestConditionReasonIncompleteChain = "IncompleteChain"
+
+	CertificateRequestConditionReasonCompleteChain = "CompleteChain"
+)
+
+const (
+	// CertificateRequestConditionQuotaExceeded is the type of the condition
+	// that is set when the QuotaCheck function reports that the quota for the
+	// request is exceeded. The request is retried until the quota check
+	// succeeds.
+	CertificateRequestConditionQuotaExceeded = "QuotaExceeded"
+
+	CertificateRequestConditionReasonQuotaExceeded = "QuotaExceeded"
+)
+
+const (
+	// CertificateRequestConditionIssuerNotFound is the type of the condition
+	// that tracks whether the issuer of a CertificateRequest exists. It is True
+	// with the IssuerNotCreated reason while the issuer has never been found,
+	// and True with the IssuerDeleted reason if the issuer was deleted while
+	// the request was being processed. In that case, its last transition time
+	// is the start of the grace period, after which the request is failed
+	// permanently. It is set to False every time the issuer is found, only a
+	// request with this False condition starts the grace period when its
+	// issuer is deleted.
+	CertificateRequestConditionIssuerNotFound = "IssuerNotFound"
+
+	CertificateRequestConditionReasonIssuerNotCreated = "IssuerNotCreated"
+
+	CertificateRequestConditionReasonIssuerDeleted = "IssuerDeleted"
+
+	CertificateRequestConditionReasonIssuerFound = "IssuerFound"
+)
+
+const (
+	// CertificateRequestConditionNeedsManualReview is the type of the
+	// condition that is set when the Sign function returns a
+	// signer.ManualInterventionError. While the condition is True, the request
+	// is not retried until it is resumed using the
+	// CertificateRequestResumeAnnotation.
+	CertificateRequestConditionNeedsManualReview = "NeedsManualReview"
+
+	CertificateRequestConditionReasonResumed = "Resumed"
+)
+
+const (
+	// CertificateSigningRequestConditionAfterSignPending is the type of the
+	// condition that is set on a Kubernetes CertificateSigningRequest when the
+	// AfterSign function failed after the certificate was stored in its
+	// status. While the condition is True, AfterSign is retried using the
+	// stored certificate. It is set to False once AfterSign succeeds or once
+	// it is no longer retried (see the AfterSignFailed reason).
+	CertificateSigningRequestConditionAfterSignPending = "AfterSignPending"
+
+	CertificateSigningRequestConditionReasonAfterSignError = "AfterSignError"
+
+	CertificateSigningRequestConditionReasonAfterSignFailed = "AfterSignFailed"
+
+	CertificateSigningRequestConditionReasonAfterSignSucceeded = "AfterSignSucceeded"
+)
+
+const (
+	// CertificateRequestConditionUnknownIssuerKind is the type of the
+	// condition that is set when the issuerRef of a CertificateRequest has the
+	// group of one of the registered issuer types, but a kind that doesn't
+	// match any of them (eg. because of a typo). The request is not signed.
+	CertificateRequestConditionUnknownIssuerKind = "UnknownIssuerKind"
+
+	CertificateRequestConditionReasonUnknownIssuerKind = "UnknownIssuerKind"
+)
+
+const (
+	// CertificateRequestRetryDeadlineAnnotation is set on a CertificateRequest
+	// by the CertificateRequest controller when signing fails with a retryable
+	// error. Its value is the time (in RFC3339 format) after which the request
+	// is no longer retried and is failed permanently, ie. the creation time of
+	// the request plus the MaxRetryDuration. When a re-issuance is forced using
+	// the ForceReissueAnnotation of the controller, a new deadline is recorded,
+	// starting at the time the re-issuance was handled.
+	CertificateRequestRetryDeadlineAnnotation = "issuer-lib.cert-manager.io/retry-deadline"
+
+	// CertificateRequestMaxWaitAnnotation can be set on a CertificateRequest by
+	// the client to specify how long it is willing to wait for the certificate
+	// (eg. "5m"). The value is a Go duration, counted from the creation time of
+	// the request. The resulting deadline is used as the deadline of the Sign
+	// context, and the request is failed permanently once it has passed, also
+	// if it is still waiting for its issuer.
+	CertificateRequestMaxWaitAnnotation = "issuer-lib.cert-manager.io/max-wait"
+
+	// CertificateRequestResumeAnnotation can be set on a CertificateRequest
+	// that has the NeedsManualReview condition to resume it, the Sign function
+	// is then called again. Every new value of the annotation resumes the
+	// request once; the handled value is recorded in the
+	// CertificateRequestResumeObservedAnnotation.
+	CertificateRequestResumeAnnotation = "issuer-lib.cert-manager.io/resume"
+
+	// CertificateRequestResumeObservedAnnotation is set by the
+	// CertificateRequest controller to the last value of the
+	// CertificateRequestResumeAnnotation that has been handled.
+	CertificateRequestResumeObservedAnnotation = "issuer-lib.cert-manager.io/resume-observed"
+)
+
+const (
+	// IssuerBootstrappedAnnotation is set on an issuer by the issuer controller
+	// once the OnIssuerBootstrap function has succeeded for that issuer. Its
+	// value is the time (in RFC3339 format) at which the bootstrap succeeded.
+	// Removing the annotation runs the OnIssuerBootstrap function again.
+	IssuerBootstrappedAnnotation = "issuer-lib.cert-manager.io/bootstrapped"
+)
+
+const (
+	// ArtifactAnnotationPrefix is the prefix of the annotations that contain the
+	// AdditionalArtifacts returned by the signer (eg. a delegated credential).
+	// Each artifact is stored base64 encoded in the annotation
+	// "<ArtifactAnnotationPrefix><artifact name>" on the CertificateRequest or
+	// Kubernetes CSR resource.
+	ArtifactAnnotationPrefix = "artifacts.issuer-lib.cert-manager.io/"
+
+	// SignedByAnnotation is the annotation that signer.FallbackSign adds to the
+	// CertificateRequest or Kubernetes CSR resource to record whether the
+	// certificate was signed by the "primary" or the "secondary" Sign function.
+	SignedByAnnotation = "issuer-lib.cert-manager.io/signed-by"
+
+	// RenewalTimeAnnotation is the annotation that contains the RenewalTime
+	// recommended by the CA in the PEMBundle returned by the signer, formatted
+	// as an RFC3339 timestamp. This is mostly useful for Kubernetes CSRs, which
+	// are not renewed by cert-manager.
+	RenewalTimeAnnotation = "issuer-lib.cert-manager.io/renewal-time"
+)
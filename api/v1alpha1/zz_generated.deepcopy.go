@@ -25,6 +25,22 @@ import (
 	"github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IssuerCheckError) DeepCopyInto(out *IssuerCheckError) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerCheckError.
+func (in *IssuerCheckError) DeepCopy() *IssuerCheckError {
+	if in == nil {
+		return nil
+	}
+	out := new(IssuerCheckError)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IssuerStatus) DeepCopyInto(out *IssuerStatus) {
 	*out = *in
@@ -35,6 +51,15 @@ func (in *IssuerStatus) DeepCopyInto(out *IssuerStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastCheckError != nil {
+		in, out := &in.LastCheckError, &out.LastCheckError
+		*out = new(IssuerCheckError)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerStatus.
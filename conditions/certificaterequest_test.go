@@ -219,3 +219,31 @@ func TestSetCertificateRequestStatusCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestSetCertificateRequestStatusConditionMissingLastTransitionTime(t *testing.T) {
+	fakeTime := randomTime()
+	fakeTimeObj := metav1.NewTime(fakeTime)
+	fakeClock := clocktesting.NewFakeClock(fakeTime)
+
+	existingConditions := []cmapi.CertificateRequestCondition{
+		{
+			Type:   cmapi.CertificateRequestConditionReady,
+			Status: cmmeta.ConditionTrue,
+		},
+	}
+
+	var patchConditions []cmapi.CertificateRequestCondition
+	cond, _ := SetCertificateRequestStatusCondition(
+		fakeClock,
+		existingConditions,
+		&patchConditions,
+		cmapi.CertificateRequestConditionReady,
+		cmmeta.ConditionTrue,
+		"NewReason",
+		"NewMessage",
+	)
+
+	// The status did not change, but the existing condition has no
+	// LastTransitionTime, so it is set to the current time.
+	require.Equal(t, &fakeTimeObj, cond.LastTransitionTime)
+}
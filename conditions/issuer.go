@@ -52,8 +52,9 @@ func SetIssuerStatusCondition(
 		}
 
 		// If this update doesn't contain a state transition, we don't update
-		// the conditions LastTransitionTime to Now()
-		if cond.Status == status {
+		// the conditions LastTransitionTime to Now(), unless the existing
+		// condition is missing its LastTransitionTime.
+		if cond.Status == status && cond.LastTransitionTime != nil {
 			newCondition.LastTransitionTime = cond.LastTransitionTime
 		}
 	}
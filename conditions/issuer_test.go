@@ -208,3 +208,32 @@ func TestSetIssuerStatusCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestSetIssuerStatusConditionMissingLastTransitionTime(t *testing.T) {
+	fakeTime := randomTime()
+	fakeTimeObj := metav1.NewTime(fakeTime)
+	fakeClock := clocktesting.NewFakeClock(fakeTime)
+
+	existingConditions := []cmapi.IssuerCondition{
+		{
+			Type:   cmapi.IssuerConditionReady,
+			Status: cmmeta.ConditionTrue,
+		},
+	}
+
+	var patchConditions []cmapi.IssuerCondition
+	cond, _ := SetIssuerStatusCondition(
+		fakeClock,
+		existingConditions,
+		&patchConditions,
+		1,
+		cmapi.IssuerConditionReady,
+		cmmeta.ConditionTrue,
+		"NewReason",
+		"NewMessage",
+	)
+
+	// The status did not change, but the existing condition has no
+	// LastTransitionTime, so it is set to the current time.
+	require.Equal(t, &fakeTimeObj, cond.LastTransitionTime)
+}
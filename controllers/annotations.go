@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// reservedAnnotationPrefix is the prefix of the annotations that are used by
+// cert-manager itself (eg. to keep track of renewals). Annotations returned by
+// the signer with this prefix are ignored.
+const reservedAnnotationPrefix = "cert-manager.io/"
+
+// applySignerAnnotations adds the annotations returned by the signer to obj
+// using a merge patch. Existing annotations are never removed and annotations
+// with a reserved prefix are not applied; their keys are returned instead.
+// No request is made if all annotations are already present.
+func applySignerAnnotations(
+	ctx context.Context,
+	cl client.Client,
+	obj client.Object,
+	annotations map[string]string,
+) (ignored []string, err error) {
+	toApply := map[string]string{}
+	for key, value := range annotations {
+		if strings.HasPrefix(key, reservedAnnotationPrefix) {
+			ignored = append(ignored, key)
+			continue
+		}
+
+		if current, ok := obj.GetAnnotations()[key]; ok && current == value {
+			continue
+		}
+
+		toApply[key] = value
+	}
+	sort.Strings(ignored)
+
+	if len(toApply) == 0 {
+		return ignored, nil
+	}
+
+	original := obj.DeepCopyObject().(client.Object)
+
+	newAnnotations := make(map[string]string, len(obj.GetAnnotations())+len(toApply))
+	for key, value := range obj.GetAnnotations() {
+		newAnnotations[key] = value
+	}
+	for key, value := range toApply {
+		newAnnotations[key] = value
+	}
+	obj.SetAnnotations(newAnnotations)
+
+	return ignored, cl.Patch(ctx, obj, client.MergeFrom(original))
+}
+
+// signerAnnotations returns the annotations that have to be added to the
+// request for the bundle returned by the signer: the Annotations of the bundle,
+// an annotation with the base64 encoded value of each of the
+// AdditionalArtifacts and the RenewalTimeAnnotation if the bundle has a
+// RenewalTime. The names of the artifacts that don't result in a valid
+// annotation name are returned instead.
+func signerAnnotations(bundle signer.PEMBundle) (annotations map[string]string, invalidArtifacts []string) {
+	if len(bundle.AdditionalArtifacts) == 0 && bundle.RenewalTime.IsZero() {
+		return bundle.Annotations, nil
+	}
+
+	annotations = make(map[string]string, len(bundle.Annotations)+len(bundle.AdditionalArtifacts)+1)
+	for key, value := range bundle.Annotations {
+		annotations[key] = value
+	}
+	if !bundle.RenewalTime.IsZero() {
+		annotations[v1alpha1.RenewalTimeAnnotation] = bundle.RenewalTime.UTC().Format(time.RFC3339)
+	}
+	for name, artifact := range bundle.AdditionalArtifacts {
+		key := v1alpha1.ArtifactAnnotationPrefix + name
+		if len(validation.IsQualifiedName(key)) > 0 {
+			invalidArtifacts = append(invalidArtifacts, name)
+			continue
+		}
+
+		annotations[key] = base64.StdEncoding.EncodeToString(artifact)
+	}
+	sort.Strings(invalidArtifacts)
+
+	return annotations, invalidArtifacts
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+func TestApplySignerAnnotations(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name                string
+		existing            map[string]string
+		annotations         map[string]string
+		expectedIgnored     []string
+		expectedAnnotations map[string]string
+	}
+
+	tests := []testCase{
+		{
+			name:     "no-annotations",
+			existing: map[string]string{"example.com/existing": "value"},
+			expectedAnnotations: map[string]string{
+				"example.com/existing": "value",
+			},
+		},
+		{
+			name:     "add-and-overwrite-annotations",
+			existing: map[string]string{"example.com/existing": "value", "example.com/overwritten": "old"},
+			annotations: map[string]string{
+				"example.com/overwritten": "new",
+				"example.com/added":       "value",
+			},
+			expectedAnnotations: map[string]string{
+				"example.com/existing":    "value",
+				"example.com/overwritten": "new",
+				"example.com/added":       "value",
+			},
+		},
+		{
+			name:     "ignore-reserved-annotations",
+			existing: map[string]string{"cert-manager.io/certificate-revision": "1"},
+			annotations: map[string]string{
+				"cert-manager.io/certificate-revision":    "2",
+				"cert-manager.io/private-key-secret-name": "secret",
+				"example.com/added":                       "value",
+			},
+			expectedIgnored: []string{
+				"cert-manager.io/certificate-revision",
+				"cert-manager.io/private-key-secret-name",
+			},
+			expectedAnnotations: map[string]string{
+				"cert-manager.io/certificate-revision": "1",
+				"example.com/added":                    "value",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, setupCertificateRequestReconcilerScheme(scheme))
+
+			cr := cmgen.CertificateRequest("cr1", cmgen.SetCertificateRequestNamespace("ns1"))
+			cr.Annotations = tc.existing
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(cr).
+				Build()
+
+			ignored, err := applySignerAnnotations(context.TODO(), fakeClient, cr, tc.annotations)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedIgnored, ignored)
+
+			require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(cr), cr))
+			assert.Equal(t, tc.expectedAnnotations, cr.Annotations)
+		})
+	}
+}
+
+func TestSignerAnnotations(t *testing.T) {
+	t.Parallel()
+
+	annotations, invalidArtifacts := signerAnnotations(signer.PEMBundle{
+		Annotations: map[string]string{
+			"example.com/added": "value",
+		},
+		AdditionalArtifacts: map[string][]byte{
+			"delegated-credential": []byte("credential"),
+			"empty":                nil,
+			"invalid/name":         []byte("ignored"),
+			"-invalid":             []byte("ignored"),
+		},
+	})
+
+	assert.Equal(t, map[string]string{
+		"example.com/added": "value",
+		v1alpha1.ArtifactAnnotationPrefix + "delegated-credential": "Y3JlZGVudGlhbA==",
+		v1alpha1.ArtifactAnnotationPrefix + "empty":                "",
+	}, annotations)
+	assert.Equal(t, []string{"-invalid", "invalid/name"}, invalidArtifacts)
+}
+
+func TestSignerAnnotationsRenewalTime(t *testing.T) {
+	t.Parallel()
+
+	renewalTime := time.Date(2030, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	annotations, invalidArtifacts := signerAnnotations(signer.PEMBundle{
+		Annotations: map[string]string{
+			"example.com/added": "value",
+		},
+		RenewalTime: renewalTime,
+	})
+
+	assert.Equal(t, map[string]string{
+		"example.com/added":            "value",
+		v1alpha1.RenewalTimeAnnotation: "2030-01-02T02:04:05Z",
+	}, annotations)
+	assert.Empty(t, invalidArtifacts)
+}
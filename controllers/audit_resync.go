@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cmutil "github.com/cert-manager/cert-manager/pkg/api/util"
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"github.com/cert-manager/cert-manager/pkg/controller/certificatesigningrequests/util"
+	"github.com/go-logr/logr"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// auditResyncer periodically lists the objects that are managed by a
+// controller and enqueues them using a channel source, so that every object is
+// reconciled at least once per interval, independent of watch events and of
+// the informer resync. A log line is written for every cycle, so that the
+// periodic re-evaluation can be proven for audit purposes.
+type auditResyncer struct {
+	controllerName string
+	interval       time.Duration
+	list           func(ctx context.Context) ([]client.Object, error)
+	logger         logr.Logger
+
+	events chan event.GenericEvent
+}
+
+var _ manager.Runnable = &auditResyncer{}
+var _ manager.LeaderElectionRunnable = &auditResyncer{}
+
+// setupAuditResync adds an auditResyncer for the controller to the manager and
+// returns the builder that watches the objects that it enqueues.
+func setupAuditResync(
+	mgr ctrl.Manager,
+	build *builder.Builder,
+	logger logr.Logger,
+	controllerName string,
+	interval time.Duration,
+	list func(ctx context.Context) ([]client.Object, error),
+) (*builder.Builder, error) {
+	resyncer := &auditResyncer{
+		controllerName: controllerName,
+		interval:       interval,
+		list:           list,
+		logger:         logger.WithName("audit-resync").WithValues("controller", controllerName),
+		events:         make(chan event.GenericEvent),
+	}
+
+	if err := mgr.Add(resyncer); err != nil {
+		return nil, fmt.Errorf("failed to add the audit resync of the %s controller: %w", controllerName, err)
+	}
+
+	return build.WatchesRawSource(&source.Channel{Source: resyncer.events}, &handler.EnqueueRequestForObject{}), nil
+}
+
+func (a *auditResyncer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := a.resync(ctx); err != nil {
+				a.logger.Error(err, "Audit resync failed, will retry in the next cycle.")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns true, because the controllers only process
+// their workqueues when they are the leader.
+func (a *auditResyncer) NeedLeaderElection() bool {
+	return true
+}
+
+// resync enqueues all the objects returned by the list function and returns
+// the number of enqueued objects.
+func (a *auditResyncer) resync(ctx context.Context) (int, error) {
+	objects, err := a.list(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, object := range objects {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case a.events <- event.GenericEvent{Object: object}:
+		}
+	}
+
+	a.logger.Info("Audit resync: enqueued all the managed objects for reconciliation.", "objects", len(objects), "interval", a.interval)
+	return len(objects), nil
+}
+
+// auditResyncObjects returns all the issuers of the type of the reconciler.
+func (r *IssuerReconciler) auditResyncObjects(ctx context.Context) ([]client.Object, error) {
+	gvk := r.ForObject.GetObjectKind().GroupVersionKind()
+	listObj, err := r.Client.Scheme().New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create list for %s: %w", gvk.Kind, err)
+	}
+	list, ok := listObj.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a list type", listObj)
+	}
+
+	if err := r.Client.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to list %s issuers: %w", gvk.Kind, err)
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s issuers: %w", gvk.Kind, err)
+	}
+
+	objects := make([]client.Object, 0, len(items))
+	for _, item := range items {
+		object, ok := item.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("%T is not a client.Object", item)
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+// auditResyncObjects returns the outstanding CertificateRequests (not Ready,
+// Failed or Denied) that reference one of the issuer types of the reconciler.
+func (r *CertificateRequestReconciler) auditResyncObjects(ctx context.Context) ([]client.Object, error) {
+	var list cmapi.CertificateRequestList
+	if err := r.Client.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list CertificateRequests: %w", err)
+	}
+
+	var objects []client.Object
+	for i := range list.Items {
+		cr := &list.Items[i]
+		if issuerObject, _ := r.matchIssuerType(cr); issuerObject == nil {
+			continue
+		}
+
+		if ready := cmutil.GetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady); ready != nil &&
+			(ready.Status == cmmeta.ConditionTrue ||
+				ready.Reason == cmapi.CertificateRequestReasonFailed ||
+				ready.Reason == cmapi.CertificateRequestReasonDenied) {
+			continue
+		}
+
+		objects = append(objects, cr)
+	}
+	return objects, nil
+}
+
+// auditResyncObjects returns the outstanding Kubernetes CSRs (without a
+// certificate and not Failed or Denied) that are addressed to one of the
+// issuer types or virtual issuers of the reconciler.
+func (r *CertificateSigningRequestReconciler) auditResyncObjects(ctx context.Context) ([]client.Object, error) {
+	var list certificatesv1.CertificateSigningRequestList
+	if err := r.Client.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list CertificateSigningRequests: %w", err)
+	}
+
+	var objects []client.Object
+	for i := range list.Items {
+		csr := &list.Items[i]
+		if _, ok := r.issuerKey(csr); !ok {
+			continue
+		}
+
+		if len(csr.Status.Certificate) > 0 ||
+			util.CertificateSigningRequestIsFailed(csr) ||
+			util.CertificateSigningRequestIsDenied(csr) {
+			continue
+		}
+
+		objects = append(objects, csr)
+	}
+	return objects, nil
+}
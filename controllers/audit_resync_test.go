@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/testutil"
+)
+
+// TestAuditResyncer verifies that all the outstanding CertificateRequests are
+// enqueued within every interval, and that the CertificateRequests that are
+// done or that belong to another issuer are not.
+func TestAuditResyncer(t *testing.T) {
+	t.Parallel()
+
+	withCondition := func(reason string, status cmmeta.ConditionStatus) cmgen.CertificateRequestModifier {
+		return cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionReady,
+			Status: status,
+			Reason: reason,
+		})
+	}
+	ourIssuer := cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+		Group: api.SchemeGroupVersion.Group,
+		Kind:  "SimpleIssuer",
+		Name:  "issuer-1",
+	})
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, setupCertificateRequestReconcilerScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			cmgen.CertificateRequest("new", cmgen.SetCertificateRequestNamespace("ns1"), ourIssuer),
+			cmgen.CertificateRequest("pending", cmgen.SetCertificateRequestNamespace("ns1"), ourIssuer, withCondition(cmapi.CertificateRequestReasonPending, cmmeta.ConditionFalse)),
+			cmgen.CertificateRequest("ready", cmgen.SetCertificateRequestNamespace("ns1"), ourIssuer, withCondition(cmapi.CertificateRequestReasonIssued, cmmeta.ConditionTrue)),
+			cmgen.CertificateRequest("failed", cmgen.SetCertificateRequestNamespace("ns1"), ourIssuer, withCondition(cmapi.CertificateRequestReasonFailed, cmmeta.ConditionFalse)),
+			cmgen.CertificateRequest("denied", cmgen.SetCertificateRequestNamespace("ns1"), ourIssuer, withCondition(cmapi.CertificateRequestReasonDenied, cmmeta.ConditionFalse)),
+			cmgen.CertificateRequest("foreign", cmgen.SetCertificateRequestNamespace("ns1"), cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+				Group: "other.example.com",
+				Kind:  "OtherIssuer",
+				Name:  "issuer-1",
+			})),
+		).
+		Build()
+
+	reconciler := &CertificateRequestReconciler{
+		IssuerTypes: []v1alpha1.Issuer{&api.SimpleIssuer{}},
+		Client:      fakeClient,
+	}
+	require.NoError(t, reconciler.setIssuersGroupVersionKind(scheme))
+
+	interval := 50 * time.Millisecond
+	resyncer := &auditResyncer{
+		controllerName: "certificaterequest",
+		interval:       interval,
+		list:           reconciler.auditResyncObjects,
+		logger:         logr.Discard(),
+		events:         make(chan event.GenericEvent),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, resyncer.Start(ctx))
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// Collect the objects of two cycles, each cycle must be complete within
+	// the interval (plus some slack for slow test machines).
+	for cycle := 0; cycle < 2; cycle++ {
+		var visited []string
+		timeout := time.After(interval + 5*time.Second)
+		for len(visited) < 2 {
+			select {
+			case e := <-resyncer.events:
+				visited = append(visited, client.ObjectKeyFromObject(e.Object).String())
+			case <-timeout:
+				t.Fatalf("cycle %d: timed out waiting for the objects to be enqueued, got: %v", cycle, visited)
+			}
+		}
+
+		sort.Strings(visited)
+		assert.Equal(t, []string{"ns1/new", "ns1/pending"}, visited, "cycle %d", cycle)
+	}
+}
+
+// TestIssuerReconcilerAuditResyncObjects verifies that the audit resync of the
+// issuer controller lists all the issuers of its type.
+func TestIssuerReconcilerAuditResyncObjects(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			testutil.SimpleIssuer("issuer-1", testutil.SetSimpleIssuerNamespace("ns1")),
+			testutil.SimpleIssuer("issuer-2", testutil.SetSimpleIssuerNamespace("ns2")),
+			testutil.SimpleClusterIssuer("cluster-issuer-1"),
+		).
+		Build()
+
+	forObject := &api.SimpleIssuer{}
+	forObject.SetGroupVersionKind(api.SchemeGroupVersion.WithKind("SimpleIssuer"))
+	reconciler := &IssuerReconciler{
+		ForObject: forObject,
+		Client:    fakeClient,
+	}
+
+	objects, err := reconciler.auditResyncObjects(context.TODO())
+	require.NoError(t, err)
+
+	var keys []string
+	for _, object := range objects {
+		keys = append(keys, client.ObjectKeyFromObject(object).String())
+	}
+	sort.Strings(keys)
+	assert.Equal(t, []string{"ns1/issuer-1", "ns2/issuer-2"}, keys)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cert-manager/cert-manager/pkg/util/pki"
+
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// verifyChainCompleteness verifies that a chain can be built from the leaf
+// certificate up to a self-signed root certificate using only the certificates
+// in the ChainPEM and CAPEM fields of the bundle.
+func verifyChainCompleteness(bundle signer.PEMBundle, now time.Time) error {
+	chain, err := pki.DecodeX509CertificateChainBytes(bundle.ChainPEM)
+	if err != nil {
+		return fmt.Errorf("incomplete certificate chain: failed to decode chain: %w", err)
+	}
+
+	certificates := chain
+	if len(bytes.TrimSpace(bundle.CAPEM)) > 0 {
+		ca, err := pki.DecodeX509CertificateChainBytes(bundle.CAPEM)
+		if err != nil {
+			return fmt.Errorf("incomplete certificate chain: failed to decode CA: %w", err)
+		}
+		certificates = append(certificates, ca...)
+	}
+
+	leaf := chain[0]
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates {
+		if isSelfSigned(certificate) {
+			roots.AddCert(certificate)
+		} else {
+			intermediates.AddCert(certificate)
+		}
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("incomplete certificate chain: %w", err)
+	}
+
+	return nil
+}
+
+func isSelfSigned(certificate *x509.Certificate) bool {
+	return bytes.Equal(certificate.RawIssuer, certificate.RawSubject) &&
+		certificate.CheckSignature(certificate.SignatureAlgorithm, certificate.RawTBSCertificate, certificate.Signature) == nil
+}
+
+// oidExtensionSCTList is the OID of the X.509 extension that contains the
+// embedded signed certificate timestamps (RFC 6962, section 3.3).
+var oidExtensionSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// verifySCTs verifies that the leaf certificate of the bundle contains at least
+// minimum embedded signed certificate timestamps. If minimum is not positive, at
+// least one SCT is required.
+func verifySCTs(bundle signer.PEMBundle, minimum int) error {
+	if minimum <= 0 {
+		minimum = 1
+	}
+
+	leaf, err := pki.DecodeX509CertificateBytes(bundle.ChainPEM)
+	if err != nil {
+		return fmt.Errorf("failed to decode the leaf certificate: %w", err)
+	}
+
+	count := 0
+	for _, extension := range leaf.Extensions {
+		if !extension.Id.Equal(oidExtensionSCTList) {
+			continue
+		}
+
+		count, err = countSCTs(extension.Value)
+		if err != nil {
+			return fmt.Errorf("invalid signed certificate timestamp list: %w", err)
+		}
+		break
+	}
+
+	if count < minimum {
+		return fmt.Errorf("the certificate contains %d embedded signed certificate timestamps, at least %d are required", count, minimum)
+	}
+
+	return nil
+}
+
+// countSCTs returns the number of SCTs in the value of the SCT list extension.
+// The value is an OCTET STRING that contains a TLS-encoded
+// SignedCertificateTimestampList: a 2-byte length, followed by the SCTs, which
+// are each prefixed by a 2-byte length.
+func countSCTs(value []byte) (int, error) {
+	var list []byte
+	if rest, err := asn1.Unmarshal(value, &list); err != nil {
+		return 0, err
+	} else if len(rest) > 0 {
+		return 0, fmt.Errorf("trailing data after the OCTET STRING")
+	}
+
+	if len(list) < 2 || int(binary.BigEndian.Uint16(list)) != len(list)-2 {
+		return 0, fmt.Errorf("invalid list length")
+	}
+	list = list[2:]
+
+	count := 0
+	for len(list) > 0 {
+		if len(list) < 2 {
+			return 0, fmt.Errorf("truncated SCT length")
+		}
+		sctLength := int(binary.BigEndian.Uint16(list))
+		if sctLength == 0 || len(list)-2 < sctLength {
+			return 0, fmt.Errorf("invalid SCT length")
+		}
+		list = list[2+sctLength:]
+		count++
+	}
+
+	return count, nil
+}
+
+// verifyExactSANs verifies that the leaf certificate of the bundle contains
+// exactly the subject alternative names that were requested, so that a CA can't
+// silently add or drop SANs.
+func verifyExactSANs(bundle signer.PEMBundle, cr signer.CertificateRequestObject) error {
+	template, _, _, err := cr.GetRequest()
+	if err != nil {
+		return err
+	}
+
+	leaf, err := pki.DecodeX509CertificateBytes(bundle.ChainPEM)
+	if err != nil {
+		return fmt.Errorf("failed to decode the leaf certificate: %w", err)
+	}
+
+	requested := subjectAltNames(template)
+	issued := subjectAltNames(leaf)
+
+	var added, missing []string
+	for san := range issued {
+		if !requested[san] {
+			added = append(added, san)
+		}
+	}
+	for san := range requested {
+		if !issued[san] {
+			missing = append(missing, san)
+		}
+	}
+
+	if len(added) == 0 && len(missing) == 0 {
+		return nil
+	}
+
+	sort.Strings(added)
+	sort.Strings(missing)
+
+	var diff []string
+	if len(added) > 0 {
+		diff = append(diff, fmt.Sprintf("unexpected %s", strings.Join(added, ", ")))
+	}
+	if len(missing) > 0 {
+		diff = append(diff, fmt.Sprintf("missing %s", strings.Join(missing, ", ")))
+	}
+
+	return fmt.Errorf("the subject alternative names of the certificate don't match the request: %s", strings.Join(diff, "; "))
+}
+
+// subjectAltNames returns the set of subject alternative names of the
+// certificate, each prefixed with its type.
+func subjectAltNames(certificate *x509.Certificate) map[string]bool {
+	sans := map[string]bool{}
+	for _, dnsName := range certificate.DNSNames {
+		sans["DNS:"+dnsName] = true
+	}
+	for _, ip := range certificate.IPAddresses {
+		sans["IP:"+ip.String()] = true
+	}
+	for _, uri := range certificate.URIs {
+		sans["URI:"+uri.String()] = true
+	}
+	for _, email := range certificate.EmailAddresses {
+		sans["email:"+email] = true
+	}
+	return sans
+}
@@ -0,0 +1,358 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cert-manager/cert-manager/pkg/util/pki"
+	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+)
+
+type testCertificateChain struct {
+	rootPEM         []byte
+	intermediatePEM []byte
+	leafPEM         []byte
+}
+
+// newTestCertificateChain generates a root CA, an intermediate CA signed by
+// the root and a leaf certificate signed by the intermediate.
+func newTestCertificateChain(t *testing.T, now time.Time) testCertificateChain {
+	t.Helper()
+
+	newCertificate := func(serial int64, commonName string, isCA bool, parent *x509.Certificate, parentKey interface{}) ([]byte, *x509.Certificate, interface{}) {
+		key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+		require.NoError(t, err)
+
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: commonName},
+			NotBefore:             now.Add(-time.Hour),
+			NotAfter:              now.Add(time.Hour),
+			BasicConstraintsValid: true,
+			IsCA:                  isCA,
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		}
+
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+
+		certPEM, cert, err := pki.SignCertificate(template, parent, key.Public(), parentKey)
+		require.NoError(t, err)
+
+		return certPEM, cert, key
+	}
+
+	rootPEM, root, rootKey := newCertificate(1, "root", true, nil, nil)
+	intermediatePEM, intermediate, intermediateKey := newCertificate(2, "intermediate", true, root, rootKey)
+	leafPEM, _, _ := newCertificate(3, "leaf", false, intermediate, intermediateKey)
+
+	return testCertificateChain{
+		rootPEM:         rootPEM,
+		intermediatePEM: intermediatePEM,
+		leafPEM:         leafPEM,
+	}
+}
+
+func TestVerifyChainCompleteness(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	chain := newTestCertificateChain(t, now)
+
+	join := func(pems ...[]byte) []byte {
+		var joined []byte
+		for _, pem := range pems {
+			joined = append(joined, pem...)
+		}
+		return joined
+	}
+
+	type testCase struct {
+		name          string
+		bundle        signer.PEMBundle
+		validateError *errormatch.Matcher
+	}
+
+	tests := []testCase{
+		{
+			name: "complete-chain-with-root-in-ca",
+			bundle: signer.PEMBundle{
+				ChainPEM: join(chain.leafPEM, chain.intermediatePEM),
+				CAPEM:    chain.rootPEM,
+			},
+		},
+		{
+			name: "complete-chain-with-root-in-chain",
+			bundle: signer.PEMBundle{
+				ChainPEM: join(chain.leafPEM, chain.intermediatePEM, chain.rootPEM),
+			},
+		},
+		{
+			name: "self-signed-leaf",
+			bundle: signer.PEMBundle{
+				ChainPEM: chain.rootPEM,
+			},
+		},
+		{
+			name: "missing-intermediate",
+			bundle: signer.PEMBundle{
+				ChainPEM: chain.leafPEM,
+				CAPEM:    chain.rootPEM,
+			},
+			validateError: errormatch.ErrorContains("incomplete certificate chain: x509: certificate signed by unknown authority"),
+		},
+		{
+			name: "missing-root",
+			bundle: signer.PEMBundle{
+				ChainPEM: join(chain.leafPEM, chain.intermediatePEM),
+			},
+			validateError: errormatch.ErrorContains("incomplete certificate chain: x509: certificate signed by unknown authority"),
+		},
+		{
+			name: "invalid-chain",
+			bundle: signer.PEMBundle{
+				ChainPEM: []byte("invalid"),
+			},
+			validateError: errormatch.ErrorContains("incomplete certificate chain: failed to decode chain: error decoding certificate PEM block"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := verifyChainCompleteness(tc.bundle, now)
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+		})
+	}
+}
+
+// newTestSCTCertificate generates a self-signed certificate with the given
+// value as SCT list extension. If the value is nil, the extension is omitted.
+func newTestSCTCertificate(t *testing.T, sctListValue []byte) []byte {
+	t.Helper()
+
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if sctListValue != nil {
+		template.ExtraExtensions = []pkix.Extension{{Id: oidExtensionSCTList, Value: sctListValue}}
+	}
+
+	certPEM, _, err := pki.SignCertificate(template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	return certPEM
+}
+
+// encodeSCTList encodes the SCTs as the value of the SCT list extension.
+func encodeSCTList(t *testing.T, scts ...[]byte) []byte {
+	t.Helper()
+
+	var list []byte
+	for _, sct := range scts {
+		list = binary.BigEndian.AppendUint16(list, uint16(len(sct)))
+		list = append(list, sct...)
+	}
+	list = append(binary.BigEndian.AppendUint16(nil, uint16(len(list))), list...)
+
+	value, err := asn1.Marshal(list)
+	require.NoError(t, err)
+
+	return value
+}
+
+func TestVerifySCTs(t *testing.T) {
+	t.Parallel()
+
+	sct := []byte("fake-signed-certificate-timestamp")
+
+	type testCase struct {
+		name          string
+		bundle        signer.PEMBundle
+		minimum       int
+		validateError *errormatch.Matcher
+	}
+
+	tests := []testCase{
+		{
+			name: "without-scts",
+			bundle: signer.PEMBundle{
+				ChainPEM: newTestSCTCertificate(t, nil),
+			},
+			validateError: errormatch.ErrorContains("the certificate contains 0 embedded signed certificate timestamps, at least 1 are required"),
+		},
+		{
+			name: "empty-sct-list",
+			bundle: signer.PEMBundle{
+				ChainPEM: newTestSCTCertificate(t, encodeSCTList(t)),
+			},
+			validateError: errormatch.ErrorContains("the certificate contains 0 embedded signed certificate timestamps, at least 1 are required"),
+		},
+		{
+			name: "with-sct",
+			bundle: signer.PEMBundle{
+				ChainPEM: newTestSCTCertificate(t, encodeSCTList(t, sct)),
+			},
+		},
+		{
+			name: "with-enough-scts",
+			bundle: signer.PEMBundle{
+				ChainPEM: newTestSCTCertificate(t, encodeSCTList(t, sct, sct)),
+			},
+			minimum: 2,
+		},
+		{
+			name: "not-enough-scts",
+			bundle: signer.PEMBundle{
+				ChainPEM: newTestSCTCertificate(t, encodeSCTList(t, sct, sct)),
+			},
+			minimum:       3,
+			validateError: errormatch.ErrorContains("the certificate contains 2 embedded signed certificate timestamps, at least 3 are required"),
+		},
+		{
+			name: "invalid-sct-list",
+			bundle: signer.PEMBundle{
+				ChainPEM: newTestSCTCertificate(t, []byte{0x04, 0x03, 0x00, 0x05, 0x00}),
+			},
+			validateError: errormatch.ErrorContains("invalid signed certificate timestamp list: invalid list length"),
+		},
+		{
+			name: "invalid-certificate",
+			bundle: signer.PEMBundle{
+				ChainPEM: []byte("invalid"),
+			},
+			validateError: errormatch.ErrorContains("failed to decode the leaf certificate"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := verifySCTs(tc.bundle, tc.minimum)
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+		})
+	}
+}
+
+// newTestSANCertificate generates a self-signed certificate with the given DNS
+// names as subject alternative names.
+func newTestSANCertificate(t *testing.T, dnsNames ...string) []byte {
+	t.Helper()
+
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     dnsNames,
+	}
+
+	certPEM, _, err := pki.SignCertificate(template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	return certPEM
+}
+
+func TestVerifyExactSANs(t *testing.T) {
+	t.Parallel()
+
+	csrPEM, _, err := cmgen.CSR(x509.ECDSA, cmgen.SetCSRDNSNames("a.example.com", "b.example.com"))
+	require.NoError(t, err)
+
+	cr := signer.CertificateRequestObjectFromCertificateRequest(
+		cmgen.CertificateRequest("cr1", cmgen.SetCertificateRequestCSR(csrPEM)),
+	)
+
+	type testCase struct {
+		name          string
+		bundle        signer.PEMBundle
+		validateError *errormatch.Matcher
+	}
+
+	tests := []testCase{
+		{
+			name: "exact-sans",
+			bundle: signer.PEMBundle{
+				ChainPEM: newTestSANCertificate(t, "b.example.com", "a.example.com"),
+			},
+		},
+		{
+			name: "extra-san",
+			bundle: signer.PEMBundle{
+				ChainPEM: newTestSANCertificate(t, "a.example.com", "b.example.com", "extra.example.com"),
+			},
+			validateError: errormatch.ErrorContains("the subject alternative names of the certificate don't match the request: unexpected DNS:extra.example.com"),
+		},
+		{
+			name: "missing-san",
+			bundle: signer.PEMBundle{
+				ChainPEM: newTestSANCertificate(t, "a.example.com"),
+			},
+			validateError: errormatch.ErrorContains("the subject alternative names of the certificate don't match the request: missing DNS:b.example.com"),
+		},
+		{
+			name: "extra-and-missing-san",
+			bundle: signer.PEMBundle{
+				ChainPEM: newTestSANCertificate(t, "a.example.com", "c.example.com"),
+			},
+			validateError: errormatch.ErrorContains("the subject alternative names of the certificate don't match the request: unexpected DNS:c.example.com; missing DNS:b.example.com"),
+		},
+		{
+			name: "invalid-certificate",
+			bundle: signer.PEMBundle{
+				ChainPEM: []byte("invalid"),
+			},
+			validateError: errormatch.ErrorContains("failed to decode the leaf certificate"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := verifyExactSANs(tc.bundle, cr)
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+		})
+	}
+}
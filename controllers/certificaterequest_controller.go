@@ -20,6 +20,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	cmutil "github.com/cert-manager/cert-manager/pkg/api/util"
@@ -49,6 +51,31 @@ import (
 	"github.com/cert-manager/issuer-lib/internal/ssaclient"
 )
 
+// reasonRequestExpired is used for the events (and the CSR Failed condition)
+// of requests that are older than the configured MaxRequestAge.
+const reasonRequestExpired = "RequestExpired"
+
+// reasonRequestTooLarge is used for the events (and the CSR Failed condition)
+// of requests with a CSR that is larger than the configured MaxCSRSize.
+const reasonRequestTooLarge = "RequestTooLarge"
+
+// reasonSignWarning is used for the events of the Warnings that are returned
+// by a successful Sign.
+const reasonSignWarning = "SignWarning"
+
+// DefaultMaxCSRSize is the default maximum size in bytes of the PEM encoded CSR
+// of a request. It is large enough for CSRs with thousands of subjectAltNames.
+const DefaultMaxCSRSize = 256 * 1024
+
+// DefaultIssuerDeletedGracePeriod is the default duration that a request waits
+// for its issuer to be recreated after the issuer was deleted while the request
+// was being processed. The request is failed permanently afterwards.
+const DefaultIssuerDeletedGracePeriod = 1 * time.Hour
+
+// forceReissueObservedSuffix is appended to the ForceReissueAnnotation to get
+// the name of the annotation that stores the last handled value.
+const forceReissueObservedSuffix = "-observed"
+
 // CertificateRequestReconciler reconciles a CertificateRequest object
 type CertificateRequestReconciler struct {
 	IssuerTypes        []v1alpha1.Issuer
@@ -58,18 +85,112 @@ type CertificateRequestReconciler struct {
 	MaxRetryDuration time.Duration
 	EventSource      kubeutil.EventSource
 
-	// Client is a controller-runtime client used to get and set K8S API resources
+	// MaxRequestAge is the maximum age of a request (based on its creation
+	// timestamp) that will still be signed. Older requests that have not been
+	// signed yet are failed permanently without calling Sign. This is useful
+	// to not sign requests that have been waiting for approval for too long.
+	// This is disabled by default.
+	MaxRequestAge time.Duration
+
+	// SignTimeout is the maximum duration of a single Sign call, the context
+	// passed to Sign is cancelled afterwards and the request is retried. The
+	// deadline that a client can request using the
+	// CertificateRequestMaxWaitAnnotation is capped by this timeout. If zero,
+	// the duration of a Sign call is not limited.
+	SignTimeout time.Duration
+
+	// MaxCSRSize is the maximum size in bytes of the PEM encoded CSR of a
+	// request. Requests with a larger CSR are failed permanently before the
+	// CSR is parsed and without calling Sign, to protect the controller and the
+	// CA against abuse. If zero, DefaultMaxCSRSize is used.
+	MaxCSRSize int
+
+	// IssuerDeletedGracePeriod is the duration that a request waits for its
+	// issuer to be recreated, if the issuer is deleted while the request is
+	// being processed. During this period, the IssuerNotFound condition is set
+	// on the request; afterwards the request is failed permanently. Requests
+	// that reference an issuer that has never been found wait for it to be
+	// created without a deadline. If zero, DefaultIssuerDeletedGracePeriod is
+	// used. If negative, the request is failed as soon as the deletion is noticed.
+	IssuerDeletedGracePeriod time.Duration
+
+	// PendingCertificateRequestResyncInterval is the interval at which requests
+	// that are waiting for their issuer to exist or to become Ready are
+	// reconciled again. Normally, these requests are reconciled as soon as the
+	// issuer changes, this resync is a low-frequency backstop in case that
+	// trigger was missed (eg. during a controller restart). It should be set
+	// to a large value (eg. 10 minutes). This is disabled by default.
+	PendingCertificateRequestResyncInterval time.Duration
+
+	// FormatPendingMessage is an optional function that returns the message of
+	// the Pending Ready condition that is set while the CertificateRequest is
+	// waiting for its issuer to become Ready. The issuer's Ready condition is
+	// passed to the function and is nil if the issuer has no Ready condition.
+	// This can be used to customize (eg. localize or simplify) the message
+	// shown to users. By default, a message that includes the issuer's
+	// Ready condition is used.
+	FormatPendingMessage func(issuerReady *cmapi.IssuerCondition) string
+
+	// ForceReissueAnnotation is the name of an annotation (eg.
+	// "issuer-lib/force-reissue") that can be set on a Failed CertificateRequest
+	// to reset its failed state and to sign it again. Each time the value of the
+	// annotation is changed, the CertificateRequest is re-signed once. The last
+	// handled value is stored in the annotation with the "-observed" suffix.
+	// A forced re-issuance gets a new MaxRetryDuration, counted from the time
+	// it was handled. This is disabled by default.
+	ForceReissueAnnotation string
+
+	// Client is a controller-runtime client used to get and set K8S API resources.
+	// The client of the manager should be used, which reads the CertificateRequests
+	// and issuers from the informer cache instead of getting them from the API server.
 	client.Client
 	// Sign connects to a CA and returns a signed certificate for the supplied CertificateRequest.
 	signer.Sign
+	// AfterSign is an optional function that is called after a successful Sign,
+	// before the CertificateRequest is marked as Ready.
+	signer.AfterSign
 	// IgnoreCertificateRequest is an optional function that can prevent the CertificateRequest
 	// and Kubernetes CSR controllers from reconciling a CertificateRequest resource.
 	signer.IgnoreCertificateRequest
+	// IgnoreIssuer is an optional function that can prevent the issuer controllers from
+	// reconciling an issuer resource. The requests that reference an ignored issuer
+	// are ignored too.
+	signer.IgnoreIssuer
+	// ApproveCertificateRequest is an optional function that approves or denies
+	// CertificateRequests that have not been Approved or Denied yet.
+	// By default, CertificateRequests are approved by an external approval controller.
+	signer.ApproveCertificateRequest
+	// GetNameConstraints is an optional function that returns the name constraints
+	// of an issuer, which are validated before calling Sign.
+	signer.GetNameConstraints
+
+	// QuotaCheck is an optional function that is called before the Sign
+	// function to enforce a quota (see signer.QuotaCheck). If the quota is
+	// exceeded, the QuotaExceeded condition is set and the request is retried.
+	signer.QuotaCheck
+	// DefaultUsages is an optional function that returns the usages that an
+	// issuer applies to CertificateRequests that don't specify any usages.
+	signer.DefaultUsages
+
+	// HTTPClientProvider is an optional function that returns a pre-configured
+	// HTTP client (eg. with proxy, TLS trust and timeout settings) that is
+	// passed to the Sign and AfterSign functions using the context. The client can be retrieved using
+	// signer.HTTPClientFromContext.
+	HTTPClientProvider func() *http.Client
+
+	// APIReader is an optional reader that is passed to the Sign and AfterSign
+	// functions using the context, so they can read Kubernetes objects using the
+	// credentials of the controller. It can be retrieved using
+	// signer.APIReaderFromContext. The CombinedController sets it to the
+	// (uncached) API reader of the manager.
+	APIReader client.Reader
 
 	// EventRecorder is used for creating Kubernetes events on resources.
 	EventRecorder record.EventRecorder
 
-	// Clock is used to mock condition transition times in tests.
+	// Clock is used to mock condition transition times in tests. It is also
+	// used to determine whether the MaxRetryDuration has passed, so tests can
+	// expire it deterministically using a fake clock.
 	Clock clock.PassiveClock
 
 	// SetCAOnCertificateRequest is used to enable setting the CA status field on
@@ -79,6 +200,116 @@ type CertificateRequestReconciler struct {
 	// separately using a tool such as trust-manager.
 	SetCAOnCertificateRequest bool
 
+	// PreserveExistingCertificate is used to take over CertificateRequests that
+	// already have a certificate in their status (eg. when migrating from
+	// another issuer). Such a CertificateRequest is marked as Ready without
+	// calling Sign, and its certificate is never overwritten. This is disabled
+	// by default.
+	PreserveExistingCertificate bool
+
+	// CheckChainCompleteness is used to verify that the certificate chain
+	// returned by the Sign function can be verified up to a self-signed root
+	// certificate that is part of the returned bundle. If the chain is
+	// incomplete, the IncompleteChain condition is set and a Warning event is
+	// emitted. This is disabled by default.
+	CheckChainCompleteness bool
+
+	// RetryIncompleteChain is used to handle an incomplete chain as a retryable
+	// Sign error, instead of only setting the IncompleteChain condition. This
+	// option is only used if CheckChainCompleteness is enabled.
+	RetryIncompleteChain bool
+
+	// RequireSCT is used to verify that the certificate returned by the Sign
+	// function contains embedded signed certificate timestamps (SCTs), as is
+	// required for publicly trusted certificates. A certificate without enough
+	// SCTs is rejected and the request is marked as Failed. This is disabled by
+	// default.
+	RequireSCT bool
+
+	// MinimumSCTs is the minimum number of embedded SCTs that is required when
+	// RequireSCT is enabled. Defaults to 1.
+	MinimumSCTs int
+
+	// RequireExactSANs is used to verify that the certificate returned by the
+	// Sign function contains exactly the subject alternative names that were
+	// requested. A certificate with added or missing SANs is handled as a
+	// retryable Sign error, which catches CAs that are misconfigured to
+	// silently change the SANs. This is disabled by default.
+	RequireExactSANs bool
+
+	// DeduplicateSANs removes duplicate subject alternative names (eg. the same
+	// DNS name twice, or with a different case) from the request that is passed
+	// to the Sign function, see signer.DeduplicateSANs. The removed SANs are
+	// recorded in a "SANsDeduplicated" event. The raw CSR is not changed.
+	DeduplicateSANs bool
+
+	// RejectConflictingSANs fails requests with conflicting subject alternative
+	// names (eg. an IP address requested as a DNS name) permanently, without
+	// calling the Sign function, see signer.ConflictingSANs.
+	RejectConflictingSANs bool
+
+	// IsPermanent is an optional function that classifies additional Sign
+	// errors as permanent, in addition to the errors that wrap a
+	// signer.PermanentError. This can be used to handle the sentinel errors of
+	// a backend library without wrapping them.
+	IsPermanent func(error) bool
+
+	// EscalateToIssuer is an optional function that decides which Sign errors
+	// are reported to the issuer, which makes the issuer not Ready until its
+	// Check succeeds again. By default, only errors that wrap a
+	// signer.IssuerError are escalated. If set, this function replaces that
+	// default, so that eg. transient per-request problems don't make the whole
+	// issuer not Ready. Errors that are not escalated are handled on the
+	// request only (an escalated signer.IssuerError is reported with the error
+	// that it wraps).
+	EscalateToIssuer func(error) bool
+
+	// TreatAllErrorsAsRetryable is a debugging option that handles all Sign
+	// errors as retryable errors, including PermanentErrors. Requests are
+	// then only failed once MaxRetryDuration has passed. This is useful while
+	// developing a new signer, but it is unsafe to use in production: requests
+	// that can never succeed are retried until they time out.
+	TreatAllErrorsAsRetryable bool
+
+	// Logger is an optional logger that is used instead of the manager's logger.
+	// This can be used to inject a pre-configured logger, eg. to filter the
+	// verbosity levels used by the controller (see README.md).
+	Logger logr.Logger
+
+	// DisableForceApply is used to apply status patches without forcing the
+	// ownership of fields that are managed by another field manager. When this
+	// option is set, such conflicts are returned as errors instead of being
+	// overwritten. Forcing is enabled by default.
+	DisableForceApply bool
+
+	// CacheSyncTimeout is the maximum duration that the controller waits for
+	// its informer caches to sync when it starts. This can be increased on
+	// large clusters where the initial sync takes longer. If zero, the manager's
+	// controller options are used, which default to 2 minutes.
+	CacheSyncTimeout time.Duration
+
+	// MaxBackoff caps the exponential backoff of the controller's rate limiter,
+	// so that eg. a CA that becomes available again is detected within a
+	// bounded time. If zero, the backoff of the default rate limiter is used,
+	// which grows up to 1000 seconds.
+	MaxBackoff time.Duration
+
+	// AuditResyncInterval is the interval at which all the outstanding
+	// CertificateRequests (not Ready, Failed or Denied) are reconciled again,
+	// independent of watch events and of the informer resync. A log line is
+	// written for every cycle. If zero, this is disabled.
+	AuditResyncInterval time.Duration
+
+	// PreSetupWithManager is an optional function that is called before the
+	// controller is built. It can be used to customize the controller builder,
+	// eg. to set a custom rate limiter using WithOptions or to add event filters.
+	// The Logger, CacheSyncTimeout and MaxBackoff options of the reconciler
+	// are set on the builder before the hook is called. WithOptions replaces
+	// all the options, so a hook that calls WithOptions has to set these
+	// options again if it wants to keep them.
+	PreSetupWithManager func(context.Context, schema.GroupVersionKind, ctrl.Manager, *builder.Builder) error
+	// PostSetupWithManager is an optional function that is called after the
+	// controller is built, eg. to add extra watches or health checks.
 	PostSetupWithManager func(context.Context, schema.GroupVersionKind, ctrl.Manager, controller.Controller) error
 }
 
@@ -98,7 +329,7 @@ func (r *CertificateRequestReconciler) Reconcile(ctx context.Context, req ctrl.R
 		if err := r.Client.Status().Patch(ctx, &cr, patch, &client.SubResourcePatchOptions{
 			PatchOptions: client.PatchOptions{
 				FieldManager: r.FieldOwner,
-				Force:        ptr.To(true),
+				Force:        ptr.To(!r.DisableForceApply),
 			},
 		}); err != nil {
 			if err := client.IgnoreNotFound(err); err != nil {
@@ -116,6 +347,13 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 	ctx context.Context,
 	req ctrl.Request,
 ) (result ctrl.Result, crStatusPatch *cmapi.CertificateRequestStatus, returnedError error) {
+	if r.HTTPClientProvider != nil {
+		ctx = signer.ContextWithHTTPClient(ctx, r.HTTPClientProvider())
+	}
+	if r.APIReader != nil {
+		ctx = signer.ContextWithAPIReader(ctx, r.APIReader)
+	}
+
 	var cr cmapi.CertificateRequest
 	if err := r.Client.Get(ctx, req.NamespacedName, &cr); err != nil && apierrors.IsNotFound(err) {
 		logger.V(1).Info("Not found. Ignoring.")
@@ -125,20 +363,36 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 	}
 
 	// Ignore CertificateRequest if it has not yet been assigned an approval
-	// status condition by an approval controller.
-	if !cmutil.CertificateRequestIsApproved(&cr) && !cmutil.CertificateRequestIsDenied(&cr) {
+	// status condition by an approval controller, unless we are responsible
+	// for approving it ourselves.
+	isApprovedOrDenied := cmutil.CertificateRequestIsApproved(&cr) || cmutil.CertificateRequestIsDenied(&cr)
+	if !isApprovedOrDenied && r.ApproveCertificateRequest == nil {
 		logger.V(1).Info("CertificateRequest has not been approved or denied. Ignoring.")
 		return result, nil, nil // done
 	}
 
 	// Select first matching issuer type and construct an issuerObject and issuerName
 	issuerObject, issuerName := r.matchIssuerType(&cr)
-	// Ignore CertificateRequest if issuerRef doesn't match one of our issuer Types
+	// Ignore CertificateRequest if issuerRef doesn't match one of our issuer Types,
+	// unless it has our group (the kind is probably mistyped). The unknown kind
+	// is reported below, once we know that the request is not ignored.
+	var unknownIssuerKinds []string
+	var issuerGvk schema.GroupVersionKind
 	if issuerObject == nil {
-		logger.V(1).Info("Foreign issuer. Ignoring.", "group", cr.Spec.IssuerRef.Group, "kind", cr.Spec.IssuerRef.Kind)
-		return result, nil, nil // done
+		unknownIssuerKinds = r.registeredIssuerKinds(cr.Spec.IssuerRef.Group)
+		if len(unknownIssuerKinds) == 0 {
+			logger.V(1).Info("Foreign issuer. Ignoring.", "group", cr.Spec.IssuerRef.Group, "kind", cr.Spec.IssuerRef.Kind)
+			return result, nil, nil // done
+		}
+
+		// The version and scope of an unknown kind are not known, so the
+		// IgnoreCertificateRequest function receives the kind without a
+		// version and the issuer name in the namespace of the request.
+		issuerGvk = schema.GroupVersionKind{Group: cr.Spec.IssuerRef.Group, Kind: cr.Spec.IssuerRef.Kind}
+		issuerName = types.NamespacedName{Name: cr.Spec.IssuerRef.Name, Namespace: cr.Namespace}
+	} else {
+		issuerGvk = issuerObject.GetObjectKind().GroupVersionKind()
 	}
-	issuerGvk := issuerObject.GetObjectKind().GroupVersionKind()
 
 	// Ignore CertificateRequest if it is already Ready
 	if cmutil.CertificateRequestHasCondition(&cr, cmapi.CertificateRequestCondition{
@@ -149,12 +403,15 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 		return result, nil, nil // done
 	}
 
-	// Ignore CertificateRequest if it is already Failed
-	if cmutil.CertificateRequestHasCondition(&cr, cmapi.CertificateRequestCondition{
+	// Ignore CertificateRequest if it is already Failed, unless a re-issuance
+	// has been forced.
+	isFailed := cmutil.CertificateRequestHasCondition(&cr, cmapi.CertificateRequestCondition{
 		Type:   cmapi.CertificateRequestConditionReady,
 		Status: cmmeta.ConditionFalse,
 		Reason: cmapi.CertificateRequestReasonFailed,
-	}) {
+	})
+	forceReissueValue, forceReissue := r.forceReissueRequested(&cr)
+	if isFailed && !forceReissue {
 		logger.V(1).Info("CertificateRequest is Failed. Ignoring.")
 		return result, nil, nil // done
 	}
@@ -169,6 +426,18 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 		return result, nil, nil // done
 	}
 
+	// Ignore CertificateRequest if it needs a manual review, unless it has
+	// been resumed.
+	needsManualReview := cmutil.CertificateRequestHasCondition(&cr, cmapi.CertificateRequestCondition{
+		Type:   v1alpha1.CertificateRequestConditionNeedsManualReview,
+		Status: cmmeta.ConditionTrue,
+	})
+	resumeValue, resume := resumeRequested(&cr)
+	if needsManualReview && !resume {
+		logger.V(1).Info("CertificateRequest needs a manual review. Ignoring.")
+		return result, nil, nil // done
+	}
+
 	if r.IgnoreCertificateRequest != nil {
 		ignore, err := r.IgnoreCertificateRequest(ctx, signer.CertificateRequestObjectFromCertificateRequest(&cr), issuerGvk, issuerName)
 		if err != nil {
@@ -180,10 +449,80 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 		}
 	}
 
+	if issuerObject == nil {
+		return r.reportUnknownIssuerKind(logger, &cr, unknownIssuerKinds)
+	}
+
 	// We now have a CertificateRequest that belongs to us so we are responsible
 	// for updating its Status.
 	crStatusPatch = &cmapi.CertificateRequestStatus{}
 
+	if r.ApproveCertificateRequest != nil {
+		if !isApprovedOrDenied {
+			return r.approveCertificateRequest(logger, ctx, &cr, issuerObject, issuerName, crStatusPatch)
+		}
+
+		// Include the approval condition in every patch, our field manager might
+		// own it and omitting it from the patch would remove it.
+		for _, condition := range cr.Status.Conditions {
+			if condition.Type == cmapi.CertificateRequestConditionApproved ||
+				condition.Type == cmapi.CertificateRequestConditionDenied {
+				crStatusPatch.Conditions = append(crStatusPatch.Conditions, condition)
+			}
+		}
+	}
+
+	if forceReissue {
+		if isFailed {
+			logger.V(1).Info("Re-issuance was forced. Resetting the failed state.", "value", forceReissueValue)
+			conditions.SetCertificateRequestStatusCondition(
+				r.Clock,
+				cr.Status.Conditions,
+				&crStatusPatch.Conditions,
+				cmapi.CertificateRequestConditionReady,
+				cmmeta.ConditionUnknown,
+				v1alpha1.CertificateRequestConditionReasonInitializing,
+				fmt.Sprintf("Re-issuance was forced using the %q annotation", r.ForceReissueAnnotation),
+			)
+			r.EventRecorder.Eventf(&cr, corev1.EventTypeNormal, "ForceReissue", "Re-issuance was forced using the %q annotation", r.ForceReissueAnnotation)
+			// The failure time is cleared by omitting it from the patch. Changing
+			// the Ready condition does not trigger a new reconcile, so we requeue.
+			result.Requeue = true
+			return result, crStatusPatch, nil // apply patch, done
+		}
+
+		// The failed state has been reset in a previous reconcile, record that
+		// this value of the annotation has been handled and start a new retry
+		// window, so that a request that is older than the MaxRetryDuration is
+		// not failed again on the first retryable error.
+		if _, err := applySignerAnnotations(ctx, r.Client, &cr, map[string]string{
+			r.ForceReissueAnnotation + forceReissueObservedSuffix: forceReissueValue,
+			v1alpha1.CertificateRequestRetryDeadlineAnnotation:    r.Clock.Now().Add(r.MaxRetryDuration).UTC().Format(time.RFC3339),
+		}); err != nil {
+			return result, nil, fmt.Errorf("failed to record the handled force re-issue annotation: %v", err) // retry
+		}
+	}
+
+	if needsManualReview {
+		logger.V(1).Info("CertificateRequest was resumed after a manual review.", "value", resumeValue)
+		conditions.SetCertificateRequestStatusCondition(
+			r.Clock,
+			cr.Status.Conditions,
+			&crStatusPatch.Conditions,
+			v1alpha1.CertificateRequestConditionNeedsManualReview,
+			cmmeta.ConditionFalse,
+			v1alpha1.CertificateRequestConditionReasonResumed,
+			fmt.Sprintf("CertificateRequest was resumed using the %q annotation", v1alpha1.CertificateRequestResumeAnnotation),
+		)
+		r.EventRecorder.Eventf(&cr, corev1.EventTypeNormal, "Resumed", "CertificateRequest was resumed using the %q annotation", v1alpha1.CertificateRequestResumeAnnotation)
+
+		if _, err := applySignerAnnotations(ctx, r.Client, &cr, map[string]string{
+			v1alpha1.CertificateRequestResumeObservedAnnotation: resumeValue,
+		}); err != nil {
+			return result, nil, fmt.Errorf("failed to record the handled resume annotation: %v", err) // retry
+		}
+	}
+
 	// Add a Ready condition if one does not already exist. Set initial Status
 	// to Unknown.
 	if ready := cmutil.GetCertificateRequestCondition(&cr, cmapi.CertificateRequestConditionReady); ready == nil {
@@ -219,8 +558,77 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 		return result, crStatusPatch, nil // done, apply patch
 	}
 
-	if err := r.Client.Get(ctx, issuerName, issuerObject); err != nil && apierrors.IsNotFound(err) {
+	// Fail permanently if the request is too old to still be signed.
+	if r.MaxRequestAge > 0 && len(cr.Status.Certificate) == 0 &&
+		r.Clock.Now().After(cr.CreationTimestamp.Add(r.MaxRequestAge)) {
+		logger.V(1).Info("CertificateRequest is older than the maximum request age. Marking as failed.")
+		_, failedAt := conditions.SetCertificateRequestStatusCondition(
+			r.Clock,
+			cr.Status.Conditions,
+			&crStatusPatch.Conditions,
+			cmapi.CertificateRequestConditionReady,
+			cmmeta.ConditionFalse,
+			cmapi.CertificateRequestReasonFailed,
+			fmt.Sprintf("CertificateRequest has failed permanently: the request is older than the maximum request age of %s", r.MaxRequestAge),
+		)
+		crStatusPatch.FailureTime = failedAt.DeepCopy()
+		r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, reasonRequestExpired, "The request is older than the maximum request age of %s, not signing it", r.MaxRequestAge)
+		return result, crStatusPatch, nil // done, apply patch
+	}
+
+	// Fail permanently if the CSR is too large, before it is parsed.
+	if maxCSRSize := maxCSRSizeOrDefault(r.MaxCSRSize); len(cr.Status.Certificate) == 0 && len(cr.Spec.Request) > maxCSRSize {
+		logger.V(1).Info("CertificateRequest has a CSR that is larger than the maximum size. Marking as failed.", "size", len(cr.Spec.Request), "maxSize", maxCSRSize)
+		_, failedAt := conditions.SetCertificateRequestStatusCondition(
+			r.Clock,
+			cr.Status.Conditions,
+			&crStatusPatch.Conditions,
+			cmapi.CertificateRequestConditionReady,
+			cmmeta.ConditionFalse,
+			cmapi.CertificateRequestReasonFailed,
+			fmt.Sprintf("CertificateRequest has failed permanently: the CSR is %d bytes, which is larger than the maximum size of %d bytes", len(cr.Spec.Request), maxCSRSize),
+		)
+		crStatusPatch.FailureTime = failedAt.DeepCopy()
+		r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, reasonRequestTooLarge, "The CSR is %d bytes, which is larger than the maximum size of %d bytes, not signing it", len(cr.Spec.Request), maxCSRSize)
+		return result, crStatusPatch, nil // done, apply patch
+	}
+
+	// Fail permanently once the deadline requested by the client has passed,
+	// also while the request is waiting for its issuer.
+	deadline := r.requestDeadline(logger, &cr)
+	if !deadline.IsZero() && len(cr.Status.Certificate) == 0 && !r.Clock.Now().Before(deadline) {
+		err := fmt.Errorf("%w: the client requested the certificate before %s", errRequestDeadlineExceeded, deadline.UTC().Format(time.RFC3339))
+		logger.V(1).Error(err, "The request deadline has passed. Marking as failed.")
+		_, failedAt := conditions.SetCertificateRequestStatusCondition(
+			r.Clock,
+			cr.Status.Conditions,
+			&crStatusPatch.Conditions,
+			cmapi.CertificateRequestConditionReady,
+			cmmeta.ConditionFalse,
+			cmapi.CertificateRequestReasonFailed,
+			fmt.Sprintf("CertificateRequest has failed permanently: %s", err),
+		)
+		crStatusPatch.FailureTime = failedAt.DeepCopy()
+		r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, v1alpha1.CertificateRequestReasonRequestDeadlineExceeded, "Failed permanently to sign CertificateRequest: %s", err)
+		return result, crStatusPatch, nil // done, apply patch
+	}
+
+	// The issuer types are watched (see SetupWithManager), so the issuer is read
+	// from the informer cache. A stale issuer is fine: the Ready condition is the
+	// only state we rely on and a change of that condition triggers a new reconcile.
+	if err := r.Client.Get(ctx, issuerName, issuerObject); err != nil && apierrors.IsNotFound(err) && issuerWasFound(&cr) {
+		return r.handleDeletedIssuer(logger, &cr, err, crStatusPatch)
+	} else if err != nil && apierrors.IsNotFound(err) {
 		logger.V(1).Info("Issuer not found. Waiting for it to be created")
+		conditions.SetCertificateRequestStatusCondition(
+			r.Clock,
+			cr.Status.Conditions,
+			&crStatusPatch.Conditions,
+			v1alpha1.CertificateRequestConditionIssuerNotFound,
+			cmmeta.ConditionTrue,
+			v1alpha1.CertificateRequestConditionReasonIssuerNotCreated,
+			fmt.Sprintf("%s. Waiting for it to be created.", err),
+		)
 		conditions.SetCertificateRequestStatusCondition(
 			r.Clock,
 			cr.Status.Conditions,
@@ -230,6 +638,7 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 			cmapi.CertificateRequestReasonPending,
 			fmt.Sprintf("%s. Waiting for it to be created.", err),
 		)
+		result.RequeueAfter = r.pendingRequeueAfter(deadline) // resync backstop
 		r.EventRecorder.Eventf(&cr, corev1.EventTypeNormal, "WaitingForIssuerExist", "Waiting for the issuer to exist")
 		return result, crStatusPatch, nil // done, apply patch
 	} else if err != nil {
@@ -237,6 +646,31 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 		return result, nil, fmt.Errorf("unexpected get error: %v", err) // retry
 	}
 
+	// Record that the issuer was found, a later deletion of the issuer starts
+	// the IssuerDeletedGracePeriod only once this has been recorded.
+	conditions.SetCertificateRequestStatusCondition(
+		r.Clock,
+		cr.Status.Conditions,
+		&crStatusPatch.Conditions,
+		v1alpha1.CertificateRequestConditionIssuerNotFound,
+		cmmeta.ConditionFalse,
+		v1alpha1.CertificateRequestConditionReasonIssuerFound,
+		"The issuer was found.",
+	)
+
+	if r.IgnoreIssuer != nil {
+		ignore, err := r.IgnoreIssuer(ctx, issuerObject)
+		if err != nil {
+			return result, nil, fmt.Errorf("failed to check if issuer should be ignored: %v", err) // retry
+		}
+		if ignore {
+			// The issuer is handled by a different controller, and so are the
+			// requests that reference it.
+			logger.V(1).Info("IgnoreIssuer() returned true for the issuer. Ignoring.")
+			return result, nil, nil // done
+		}
+	}
+
 	readyCondition := conditions.GetIssuerStatusCondition(
 		issuerObject.GetStatus().Conditions,
 		cmapi.IssuerConditionReady,
@@ -246,7 +680,9 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 		(readyCondition.ObservedGeneration < issuerObject.GetGeneration()) {
 
 		message := ""
-		if readyCondition == nil {
+		if r.FormatPendingMessage != nil {
+			message = r.FormatPendingMessage(readyCondition)
+		} else if readyCondition == nil {
 			message = "Issuer is not Ready yet. No ready condition found. Waiting for it to become ready."
 		} else if readyCondition.Status != cmmeta.ConditionTrue {
 			message = fmt.Sprintf("Issuer is not Ready yet. Current ready condition is \"%s\": %s. Waiting for it to become ready.", readyCondition.Reason, readyCondition.Message)
@@ -264,18 +700,76 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 			cmapi.CertificateRequestReasonPending,
 			message,
 		)
+		result.RequeueAfter = r.pendingRequeueAfter(deadline) // resync backstop
 		r.EventRecorder.Eventf(&cr, corev1.EventTypeNormal, "WaitingForIssuerReady", "Waiting for the issuer to become ready")
 		return result, crStatusPatch, nil // done, apply patch
 	}
 
-	signedCertificate, err := r.Sign(log.IntoContext(ctx, logger), signer.CertificateRequestObjectFromCertificateRequest(&cr), issuerObject)
+	crObject := signer.CertificateRequestObjectFromCertificateRequest(&cr)
+	if r.DefaultUsages != nil {
+		keyUsage, extKeyUsages := r.DefaultUsages(issuerObject)
+		crObject = signer.WithDefaultUsages(crObject, keyUsage, extKeyUsages)
+	}
+	crObject, removedSANs, sanErr := normalizeSANs(crObject, r.DeduplicateSANs, r.RejectConflictingSANs)
+
+	var signedCertificate signer.PEMBundle
+	var err error
+	preserved := false
+	if r.PreserveExistingCertificate && len(cr.Status.Certificate) > 0 {
+		logger.V(1).Info("CertificateRequest already has a certificate, preserving it.")
+		signedCertificate = signer.PEMBundle{
+			ChainPEM: cr.Status.Certificate,
+			CAPEM:    cr.Status.CA,
+		}
+		preserved = true
+	} else if r.AfterSign != nil && len(cr.Status.Certificate) > 0 {
+		// The certificate was signed in a previous reconcile, but the AfterSign
+		// function failed. We retry the AfterSign function without signing again.
+		logger.V(1).Info("Certificate was already signed, retrying AfterSign.")
+		signedCertificate = signer.PEMBundle{
+			ChainPEM: cr.Status.Certificate,
+			CAPEM:    cr.Status.CA,
+		}
+	} else if err = joinValidationErrors(
+		checkNameConstraints(ctx, r.GetNameConstraints, crObject, issuerObject),
+		sanErr,
+	); err == nil {
+		if len(removedSANs) > 0 {
+			r.EventRecorder.Eventf(&cr, corev1.EventTypeNormal, "SANsDeduplicated", "Removed duplicate SANs from the request before signing: %s", strings.Join(removedSANs, ", "))
+		}
+		err = checkQuota(ctx, r.QuotaCheck, crObject, issuerObject)
+		if err == nil {
+			signedCertificate, err = r.signWithDeadline(logger, ctx, deadline, crObject, issuerObject)
+		}
+		if err == nil && !signedCertificate.NotAfter.IsZero() {
+			// Verify that the NotAfter returned by the signer matches the certificate.
+			_, err = signedCertificate.CertificateNotAfter()
+		}
+		if err == nil && r.RequireSCT {
+			if sctErr := verifySCTs(signedCertificate, r.MinimumSCTs); sctErr != nil {
+				// Retrying won't help, the CA profile has to be fixed.
+				err = signer.PermanentError{Err: sctErr}
+			}
+		}
+		if err == nil && r.RequireExactSANs {
+			err = verifyExactSANs(signedCertificate, crObject) // handled as a retryable Sign error below
+		}
+		if err == nil && r.CheckChainCompleteness {
+			chainErr := verifyChainCompleteness(signedCertificate, r.Clock.Now())
+			if chainErr != nil && r.RetryIncompleteChain {
+				err = chainErr // handled as a retryable Sign error below
+			} else {
+				r.setIncompleteChainCondition(logger, &cr, crStatusPatch, chainErr)
+			}
+		}
+	}
 	if err != nil {
 		// An error in the issuer part of the operator should trigger a reconcile
 		// of the issuer's state.
-		if issuerError := new(signer.IssuerError); errors.As(err, issuerError) {
+		if issuerError, escalate := escalatedIssuerError(err, r.EscalateToIssuer); escalate {
 			if reportError := r.EventSource.ReportError(
 				issuerGvk, client.ObjectKeyFromObject(issuerObject),
-				issuerError.Err,
+				issuerError,
 			); reportError != nil {
 				err = utilerrors.NewAggregate([]error{err, reportError})
 			}
@@ -290,10 +784,15 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 				cmapi.CertificateRequestReasonPending,
 				"Issuer is not Ready yet. Current ready condition is outdated. Waiting for it to become ready.",
 			)
-			r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, "WaitingForIssuerReady", "Waiting for the issuer to become ready")
+			result.RequeueAfter = r.PendingCertificateRequestResyncInterval // resync backstop
+			r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, "IssuerErrorReported", "Sign reported an issuer error, waiting for the issuer to become ready again: %v", issuerError)
 			return result, crStatusPatch, nil // done, apply patch
 		}
 
+		if manualIntervention := new(signer.ManualInterventionError); errors.As(err, manualIntervention) {
+			return r.parkForManualReview(logger, ctx, &cr, *manualIntervention, crStatusPatch)
+		}
+
 		didCustomConditionTransition := false
 
 		if targetCustom := new(signer.SetCertificateRequestConditionError); errors.As(err, targetCustom) {
@@ -314,9 +813,11 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 		}
 
 		// Check if we have still time to requeue & retry
-		isPendingError := errors.As(err, &signer.PendingError{})
-		isPermanentError := errors.As(err, &signer.PermanentError{})
-		pastMaxRetryDuration := r.Clock.Now().After(cr.CreationTimestamp.Add(r.MaxRetryDuration))
+		pendingError := signer.PendingError{}
+		isPendingError := errors.As(err, &pendingError)
+		isDeadlineExceeded := errors.Is(err, errRequestDeadlineExceeded)
+		isPermanentError := isDeadlineExceeded || (!r.TreatAllErrorsAsRetryable && isPermanentError(err, r.IsPermanent))
+		pastMaxRetryDuration := r.Clock.Now().After(r.retryDeadline(&cr))
 		if !isPendingError && (isPermanentError || pastMaxRetryDuration) {
 			// fail permanently
 			logger.V(1).Error(err, "Permanent CertificateRequest error. Marking as failed.")
@@ -330,7 +831,11 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 				fmt.Sprintf("CertificateRequest has failed permanently: %s", err),
 			)
 			crStatusPatch.FailureTime = failedAt.DeepCopy()
-			r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, "PermanentError", "Failed permanently to sign CertificateRequest: %s", err)
+			eventReason := "PermanentError"
+			if isDeadlineExceeded {
+				eventReason = v1alpha1.CertificateRequestReasonRequestDeadlineExceeded
+			}
+			r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, eventReason, "Failed permanently to sign CertificateRequest: %s", err)
 			return result, crStatusPatch, nil // done, apply patch
 		} else {
 			// retry
@@ -346,6 +851,19 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 			)
 
 			r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, "RetryableError", "Failed to sign CertificateRequest, will retry: %s", err)
+
+			if !isPendingError {
+				if err := r.setRetryDeadlineAnnotation(ctx, &cr); err != nil {
+					return result, nil, err // retry
+				}
+			}
+
+			if isPendingError && pendingError.RequeueAfter > 0 {
+				// the signer indicated when the request should be reconciled again
+				result.RequeueAfter = pendingError.RequeueAfter
+				return result, crStatusPatch, nil // requeue after the requested duration, apply patch
+			}
+
 			if didCustomConditionTransition {
 				// the reconciliation loop will be retriggered because of the added/ changed custom condition
 				return result, crStatusPatch, nil // done, apply patch
@@ -364,10 +882,77 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 		}
 	}
 
+	for _, warning := range signedCertificate.Warnings {
+		r.EventRecorder.Event(&cr, corev1.EventTypeWarning, reasonSignWarning, warning)
+	}
+
+	annotations, invalidArtifacts := signerAnnotations(signedCertificate)
+	if len(invalidArtifacts) > 0 {
+		logger.V(1).Info("Ignoring artifacts with an invalid name.", "artifacts", invalidArtifacts)
+		r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, "IgnoredArtifacts", "Ignored artifacts with a name that is not a valid annotation name: %q", invalidArtifacts)
+	}
+
+	ignoredAnnotations, err := applySignerAnnotations(ctx, r.Client, &cr, annotations)
+	if err != nil {
+		return result, nil, fmt.Errorf("failed to set annotations: %v", err) // retry
+	}
+	if len(ignoredAnnotations) > 0 {
+		logger.V(1).Info("Ignoring annotations with a reserved prefix.", "annotations", ignoredAnnotations)
+		r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, "IgnoredAnnotations", "Ignored annotations with the reserved %q prefix: %q", reservedAnnotationPrefix, ignoredAnnotations)
+	}
+
 	crStatusPatch.Certificate = signedCertificate.ChainPEM
 	if r.SetCAOnCertificateRequest {
 		crStatusPatch.CA = signedCertificate.CAPEM
 	}
+
+	if r.AfterSign != nil {
+		if err := r.AfterSign(log.IntoContext(ctx, logger), crObject, issuerObject, signedCertificate); err != nil {
+			// AfterSign errors are retried until the MaxRetryDuration has
+			// passed, like Sign errors.
+			isPermanentError := !r.TreatAllErrorsAsRetryable && isPermanentError(err, r.IsPermanent)
+			pastMaxRetryDuration := r.Clock.Now().After(r.retryDeadline(&cr))
+			if isPermanentError || pastMaxRetryDuration {
+				// fail permanently, the certificate is removed from the status
+				// because AfterSign never succeeded for it
+				logger.V(1).Error(err, "Permanent AfterSign error. Marking as failed.")
+				crStatusPatch.Certificate = nil
+				crStatusPatch.CA = nil
+				_, failedAt := conditions.SetCertificateRequestStatusCondition(
+					r.Clock,
+					cr.Status.Conditions,
+					&crStatusPatch.Conditions,
+					cmapi.CertificateRequestConditionReady,
+					cmmeta.ConditionFalse,
+					cmapi.CertificateRequestReasonFailed,
+					fmt.Sprintf("CertificateRequest has failed permanently: AfterSign failed: %s", err),
+				)
+				crStatusPatch.FailureTime = failedAt.DeepCopy()
+				r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, "PermanentError", "Failed permanently to run AfterSign: %s", err)
+				return result, crStatusPatch, nil // done, apply patch
+			}
+
+			// retry, the signed certificate is stored in the status so that
+			// we don't have to sign it again
+			logger.V(1).Error(err, "AfterSign error.")
+			conditions.SetCertificateRequestStatusCondition(
+				r.Clock,
+				cr.Status.Conditions,
+				&crStatusPatch.Conditions,
+				cmapi.CertificateRequestConditionReady,
+				cmmeta.ConditionFalse,
+				cmapi.CertificateRequestReasonPending,
+				fmt.Sprintf("CertificateRequest was signed, but AfterSign failed: %s", err),
+			)
+			r.EventRecorder.Eventf(&cr, corev1.EventTypeWarning, "AfterSignError", "Failed to run AfterSign, will retry: %s", err)
+			if err := r.setRetryDeadlineAnnotation(ctx, &cr); err != nil {
+				return result, nil, err // retry
+			}
+			result.Requeue = true
+			return result, crStatusPatch, nil // requeue with backoff, apply patch
+		}
+	}
+
 	conditions.SetCertificateRequestStatusCondition(
 		r.Clock,
 		cr.Status.Conditions,
@@ -379,7 +964,106 @@ func (r *CertificateRequestReconciler) reconcileStatusPatch(
 	)
 
 	logger.V(1).Info("Successfully finished the reconciliation.")
-	r.EventRecorder.Eventf(&cr, corev1.EventTypeNormal, "Issued", "Succeeded signing the CertificateRequest")
+	if preserved {
+		r.EventRecorder.Eventf(&cr, corev1.EventTypeNormal, "Issued", "Preserved the existing certificate of the CertificateRequest")
+	} else {
+		r.EventRecorder.Eventf(&cr, corev1.EventTypeNormal, "Issued", "Succeeded signing the CertificateRequest")
+	}
+	return result, crStatusPatch, nil // done, apply patch
+}
+
+// setIncompleteChainCondition sets the IncompleteChain condition if the chain
+// is incomplete. If the chain is complete, an existing IncompleteChain
+// condition is set to False.
+func (r *CertificateRequestReconciler) setIncompleteChainCondition(
+	logger logr.Logger,
+	cr *cmapi.CertificateRequest,
+	crStatusPatch *cmapi.CertificateRequestStatus,
+	chainErr error,
+) {
+	if chainErr == nil {
+		if cmutil.GetCertificateRequestCondition(cr, v1alpha1.CertificateRequestConditionIncompleteChain) != nil {
+			conditions.SetCertificateRequestStatusCondition(
+				r.Clock,
+				cr.Status.Conditions,
+				&crStatusPatch.Conditions,
+				v1alpha1.CertificateRequestConditionIncompleteChain,
+				cmmeta.ConditionFalse,
+				v1alpha1.CertificateRequestConditionReasonCompleteChain,
+				"The certificate chain is complete",
+			)
+		}
+		return
+	}
+
+	logger.V(1).Info("Certificate chain is incomplete.", "error", chainErr)
+	conditions.SetCertificateRequestStatusCondition(
+		r.Clock,
+		cr.Status.Conditions,
+		&crStatusPatch.Conditions,
+		v1alpha1.CertificateRequestConditionIncompleteChain,
+		cmmeta.ConditionTrue,
+		v1alpha1.CertificateRequestConditionReasonIncompleteChain,
+		chainErr.Error(),
+	)
+	r.EventRecorder.Eventf(cr, corev1.EventTypeWarning, "IncompleteChain", "The signed certificate chain is incomplete: %s", chainErr)
+}
+
+// approveCertificateRequest calls the ApproveCertificateRequest function and
+// sets the resulting Approved or Denied condition on the CertificateRequest.
+// The CertificateRequest is not approved or denied before the referenced
+// issuer exists.
+func (r *CertificateRequestReconciler) approveCertificateRequest(
+	logger logr.Logger,
+	ctx context.Context,
+	cr *cmapi.CertificateRequest,
+	issuerObject v1alpha1.Issuer,
+	issuerName types.NamespacedName,
+	crStatusPatch *cmapi.CertificateRequestStatus,
+) (ctrl.Result, *cmapi.CertificateRequestStatus, error) {
+	var result ctrl.Result
+
+	if err := r.Client.Get(ctx, issuerName, issuerObject); err != nil && apierrors.IsNotFound(err) {
+		// The CertificateRequest will be reconciled again once the issuer is created.
+		logger.V(1).Info("Issuer not found. Waiting for it to be created before approving.")
+		return result, nil, nil // done
+	} else if err != nil {
+		return result, nil, fmt.Errorf("unexpected get error: %v", err) // retry
+	}
+
+	approve, reason, message, err := r.ApproveCertificateRequest(log.IntoContext(ctx, logger), signer.CertificateRequestObjectFromCertificateRequest(cr), issuerObject)
+	if err != nil {
+		r.EventRecorder.Eventf(cr, corev1.EventTypeWarning, "ApprovalError", "Failed to approve or deny CertificateRequest, will retry: %s", err)
+		return result, nil, fmt.Errorf("failed to approve or deny CertificateRequest: %v", err) // retry
+	}
+
+	conditionType := cmapi.CertificateRequestConditionDenied
+	eventReason := "Denied"
+	if approve {
+		conditionType = cmapi.CertificateRequestConditionApproved
+		eventReason = "Approved"
+	}
+
+	if reason == "" {
+		reason = r.FieldOwner
+	}
+	if message == "" {
+		message = fmt.Sprintf("CertificateRequest has been %s by %s", strings.ToLower(eventReason), r.FieldOwner)
+	}
+
+	logger.V(1).Info("Setting approval condition", "type", conditionType, "reason", reason)
+	conditions.SetCertificateRequestStatusCondition(
+		r.Clock,
+		cr.Status.Conditions,
+		&crStatusPatch.Conditions,
+		conditionType,
+		cmmeta.ConditionTrue,
+		reason,
+		message,
+	)
+	r.EventRecorder.Event(cr, corev1.EventTypeNormal, eventReason, message)
+
+	// The added approval condition will trigger a new reconcile loop.
 	return result, crStatusPatch, nil // done, apply patch
 }
 
@@ -421,6 +1105,61 @@ func (r *CertificateRequestReconciler) matchIssuerType(cr *cmapi.CertificateRequ
 	return nil, types.NamespacedName{}
 }
 
+// registeredIssuerKinds returns the kinds of the registered issuer types that
+// have the given group.
+func (r *CertificateRequestReconciler) registeredIssuerKinds(group string) []string {
+	var kinds []string
+	for _, issuerType := range r.allIssuerTypes() {
+		gvk := issuerType.GetObjectKind().GroupVersionKind()
+		if gvk.Group == group {
+			kinds = append(kinds, gvk.Kind)
+		}
+	}
+	return kinds
+}
+
+// reportUnknownIssuerKind sets the UnknownIssuerKind condition on a
+// CertificateRequest whose issuerRef has the group of one of our issuer types
+// but an unknown kind, so that the request doesn't hang without any feedback.
+func (r *CertificateRequestReconciler) reportUnknownIssuerKind(
+	logger logr.Logger,
+	cr *cmapi.CertificateRequest,
+	kinds []string,
+) (ctrl.Result, *cmapi.CertificateRequestStatus, error) {
+	logger.V(1).Info("Unknown issuer kind.", "group", cr.Spec.IssuerRef.Group, "kind", cr.Spec.IssuerRef.Kind, "registeredKinds", kinds)
+	message := fmt.Sprintf("The issuerRef kind %q is not a registered kind of the group %q, expected one of: %s", cr.Spec.IssuerRef.Kind, cr.Spec.IssuerRef.Group, strings.Join(kinds, ", "))
+
+	// Only emit the event once, the condition is the same in every reconcile.
+	if !cmutil.CertificateRequestHasCondition(cr, cmapi.CertificateRequestCondition{
+		Type:   v1alpha1.CertificateRequestConditionUnknownIssuerKind,
+		Status: cmmeta.ConditionTrue,
+	}) {
+		r.EventRecorder.Event(cr, corev1.EventTypeWarning, v1alpha1.CertificateRequestConditionReasonUnknownIssuerKind, message)
+	}
+
+	crStatusPatch := &cmapi.CertificateRequestStatus{}
+	conditions.SetCertificateRequestStatusCondition(
+		r.Clock,
+		cr.Status.Conditions,
+		&crStatusPatch.Conditions,
+		v1alpha1.CertificateRequestConditionUnknownIssuerKind,
+		cmmeta.ConditionTrue,
+		v1alpha1.CertificateRequestConditionReasonUnknownIssuerKind,
+		message,
+	)
+	conditions.SetCertificateRequestStatusCondition(
+		r.Clock,
+		cr.Status.Conditions,
+		&crStatusPatch.Conditions,
+		cmapi.CertificateRequestConditionReady,
+		cmmeta.ConditionFalse,
+		cmapi.CertificateRequestReasonPending,
+		message,
+	)
+
+	return ctrl.Result{}, crStatusPatch, nil // done, apply patch
+}
+
 func (r *CertificateRequestReconciler) allIssuerTypes() []v1alpha1.Issuer {
 	issuers := make([]v1alpha1.Issuer, 0, len(r.IssuerTypes)+len(r.ClusterIssuerTypes))
 	issuers = append(issuers, r.IssuerTypes...)
@@ -428,6 +1167,231 @@ func (r *CertificateRequestReconciler) allIssuerTypes() []v1alpha1.Issuer {
 	return issuers
 }
 
+// issuerGroupKind returns the GroupKind of the issuer type that is responsible
+// for the CertificateRequest, it is used to select the event recorder.
+func (r *CertificateRequestReconciler) issuerGroupKind(object runtime.Object) (schema.GroupKind, bool) {
+	cr, ok := object.(*cmapi.CertificateRequest)
+	if !ok {
+		return schema.GroupKind{}, false
+	}
+
+	issuerType, _ := r.matchIssuerType(cr)
+	if issuerType == nil {
+		return schema.GroupKind{}, false
+	}
+
+	return issuerType.GetObjectKind().GroupVersionKind().GroupKind(), true
+}
+
+// issuerKey returns a key that identifies the issuer that is referenced by the
+// CertificateRequest, eg. "SimpleIssuer.testing.cert-manager.io/ns1/issuer-1".
+func (r *CertificateRequestReconciler) issuerKey(object runtime.Object) (string, bool) {
+	cr, ok := object.(*cmapi.CertificateRequest)
+	if !ok {
+		return "", false
+	}
+
+	issuerType, issuerName := r.matchIssuerType(cr)
+	if issuerType == nil {
+		return "", false
+	}
+
+	return issuerType.GetObjectKind().GroupVersionKind().GroupKind().String() + "/" + issuerName.String(), true
+}
+
+// forceReissueRequested returns the value of the ForceReissueAnnotation and
+// true if the value has been set and has not been handled yet.
+func (r *CertificateRequestReconciler) forceReissueRequested(cr *cmapi.CertificateRequest) (string, bool) {
+	if r.ForceReissueAnnotation == "" {
+		return "", false
+	}
+
+	value := cr.Annotations[r.ForceReissueAnnotation]
+	if value == "" || value == cr.Annotations[r.ForceReissueAnnotation+forceReissueObservedSuffix] {
+		return "", false
+	}
+
+	return value, true
+}
+
+// errRequestDeadlineExceeded is wrapped by the error that is returned if the
+// deadline set using the CertificateRequestMaxWaitAnnotation has passed.
+var errRequestDeadlineExceeded = errors.New("the request deadline was exceeded")
+
+// requestDeadline returns the deadline that the client requested using the
+// CertificateRequestMaxWaitAnnotation, or the zero time if no (valid) deadline
+// was requested.
+func (r *CertificateRequestReconciler) requestDeadline(logger logr.Logger, cr *cmapi.CertificateRequest) time.Time {
+	value, ok := cr.Annotations[v1alpha1.CertificateRequestMaxWaitAnnotation]
+	if !ok {
+		return time.Time{}
+	}
+
+	maxWait, err := time.ParseDuration(value)
+	if err != nil || maxWait <= 0 {
+		logger.V(1).Info("Ignoring the invalid max-wait annotation.", "value", value)
+		r.EventRecorder.Eventf(cr, corev1.EventTypeWarning, "InvalidMaxWait", "Ignored the %q annotation, its value %q is not a positive duration", v1alpha1.CertificateRequestMaxWaitAnnotation, value)
+		return time.Time{}
+	}
+
+	return cr.CreationTimestamp.Add(maxWait)
+}
+
+// pendingRequeueAfter returns when a request that waits for its issuer is
+// reconciled again: after the PendingCertificateRequestResyncInterval, but no
+// later than the deadline requested by the client, so that the request is
+// failed once the deadline has passed.
+func (r *CertificateRequestReconciler) pendingRequeueAfter(deadline time.Time) time.Duration {
+	if deadline.IsZero() {
+		return r.PendingCertificateRequestResyncInterval
+	}
+
+	remaining := deadline.Sub(r.Clock.Now())
+	if interval := r.PendingCertificateRequestResyncInterval; interval > 0 && interval < remaining {
+		return interval
+	}
+	return remaining
+}
+
+// signWithDeadline calls the Sign function with a context that expires after
+// the SignTimeout or at the deadline that the client requested using the
+// CertificateRequestMaxWaitAnnotation (if not zero), whichever comes first. If
+// the requested deadline has passed (before or during the Sign call), an error
+// that wraps errRequestDeadlineExceeded is returned.
+func (r *CertificateRequestReconciler) signWithDeadline(
+	logger logr.Logger,
+	ctx context.Context,
+	deadline time.Time,
+	crObject signer.CertificateRequestObject,
+	issuerObject v1alpha1.Issuer,
+) (signer.PEMBundle, error) {
+	requestCtx := log.IntoContext(ctx, logger)
+
+	if !deadline.IsZero() {
+		remaining := deadline.Sub(r.Clock.Now())
+		if remaining <= 0 {
+			return signer.PEMBundle{}, fmt.Errorf("%w: the client requested the certificate before %s", errRequestDeadlineExceeded, deadline.UTC().Format(time.RFC3339))
+		}
+
+		// The deadline is relative to the (mockable) Clock.
+		var cancel context.CancelFunc
+		requestCtx, cancel = context.WithTimeout(requestCtx, remaining)
+		defer cancel()
+	}
+
+	signCtx := requestCtx
+	if r.SignTimeout > 0 {
+		var cancel context.CancelFunc
+		signCtx, cancel = context.WithTimeout(signCtx, r.SignTimeout)
+		defer cancel()
+	}
+
+	bundle, err := r.Sign(signCtx, crObject, issuerObject)
+	if err != nil && !deadline.IsZero() && errors.Is(requestCtx.Err(), context.DeadlineExceeded) {
+		return signer.PEMBundle{}, fmt.Errorf("%w: the client requested the certificate before %s: %v", errRequestDeadlineExceeded, deadline.UTC().Format(time.RFC3339), err)
+	}
+
+	return bundle, err
+}
+
+// retryDeadline returns the time after which retryable errors fail the request
+// permanently: the creation time of the request plus the MaxRetryDuration. If a
+// re-issuance was forced, a new retry window was started at that time and
+// recorded in the CertificateRequestRetryDeadlineAnnotation, the annotation is
+// only trusted in that case.
+func (r *CertificateRequestReconciler) retryDeadline(cr *cmapi.CertificateRequest) time.Time {
+	deadline := cr.CreationTimestamp.Add(r.MaxRetryDuration)
+	if r.ForceReissueAnnotation == "" || cr.Annotations[r.ForceReissueAnnotation+forceReissueObservedSuffix] == "" {
+		return deadline
+	}
+
+	recorded, err := time.Parse(time.RFC3339, cr.Annotations[v1alpha1.CertificateRequestRetryDeadlineAnnotation])
+	if err == nil && recorded.After(deadline) {
+		return recorded
+	}
+	return deadline
+}
+
+// setRetryDeadlineAnnotation records when the request will be failed
+// permanently in the CertificateRequestRetryDeadlineAnnotation, so that users
+// can see how much of the retry budget is left.
+func (r *CertificateRequestReconciler) setRetryDeadlineAnnotation(ctx context.Context, cr *cmapi.CertificateRequest) error {
+	retryDeadline := r.retryDeadline(cr).UTC().Format(time.RFC3339)
+	if cr.Annotations[v1alpha1.CertificateRequestRetryDeadlineAnnotation] == retryDeadline {
+		// The deadline doesn't change between retries, don't patch the
+		// request on every retry.
+		return nil
+	}
+
+	if _, err := applySignerAnnotations(ctx, r.Client, cr, map[string]string{
+		v1alpha1.CertificateRequestRetryDeadlineAnnotation: retryDeadline,
+	}); err != nil {
+		return fmt.Errorf("failed to set the retry deadline annotation: %v", err)
+	}
+	return nil
+}
+
+// resumeRequested returns the value of the CertificateRequestResumeAnnotation
+// and true if the value has been set and has not been handled yet.
+func resumeRequested(cr *cmapi.CertificateRequest) (string, bool) {
+	value := cr.Annotations[v1alpha1.CertificateRequestResumeAnnotation]
+	if value == "" || value == cr.Annotations[v1alpha1.CertificateRequestResumeObservedAnnotation] {
+		return "", false
+	}
+
+	return value, true
+}
+
+// parkForManualReview sets the NeedsManualReview condition on a request for
+// which the Sign function returned a ManualInterventionError. The request is
+// not retried until it is resumed using the CertificateRequestResumeAnnotation.
+func (r *CertificateRequestReconciler) parkForManualReview(
+	logger logr.Logger,
+	ctx context.Context,
+	cr *cmapi.CertificateRequest,
+	manualIntervention signer.ManualInterventionError,
+	crStatusPatch *cmapi.CertificateRequestStatus,
+) (ctrl.Result, *cmapi.CertificateRequestStatus, error) {
+	logger.V(1).Info("CertificateRequest needs a manual review. Parking it.", "reason", manualIntervention.Reason, "message", manualIntervention.Message)
+
+	// A value of the resume annotation that was set before the request was
+	// parked must not resume it, so we record it as handled.
+	if value := cr.Annotations[v1alpha1.CertificateRequestResumeAnnotation]; value != "" {
+		if _, err := applySignerAnnotations(ctx, r.Client, cr, map[string]string{
+			v1alpha1.CertificateRequestResumeObservedAnnotation: value,
+		}); err != nil {
+			return ctrl.Result{}, nil, fmt.Errorf("failed to record the handled resume annotation: %v", err) // retry
+		}
+	}
+
+	reason := manualIntervention.Reason
+	if reason == "" {
+		reason = v1alpha1.CertificateRequestConditionNeedsManualReview
+	}
+
+	conditions.SetCertificateRequestStatusCondition(
+		r.Clock,
+		cr.Status.Conditions,
+		&crStatusPatch.Conditions,
+		v1alpha1.CertificateRequestConditionNeedsManualReview,
+		cmmeta.ConditionTrue,
+		reason,
+		manualIntervention.Message,
+	)
+	conditions.SetCertificateRequestStatusCondition(
+		r.Clock,
+		cr.Status.Conditions,
+		&crStatusPatch.Conditions,
+		cmapi.CertificateRequestConditionReady,
+		cmmeta.ConditionFalse,
+		cmapi.CertificateRequestReasonPending,
+		fmt.Sprintf("CertificateRequest needs a manual review: %s", manualIntervention.Message),
+	)
+	r.EventRecorder.Eventf(cr, corev1.EventTypeWarning, v1alpha1.CertificateRequestConditionNeedsManualReview, "CertificateRequest needs a manual review, set the %q annotation to resume it: %s", v1alpha1.CertificateRequestResumeAnnotation, manualIntervention.Message)
+
+	return ctrl.Result{}, crStatusPatch, nil // done, apply patch
+}
+
 // SetupWithManager sets up the controller with the Manager.
 //
 // It ensures that the Manager scheme has all the types that are needed by this controller.
@@ -448,28 +1412,44 @@ func (r *CertificateRequestReconciler) SetupWithManager(ctx context.Context, mgr
 		return err
 	}
 
+	for _, issuerType := range r.allIssuerTypes() {
+		if err := ValidateIssuerType(mgr.GetScheme(), issuerType); err != nil {
+			return err
+		}
+	}
+
 	if err := r.setIssuersGroupVersionKind(mgr.GetScheme()); err != nil {
 		return err
 	}
 
+	// We are only interested in changes to the non-ready conditions of the
+	// certificaterequest, this also prevents us to get in fast reconcile loop
+	// when setting the status to Pending causing the resource to update, while
+	// we only want to re-reconcile with backoff/ when a resource becomes available.
+	predicates := []predicate.Predicate{
+		predicate.ResourceVersionChangedPredicate{},
+		CertificateRequestPredicate{},
+	}
+	// Resources that have not been approved or denied yet are ignored by the
+	// reconciler, so we also filter out their events. Unless we are responsible
+	// for approving them ourselves.
+	if r.ApproveCertificateRequest == nil {
+		predicates = append(predicates, CertificateRequestApprovedPredicate{})
+	}
+
 	build := ctrl.
 		NewControllerManagedBy(mgr).
 		For(
 			crType,
-			// We are only interested in changes to the non-ready conditions of the
-			// certificaterequest, this also prevents us to get in fast reconcile loop
-			// when setting the status to Pending causing the resource to update, while
-			// we only want to re-reconcile with backoff/ when a resource becomes available.
-			builder.WithPredicates(
-				predicate.ResourceVersionChangedPredicate{},
-				CertificateRequestPredicate{},
-			),
+			builder.WithPredicates(predicates...),
 		)
 
 	// We watch all the issuer types. When an issuer receives a watch event, we
 	// reconcile all the certificate requests that reference that issuer. This
 	// is useful when the certificate request undergoes long backoff retry
 	// periods and wouldn't react quickly to a fix in the issuer configuration.
+	// This includes the creation of an issuer, so certificate requests that were
+	// created before their issuer existed are picked up once it is created.
 	for _, issuerType := range r.allIssuerTypes() {
 		issuerType := issuerType
 		gvk := issuerType.GetObjectKind().GroupVersionKind()
@@ -482,7 +1462,10 @@ func (r *CertificateRequestReconciler) SetupWithManager(ctx context.Context, mgr
 		//
 		// The defaulting logic is based on:
 		// https://github.com/kubernetes-sigs/controller-runtime/blob/30eae58f1b984c1b8139dd9b9f68dd2d530ed429/pkg/controller/controller.go#L138-L144
-		timeout := mgr.GetControllerOptions().CacheSyncTimeout
+		timeout := r.CacheSyncTimeout
+		if timeout == 0 {
+			timeout = mgr.GetControllerOptions().CacheSyncTimeout
+		}
 		if timeout == 0 {
 			timeout = 2 * time.Minute
 		}
@@ -521,6 +1504,31 @@ func (r *CertificateRequestReconciler) SetupWithManager(ctx context.Context, mgr
 		)
 	}
 
+	if r.AuditResyncInterval > 0 {
+		logger := mgr.GetLogger()
+		if r.Logger.GetSink() != nil {
+			logger = r.Logger
+		}
+
+		auditBuild, err := setupAuditResync(mgr, build, logger, "certificaterequest", r.AuditResyncInterval, r.auditResyncObjects)
+		if err != nil {
+			return err
+		}
+		build = auditBuild
+	}
+
+	// The options are set before the PreSetupWithManager hook, so that the
+	// hook can override them.
+	build = build.WithOptions(controllerOptions(r.Logger, crType.GroupVersionKind(), r.CacheSyncTimeout, r.MaxBackoff))
+
+	if r.PreSetupWithManager != nil {
+		err := r.PreSetupWithManager(ctx, crType.GroupVersionKind(), mgr, build)
+		r.PreSetupWithManager = nil // free setup function
+		if err != nil {
+			return err
+		}
+	}
+
 	if controller, err := build.Build(r); err != nil {
 		return err
 	} else if r.PostSetupWithManager != nil {
@@ -531,6 +1539,92 @@ func (r *CertificateRequestReconciler) SetupWithManager(ctx context.Context, mgr
 	return nil
 }
 
+// issuerWasFound returns true if the issuer of the request has been found
+// before, which means that a missing issuer was deleted while the request was
+// being processed. This is tracked by the IssuerNotFound condition: it is
+// False once the issuer has been found and True with the IssuerDeleted reason
+// while a deleted issuer is awaited. A request without the condition (eg. a
+// request that was created by an older version of the controller) is treated
+// as waiting for an issuer that has not been created yet.
+func issuerWasFound(cr *cmapi.CertificateRequest) bool {
+	notFoundCondition := cmutil.GetCertificateRequestCondition(cr, v1alpha1.CertificateRequestConditionIssuerNotFound)
+	return (notFoundCondition != nil) &&
+		((notFoundCondition.Status == cmmeta.ConditionFalse) ||
+			(notFoundCondition.Reason == v1alpha1.CertificateRequestConditionReasonIssuerDeleted))
+}
+
+// handleDeletedIssuer sets the IssuerNotFound condition on a request whose
+// issuer was deleted while the request was being processed. The request stays
+// pending until the IssuerDeletedGracePeriod has passed since the deletion was
+// noticed, afterwards it is failed permanently.
+func (r *CertificateRequestReconciler) handleDeletedIssuer(
+	logger logr.Logger,
+	cr *cmapi.CertificateRequest,
+	notFoundErr error,
+	crStatusPatch *cmapi.CertificateRequestStatus,
+) (ctrl.Result, *cmapi.CertificateRequestStatus, error) {
+	gracePeriod := r.IssuerDeletedGracePeriod
+	if gracePeriod == 0 {
+		gracePeriod = DefaultIssuerDeletedGracePeriod
+	} else if gracePeriod < 0 {
+		gracePeriod = 0
+	}
+
+	notFoundCondition, _ := conditions.SetCertificateRequestStatusCondition(
+		r.Clock,
+		cr.Status.Conditions,
+		&crStatusPatch.Conditions,
+		v1alpha1.CertificateRequestConditionIssuerNotFound,
+		cmmeta.ConditionTrue,
+		v1alpha1.CertificateRequestConditionReasonIssuerDeleted,
+		fmt.Sprintf("%s. The issuer was deleted while the request was being processed.", notFoundErr),
+	)
+
+	deadline := notFoundCondition.LastTransitionTime.Add(gracePeriod)
+	if remaining := deadline.Sub(r.Clock.Now()); remaining > 0 {
+		logger.V(1).Info("Issuer was deleted. Waiting for it to be recreated.", "deadline", deadline)
+		conditions.SetCertificateRequestStatusCondition(
+			r.Clock,
+			cr.Status.Conditions,
+			&crStatusPatch.Conditions,
+			cmapi.CertificateRequestConditionReady,
+			cmmeta.ConditionFalse,
+			cmapi.CertificateRequestReasonPending,
+			fmt.Sprintf("%s. The issuer was deleted, waiting until %s for it to be recreated.", notFoundErr, deadline.UTC().Format(time.RFC3339)),
+		)
+		r.EventRecorder.Eventf(cr, corev1.EventTypeWarning, v1alpha1.CertificateRequestConditionReasonIssuerDeleted, "The issuer was deleted, waiting until %s for it to be recreated", deadline.UTC().Format(time.RFC3339))
+
+		result := ctrl.Result{RequeueAfter: remaining}
+		if interval := r.PendingCertificateRequestResyncInterval; interval > 0 && interval < remaining {
+			result.RequeueAfter = interval // resync backstop
+		}
+		return result, crStatusPatch, nil // done, apply patch
+	}
+
+	logger.V(1).Info("Issuer was deleted and was not recreated within the grace period. Marking as failed.")
+	_, failedAt := conditions.SetCertificateRequestStatusCondition(
+		r.Clock,
+		cr.Status.Conditions,
+		&crStatusPatch.Conditions,
+		cmapi.CertificateRequestConditionReady,
+		cmmeta.ConditionFalse,
+		cmapi.CertificateRequestReasonFailed,
+		fmt.Sprintf("CertificateRequest has failed permanently: %s. The issuer was deleted and was not recreated within %s.", notFoundErr, gracePeriod),
+	)
+	crStatusPatch.FailureTime = failedAt.DeepCopy()
+	r.EventRecorder.Eventf(cr, corev1.EventTypeWarning, v1alpha1.CertificateRequestConditionReasonIssuerDeleted, "The issuer was deleted and was not recreated within %s, not signing the request", gracePeriod)
+	return ctrl.Result{}, crStatusPatch, nil // done, apply patch
+}
+
 func setupCertificateRequestReconcilerScheme(scheme *runtime.Scheme) error {
 	return cmapi.AddToScheme(scheme)
 }
+
+// maxCSRSizeOrDefault returns the configured maximum CSR size, or
+// DefaultMaxCSRSize if it is not set.
+func maxCSRSizeOrDefault(maxCSRSize int) int {
+	if maxCSRSize <= 0 {
+		return DefaultMaxCSRSize
+	}
+	return maxCSRSize
+}
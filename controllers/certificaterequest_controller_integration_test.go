@@ -47,6 +47,7 @@ import (
 	"github.com/cert-manager/issuer-lib/internal/tests/testcontext"
 	"github.com/cert-manager/issuer-lib/internal/tests/testresource"
 	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+	"github.com/cert-manager/issuer-lib/testutil"
 )
 
 func extractIdFromNamespace(t *testing.T, namespace string) int {
@@ -368,12 +369,81 @@ func TestCertificateRequestControllerIntegrationSetCondition(t *testing.T) {
 	require.Equal(t, uint64(3), atomic.LoadUint64(&counter))
 }
 
-func createApprovedCR(t *testing.T, ctx context.Context, kc client.Client, clock clock.PassiveClock, cr *cmapi.CertificateRequest) {
-	t.Helper()
+// TestCertificateRequestControllerIntegrationUnapprovedCertificateRequest runs the
+// CertificateRequestController against a real Kubernetes API server.
+func TestCertificateRequestControllerIntegrationUnapprovedCertificateRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Log(
+		"Tests to show that the CertificateRequestController does not sign or update",
+		"CertificateRequests that have not yet been approved",
+		"and that it signs the CertificateRequest once it is approved",
+	)
+
+	fieldOwner := "cr-unapproved"
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	counter := uint64(0)
+	ctx = setupControllersAPIServerAndClient(t, ctx, kubeClients,
+		func(mgr ctrl.Manager) controllerInterface {
+			return &CertificateRequestReconciler{
+				IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+				ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+				FieldOwner:         fieldOwner,
+				MaxRetryDuration:   time.Minute,
+				EventSource:        kubeutil.NewEventStore(),
+				Client:             mgr.GetClient(),
+				Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+					atomic.AddUint64(&counter, 1)
+					return signer.PEMBundle{
+						ChainPEM: []byte("cert"),
+					}, nil
+				},
+				EventRecorder: record.NewFakeRecorder(100),
+				Clock:         clock.RealClock{},
+			}
+		},
+	)
 
-	require.NoError(t, kc.Create(ctx, cr))
+	crName := types.NamespacedName{
+		Name:      "cr1",
+		Namespace: "unapproved",
+	}
+
+	t.Logf("Creating a namespace: %s", crName.Namespace)
+	createNS(t, ctx, kubeClients.Client, crName.Namespace)
+
+	cr := cmgen.CertificateRequest(
+		crName.Name,
+		cmgen.SetCertificateRequestNamespace(crName.Namespace),
+		cmgen.SetCertificateRequestCSR([]byte("doo")),
+		cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Name:  "issuer-1",
+			Kind:  "SimpleIssuer",
+			Group: api.SchemeGroupVersion.Group,
+		}),
+	)
+
+	t.Log("Creating a Ready Issuer")
+	issuer := createIssuerForCR(t, ctx, kubeClients.Client, cr)
+	markIssuerReady(t, ctx, kubeClients.Client, clock.RealClock{}, fieldOwner, issuer)
+
+	t.Log("Creating the CertificateRequest without approving it")
+	require.NoError(t, kubeClients.Client.Create(ctx, cr))
+
+	t.Log("Checking that the CertificateRequest is not signed or updated while it is not approved")
+	require.Never(t, func() bool {
+		var current cmapi.CertificateRequest
+		require.NoError(t, kubeClients.Client.Get(ctx, crName, &current))
+		return len(current.Status.Conditions) > 0 || atomic.LoadUint64(&counter) > 0
+	}, 2*time.Second, 100*time.Millisecond)
+
+	checkComplete := kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Approving the CertificateRequest")
 	conditions.SetCertificateRequestStatusCondition(
-		clock,
+		clock.RealClock{},
 		cr.Status.Conditions,
 		&cr.Status.Conditions,
 		cmapi.CertificateRequestConditionApproved,
@@ -381,7 +451,146 @@ func createApprovedCR(t *testing.T, ctx context.Context, kc client.Client, clock
 		"ApprovedReason",
 		"ApprovedMessage",
 	)
-	require.NoError(t, kc.Status().Update(ctx, cr))
+	require.NoError(t, kubeClients.Client.Status().Update(ctx, cr))
+	t.Log("Waiting for the controller to marks the CertificateRequest as Ready")
+	err := checkComplete(func(obj runtime.Object) error {
+		readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionTrue) ||
+			(readyCondition.Reason != cmapi.CertificateRequestReasonIssued) ||
+			(readyCondition.Message != "issued") {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+
+	require.Equal(t, uint64(1), atomic.LoadUint64(&counter))
+}
+
+// TestCertificateRequestControllerIntegrationForeignConditionIsPreserved runs the
+// CertificateRequestController against a real Kubernetes API server.
+func TestCertificateRequestControllerIntegrationForeignConditionIsPreserved(t *testing.T) {
+	t.Parallel()
+
+	t.Log(
+		"Tests to show that the CertificateRequestController only manages the conditions it owns",
+		"and that it does not remove conditions that are set by another field manager",
+	)
+
+	fieldOwner := "cr-foreign-condition"
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	ctx = setupControllersAPIServerAndClient(t, ctx, kubeClients,
+		func(mgr ctrl.Manager) controllerInterface {
+			return &CertificateRequestReconciler{
+				IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+				ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+				FieldOwner:         fieldOwner,
+				MaxRetryDuration:   time.Minute,
+				EventSource:        kubeutil.NewEventStore(),
+				Client:             mgr.GetClient(),
+				Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+					return signer.PEMBundle{
+						ChainPEM: []byte("cert"),
+					}, nil
+				},
+				EventRecorder: record.NewFakeRecorder(100),
+				Clock:         clock.RealClock{},
+			}
+		},
+	)
+
+	crName := types.NamespacedName{
+		Name:      "cr1",
+		Namespace: "foreign-condition",
+	}
+
+	t.Logf("Creating a namespace: %s", crName.Namespace)
+	createNS(t, ctx, kubeClients.Client, crName.Namespace)
+
+	cr := cmgen.CertificateRequest(
+		crName.Name,
+		cmgen.SetCertificateRequestNamespace(crName.Namespace),
+		cmgen.SetCertificateRequestCSR([]byte("doo")),
+		cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Name:  "issuer-1",
+			Kind:  "SimpleIssuer",
+			Group: api.SchemeGroupVersion.Group,
+		}),
+	)
+
+	checkComplete := kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Creating & approving the CertificateRequest")
+	createApprovedCR(t, ctx, kubeClients.Client, clock.RealClock{}, cr)
+	t.Log("Waiting for the controller to mark the CertificateRequest as IssuerNotFound")
+	err := checkComplete(func(obj runtime.Object) error {
+		readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Reason != cmapi.CertificateRequestReasonPending) {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+
+	t.Log("Setting a custom condition using another field manager")
+	crStatus := &cmapi.CertificateRequestStatus{}
+	conditions.SetCertificateRequestStatusCondition(
+		clock.RealClock{},
+		crStatus.Conditions,
+		&crStatus.Conditions,
+		"example.com/Foreign",
+		cmmeta.ConditionTrue,
+		"ForeignReason",
+		"Set by another field manager",
+	)
+	crObj, patch, err := ssaclient.GenerateCertificateRequestStatusPatch(crName.Name, crName.Namespace, crStatus)
+	require.NoError(t, err)
+	require.NoError(t, kubeClients.Client.Status().Patch(ctx, &crObj, patch, &client.SubResourcePatchOptions{
+		PatchOptions: client.PatchOptions{
+			FieldManager: "other-field-manager",
+		},
+	}))
+
+	checkComplete = kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Creating a Ready Issuer to trigger the controller to re-reconcile the CertificateRequest")
+	issuer := createIssuerForCR(t, ctx, kubeClients.Client, cr)
+	markIssuerReady(t, ctx, kubeClients.Client, clock.RealClock{}, fieldOwner, issuer)
+	t.Log("Waiting for the controller to marks the CertificateRequest as Ready")
+	err = checkComplete(func(obj runtime.Object) error {
+		readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionTrue) ||
+			(readyCondition.Reason != cmapi.CertificateRequestReasonIssued) {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+
+	t.Log("Checking that the custom condition set by the other field manager is preserved")
+	var current cmapi.CertificateRequest
+	require.NoError(t, kubeClients.Client.Get(ctx, crName, &current))
+	foreignCondition := cmutil.GetCertificateRequestCondition(&current, "example.com/Foreign")
+	require.NotNil(t, foreignCondition)
+	require.Equal(t, cmmeta.ConditionTrue, foreignCondition.Status)
+	require.Equal(t, "ForeignReason", foreignCondition.Reason)
+	require.Equal(t, "Set by another field manager", foreignCondition.Message)
+}
+
+func createApprovedCR(t *testing.T, ctx context.Context, kc client.Client, clock clock.PassiveClock, cr *cmapi.CertificateRequest) {
+	t.Helper()
+
+	require.NoError(t, testutil.CreateApprovedCertificateRequest(ctx, kc, clock, cr))
 }
 
 func createIssuerForCR(t *testing.T, ctx context.Context, kc client.Client, cr *cmapi.CertificateRequest) v1alpha1.Issuer {
@@ -412,6 +621,23 @@ func createIssuerForCR(t *testing.T, ctx context.Context, kc client.Client, cr *
 func markIssuerReady(t *testing.T, ctx context.Context, kc client.Client, clock clock.PassiveClock, fieldOwner string, issuer v1alpha1.Issuer) {
 	t.Helper()
 
+	setIssuerReadyCondition(t, ctx, kc, clock, fieldOwner, issuer, cmmeta.ConditionTrue, v1alpha1.IssuerConditionReasonChecked, "Succeeded checking the issuer")
+}
+
+// setIssuerReadyCondition sets the Ready condition of the issuer using the
+// fieldOwner as field manager.
+func setIssuerReadyCondition(
+	t *testing.T,
+	ctx context.Context,
+	kc client.Client,
+	clock clock.PassiveClock,
+	fieldOwner string,
+	issuer v1alpha1.Issuer,
+	status cmmeta.ConditionStatus,
+	reason, message string,
+) {
+	t.Helper()
+
 	issuerStatus := &v1alpha1.IssuerStatus{}
 	conditions.SetIssuerStatusCondition(
 		clock,
@@ -419,9 +645,9 @@ func markIssuerReady(t *testing.T, ctx context.Context, kc client.Client, clock
 		&issuerStatus.Conditions,
 		issuer.GetGeneration(),
 		cmapi.IssuerConditionReady,
-		cmmeta.ConditionTrue,
-		v1alpha1.IssuerConditionReasonChecked,
-		"Succeeded checking the issuer",
+		status,
+		reason,
+		message,
 	)
 
 	err := kubeutil.SetGroupVersionKind(kc.Scheme(), issuer)
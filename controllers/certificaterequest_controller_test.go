@@ -17,26 +17,33 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
+	cmutil "github.com/cert-manager/cert-manager/pkg/api/util"
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
 	logrtesting "github.com/go-logr/logr/testing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	clocktesting "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/cert-manager/issuer-lib/api/v1alpha1"
@@ -56,11 +63,37 @@ func TestCertificateRequestReconcilerReconcile(t *testing.T) {
 	type testCase struct {
 		name                string
 		sign                signer.Sign
+		afterSign           signer.AfterSign
+		approve             signer.ApproveCertificateRequest
+		checkChain          bool
+		retryIncomplete     bool
+		requireSCT          bool
+		requireExactSANs    bool
+		deduplicateSANs     bool
+		rejectConflicting   bool
+		isPermanent         func(error) bool
+		escalateToIssuer    func(error) bool
+		preserveExistingCrt bool
+		allRetryable        bool
+		maxRequestAge       time.Duration
+		signTimeout         time.Duration
+		maxCSRSize          int
+		issuerDeletedGrace  time.Duration
+		pendingResync       time.Duration
+		formatPending       func(issuerReady *cmapi.IssuerCondition) string
+		forceReissue        string
+		nameConstraints     *signer.NameConstraints
+		quotaCheck          signer.QuotaCheck
+		ignoreIssuer        signer.IgnoreIssuer
+		ignoreCR            signer.IgnoreCertificateRequest
+		defaultUsages       signer.DefaultUsages
+		httpClientProvider  func() *http.Client
 		objects             []client.Object
 		validateError       *errormatch.Matcher
 		expectedResult      reconcile.Result
 		expectedStatusPatch *cmapi.CertificateRequestStatus
 		expectedEvents      []string
+		expectedAnnotations map[string]string
 	}
 
 	randTime := randomTime()
@@ -98,6 +131,17 @@ func TestCertificateRequestReconcilerReconcile(t *testing.T) {
 		),
 	)
 
+	testHTTPClient := &http.Client{Timeout: 5 * time.Second}
+
+	nameConstraintsCSR, _, err := cmgen.CSR(x509.ECDSA, cmgen.SetCSRDNSNames("foo.example.com"))
+	require.NoError(t, err)
+
+	duplicateSANsCSR, _, err := cmgen.CSR(x509.ECDSA, cmgen.SetCSRDNSNames("foo.example.com", "FOO.example.com", "bar.example.com"))
+	require.NoError(t, err)
+
+	conflictingSANsCSR, _, err := cmgen.CSR(x509.ECDSA, cmgen.SetCSRDNSNames("foo.example.com", "10.0.0.1"))
+	require.NoError(t, err)
+
 	cr1 := cmgen.CertificateRequest(
 		"cr1",
 		cmgen.SetCertificateRequestNamespace("ns1"),
@@ -126,6 +170,9 @@ func TestCertificateRequestReconcilerReconcile(t *testing.T) {
 		},
 	)
 
+	errPolicyRejected := errors.New("rejected by policy")
+	errCAUnavailable := errors.New("CA unavailable")
+
 	successSigner := func(cert string) signer.Sign {
 		return func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
 			return signer.PEMBundle{
@@ -134,6 +181,24 @@ func TestCertificateRequestReconcilerReconcile(t *testing.T) {
 		}
 	}
 
+	chain := newTestCertificateChain(t, fakeTime2)
+	notAfterMismatch := fmt.Sprintf(
+		"the NotAfter of the bundle (%s) does not match the NotAfter of the leaf certificate (%s)",
+		fakeTime2.Add(2*time.Hour).UTC().Format(time.RFC3339), fakeTime2.Add(time.Hour).UTC().Format(time.RFC3339),
+	)
+
+	approver := func(approve bool, reason, message string) signer.ApproveCertificateRequest {
+		return func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (bool, string, string, error) {
+			return approve, reason, message, nil
+		}
+	}
+
+	unapprovedCr1 := cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+		cr.Spec.IssuerRef.Name = issuer1.Name
+		cr.Spec.IssuerRef.Kind = issuer1.Kind
+		cr.Status.Conditions = nil
+	})
+
 	tests := []testCase{
 		// NOTE: The IssuerError error cannot be tested in this unit test. It is tested in the
 		// integration test instead.
@@ -164,14 +229,88 @@ func TestCertificateRequestReconcilerReconcile(t *testing.T) {
 			},
 		},
 
-		// Ignore CertificateRequest with an unknown issuerRef kind.
+		// Report a CertificateRequest with our issuerRef group, but a kind that
+		// doesn't match any of the registered issuer types (eg. a typo).
 		{
 			name: "issuer-ref-unknown-kind",
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
-					cr.Spec.IssuerRef.Kind = "unknown-kind"
+					cr.Spec.IssuerRef.Kind = "SimpleIsuer"
+				}),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionUnknownIssuerKind,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonUnknownIssuerKind,
+						Message:            "The issuerRef kind \"SimpleIsuer\" is not a registered kind of the group \"testing.cert-manager.io\", expected one of: SimpleIssuer, SimpleClusterIssuer",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "The issuerRef kind \"SimpleIsuer\" is not a registered kind of the group \"testing.cert-manager.io\", expected one of: SimpleIssuer, SimpleClusterIssuer",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning UnknownIssuerKind The issuerRef kind \"SimpleIsuer\" is not a registered kind of the group \"testing.cert-manager.io\", expected one of: SimpleIssuer, SimpleClusterIssuer",
+			},
+		},
+
+		// Don't emit the event again if the unknown issuerRef kind was already
+		// reported.
+		{
+			name: "issuer-ref-unknown-kind-already-reported",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Kind = "SimpleIsuer"
+					},
+					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               v1alpha1.CertificateRequestConditionUnknownIssuerKind,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonUnknownIssuerKind,
+						Message:            "The issuerRef kind \"SimpleIsuer\" is not a registered kind of the group \"testing.cert-manager.io\", expected one of: SimpleIssuer, SimpleClusterIssuer",
+						LastTransitionTime: &fakeTimeObj1,
+					}),
+				),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionUnknownIssuerKind,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonUnknownIssuerKind,
+						Message:            "The issuerRef kind \"SimpleIsuer\" is not a registered kind of the group \"testing.cert-manager.io\", expected one of: SimpleIssuer, SimpleClusterIssuer",
+						LastTransitionTime: &fakeTimeObj1,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "The issuerRef kind \"SimpleIsuer\" is not a registered kind of the group \"testing.cert-manager.io\", expected one of: SimpleIssuer, SimpleClusterIssuer",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+		},
+
+		// Don't report an unknown issuerRef kind for a CertificateRequest that
+		// is ignored by IgnoreCertificateRequest.
+		{
+			name: "issuer-ref-unknown-kind-ignored",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Kind = "SimpleIsuer"
 				}),
 			},
+			ignoreCR: func(_ context.Context, _ signer.CertificateRequestObject, issuerGvk schema.GroupVersionKind, issuerName types.NamespacedName) (bool, error) {
+				return issuerGvk.Kind == "SimpleIsuer" && issuerName.Namespace == "ns1", nil
+			},
 		},
 
 		// Ignore CertificateRequest which is already Ready.
@@ -202,620 +341,3250 @@ func TestCertificateRequestReconcilerReconcile(t *testing.T) {
 			},
 		},
 
-		// Ignore CertificateRequest which is already Denied.
+		// Reset the failed state if the force re-issue annotation was changed.
 		{
-			name: "already-denied",
+			name:         "force-reissue-resets-failed",
+			forceReissue: "issuer-lib/force-reissue",
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1,
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						"issuer-lib/force-reissue": "1",
+					}),
 					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
 						Type:   cmapi.CertificateRequestConditionReady,
 						Status: cmmeta.ConditionFalse,
-						Reason: cmapi.CertificateRequestReasonDenied,
+						Reason: cmapi.CertificateRequestReasonFailed,
 					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
 				),
+				testutil.SimpleIssuerFrom(issuer1),
 			},
-		},
-
-		// Initialize the CertificateRequest Ready condition if it is missing.
-		{
-			name: "initialize-ready-condition",
-			objects: []client.Object{
-				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
-					removeCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady)
-				}),
+			expectedResult: reconcile.Result{
+				Requeue: true,
 			},
-			expectedResult: reconcile.Result{},
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
 				Conditions: []cmapi.CertificateRequestCondition{
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
 						Status:             cmmeta.ConditionUnknown,
 						Reason:             v1alpha1.CertificateRequestConditionReasonInitializing,
-						Message:            fieldOwner + " has started reconciling this CertificateRequest",
+						Message:            "Re-issuance was forced using the \"issuer-lib/force-reissue\" annotation",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
 			},
+			expectedEvents: []string{
+				"Normal ForceReissue Re-issuance was forced using the \"issuer-lib/force-reissue\" annotation",
+			},
 		},
 
-		// If denied, set Ready condition status to false and reason to denied.
+		// Sign the CertificateRequest again after its failed state was reset and
+		// record that the force re-issue annotation value was handled.
 		{
-			name: "set-ready-denied",
+			name:         "force-reissue-signs-after-reset",
+			forceReissue: "issuer-lib/force-reissue",
+			sign:         successSigner("a-signed-certificate"),
 			objects: []client.Object{
-				cmgen.CertificateRequestFrom(cr1, cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
-					Type:   cmapi.CertificateRequestConditionDenied,
-					Status: cmmeta.ConditionTrue,
-					Reason: "",
-				})),
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						"issuer-lib/force-reissue": "1",
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
 			},
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
 				Conditions: []cmapi.CertificateRequestCondition{
 					{
-						Type:               cmapi.CertificateRequestConditionReady,
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
 						Status:             cmmeta.ConditionFalse,
-						Reason:             cmapi.CertificateRequestReasonDenied,
-						Message:            "The CertificateRequest was denied by an approval controller",
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
 						LastTransitionTime: &fakeTimeObj2,
 					},
-				},
-				FailureTime: &fakeTimeObj2,
-			},
-			expectedEvents: []string{
-				"Normal DetectedDenied Detected that the CR is denied, will update Ready condition",
-			},
-		},
-
-		// If issuer is missing, set Ready condition status to false and reason to pending.
-		{
-			name: "set-ready-pending-missing-issuer",
-			objects: []client.Object{
-				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
-					cr.Spec.IssuerRef.Name = issuer1.Name
-					cr.Spec.IssuerRef.Kind = issuer1.Kind
-				}),
-			},
-			expectedStatusPatch: &cmapi.CertificateRequestStatus{
-				Conditions: []cmapi.CertificateRequestCondition{
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
-						Status:             cmmeta.ConditionFalse,
-						Reason:             cmapi.CertificateRequestReasonPending,
-						Message:            "simpleissuers.testing.cert-manager.io \"issuer-1\" not found. Waiting for it to be created.",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
 			},
 			expectedEvents: []string{
-				"Normal WaitingForIssuerExist Waiting for the issuer to exist",
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+			expectedAnnotations: map[string]string{
+				"issuer-lib/force-reissue":                         "1",
+				"issuer-lib/force-reissue-observed":                "1",
+				v1alpha1.CertificateRequestRetryDeadlineAnnotation: fakeTime2.Add(time.Minute).UTC().Format(time.RFC3339),
 			},
 		},
 
-		// If issuer has no ready condition, set Ready condition status to false and reason to
-		// pending.
+		// A re-issuance that was forced for a request that is older than the
+		// MaxRetryDuration gets a new retry window, starting when the force
+		// re-issue annotation was handled.
 		{
-			name: "set-ready-pending-issuer-has-no-ready-condition",
+			name:         "force-reissue-resets-retry-window",
+			forceReissue: "issuer-lib/force-reissue",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, errors.New("the CA is still unavailable")
+			},
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1,
-					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
-						Name:  issuer1.Name,
-						Group: api.SchemeGroupVersion.Group,
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						"issuer-lib/force-reissue": "1",
 					}),
-				),
-				testutil.SimpleIssuerFrom(issuer1,
-					func(si *api.SimpleIssuer) {
-						si.Status.Conditions = nil
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Hour))
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
 					},
 				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedResult: reconcile.Result{
+				Requeue: true,
 			},
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
 				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
 						Status:             cmmeta.ConditionFalse,
 						Reason:             cmapi.CertificateRequestReasonPending,
-						Message:            "Issuer is not Ready yet. No ready condition found. Waiting for it to become ready.",
+						Message:            "CertificateRequest is not ready yet: the CA is still unavailable",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
 			},
 			expectedEvents: []string{
-				"Normal WaitingForIssuerReady Waiting for the issuer to become ready",
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: the CA is still unavailable",
+			},
+			expectedAnnotations: map[string]string{
+				"issuer-lib/force-reissue":                         "1",
+				"issuer-lib/force-reissue-observed":                "1",
+				v1alpha1.CertificateRequestRetryDeadlineAnnotation: fakeTime2.Add(time.Minute).UTC().Format(time.RFC3339),
 			},
 		},
 
-		// If issuer is not ready, set Ready condition status to false and reason to pending.
+		// The retry deadline annotation only extends the retry window if a
+		// re-issuance was forced.
 		{
-			name: "set-ready-pending-issuer-is-not-ready",
+			name:         "retry-deadline-annotation-ignored-without-force-reissue",
+			forceReissue: "issuer-lib/force-reissue",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, errors.New("the CA is still unavailable")
+			},
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1,
-					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
-						Name:  issuer1.Name,
-						Group: api.SchemeGroupVersion.Group,
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						v1alpha1.CertificateRequestRetryDeadlineAnnotation: fakeTime2.Add(time.Hour).UTC().Format(time.RFC3339),
 					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Hour))
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
 				),
-				testutil.SimpleIssuerFrom(issuer1,
-					testutil.SetSimpleIssuerStatusCondition(
-						fakeClock1,
-						cmapi.IssuerConditionReady,
-						cmmeta.ConditionFalse,
-						"[REASON]",
-						"[MESSAGE]",
-					),
-				),
+				testutil.SimpleIssuerFrom(issuer1),
 			},
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
 				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
 						Status:             cmmeta.ConditionFalse,
-						Reason:             cmapi.CertificateRequestReasonPending,
-						Message:            "Issuer is not Ready yet. Current ready condition is \"[REASON]\": [MESSAGE]. Waiting for it to become ready.",
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: the CA is still unavailable",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
+				FailureTime: &fakeTimeObj2,
 			},
 			expectedEvents: []string{
-				"Normal WaitingForIssuerReady Waiting for the issuer to become ready",
+				"Warning PermanentError Failed permanently to sign CertificateRequest: the CA is still unavailable",
 			},
 		},
 
-		// If issuer's ready condition is outdated, set Ready condition status to false and reason
-		// to pending.
+		// Ignore a Failed CertificateRequest if the force re-issue annotation
+		// value has already been handled.
 		{
-			name: "set-ready-pending-issuer-ready-outdated",
+			name:         "force-reissue-already-handled",
+			forceReissue: "issuer-lib/force-reissue",
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1,
-					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
-						Name:  issuer1.Name,
-						Group: api.SchemeGroupVersion.Group,
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						"issuer-lib/force-reissue":          "1",
+						"issuer-lib/force-reissue-observed": "1",
 					}),
-				),
-				testutil.SimpleIssuerFrom(issuer1,
-					testutil.SetSimpleIssuerGeneration(issuer1.Generation+1),
-				),
-			},
-			expectedStatusPatch: &cmapi.CertificateRequestStatus{
-				Conditions: []cmapi.CertificateRequestCondition{
-					{
-						Type:               cmapi.CertificateRequestConditionReady,
-						Status:             cmmeta.ConditionFalse,
-						Reason:             cmapi.CertificateRequestReasonPending,
-						Message:            "Issuer is not Ready yet. Current ready condition is outdated. Waiting for it to become ready.",
-						LastTransitionTime: &fakeTimeObj2,
+					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:   cmapi.CertificateRequestConditionReady,
+						Status: cmmeta.ConditionFalse,
+						Reason: cmapi.CertificateRequestReasonFailed,
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
 					},
-				},
-			},
-			expectedEvents: []string{
-				"Normal WaitingForIssuerReady Waiting for the issuer to become ready",
+				),
+				testutil.SimpleIssuerFrom(issuer1),
 			},
 		},
 
-		// If the sign function returns an error & it's too late for a retry, set the Ready
-		// condition to Failed.
+		// Park the CertificateRequest if the signer requires a manual
+		// intervention, it is not retried automatically.
 		{
-			name: "timeout-permanent-error",
-			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
-				return signer.PEMBundle{}, fmt.Errorf("a specific error")
+			name: "manual-intervention-parks-request",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.ManualInterventionError{
+					Reason:  "FlaggedByCA",
+					Message: "the CA flagged the request for a review",
+				}
 			},
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1,
-					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
-						Name:  issuer1.Name,
-						Group: api.SchemeGroupVersion.Group,
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						v1alpha1.CertificateRequestResumeAnnotation: "1",
 					}),
 					func(cr *cmapi.CertificateRequest) {
-						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
 					},
 				),
 				testutil.SimpleIssuerFrom(issuer1),
 			},
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
 				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               v1alpha1.CertificateRequestConditionNeedsManualReview,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "FlaggedByCA",
+						Message:            "the CA flagged the request for a review",
+						LastTransitionTime: &fakeTimeObj2,
+					},
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
 						Status:             cmmeta.ConditionFalse,
-						Reason:             cmapi.CertificateRequestReasonFailed,
-						Message:            "CertificateRequest has failed permanently: a specific error",
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest needs a manual review: the CA flagged the request for a review",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
-				FailureTime: &fakeTimeObj2,
 			},
 			expectedEvents: []string{
-				"Warning PermanentError Failed permanently to sign CertificateRequest: a specific error",
+				"Warning NeedsManualReview CertificateRequest needs a manual review, set the \"issuer-lib.cert-manager.io/resume\" annotation to resume it: the CA flagged the request for a review",
+			},
+			expectedAnnotations: map[string]string{
+				v1alpha1.CertificateRequestResumeAnnotation:         "1",
+				v1alpha1.CertificateRequestResumeObservedAnnotation: "1",
 			},
 		},
 
-		// If the sign function returns a Pending error, set the Ready condition to Pending (even if
-		// the MaxRetryDuration has been exceeded).
+		// Ignore a parked CertificateRequest until it is resumed.
 		{
-			name: "retry-on-pending-error",
-			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
-				return signer.PEMBundle{}, signer.PendingError{Err: fmt.Errorf("pending error")}
+			name: "manual-intervention-stays-parked",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, fmt.Errorf("sign should not be called")
 			},
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1,
-					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
-						Name:  issuer1.Name,
-						Group: api.SchemeGroupVersion.Group,
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						v1alpha1.CertificateRequestResumeAnnotation:         "1",
+						v1alpha1.CertificateRequestResumeObservedAnnotation: "1",
 					}),
-					func(cr *cmapi.CertificateRequest) {
+					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:   v1alpha1.CertificateRequestConditionNeedsManualReview,
+						Status: cmmeta.ConditionTrue,
+						Reason: "FlaggedByCA",
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+		},
+
+		// Sign a parked CertificateRequest again once the resume annotation
+		// was changed, and record that its value was handled.
+		{
+			name: "manual-intervention-resumed",
+			sign: successSigner("a-signed-certificate"),
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						v1alpha1.CertificateRequestResumeAnnotation:         "2",
+						v1alpha1.CertificateRequestResumeObservedAnnotation: "1",
+					}),
+					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               v1alpha1.CertificateRequestConditionNeedsManualReview,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "FlaggedByCA",
+						LastTransitionTime: &fakeTimeObj1,
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionNeedsManualReview,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonResumed,
+						Message:            "CertificateRequest was resumed using the \"issuer-lib.cert-manager.io/resume\" annotation",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal Resumed CertificateRequest was resumed using the \"issuer-lib.cert-manager.io/resume\" annotation",
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+			expectedAnnotations: map[string]string{
+				v1alpha1.CertificateRequestResumeAnnotation:         "2",
+				v1alpha1.CertificateRequestResumeObservedAnnotation: "2",
+			},
+		},
+
+		// Ignore CertificateRequest which is already Denied.
+		{
+			name: "already-denied",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:   cmapi.CertificateRequestConditionReady,
+						Status: cmmeta.ConditionFalse,
+						Reason: cmapi.CertificateRequestReasonDenied,
+					}),
+				),
+			},
+		},
+
+		// Initialize the CertificateRequest Ready condition if it is missing.
+		{
+			name: "initialize-ready-condition",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					removeCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady)
+				}),
+			},
+			expectedResult: reconcile.Result{},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionUnknown,
+						Reason:             v1alpha1.CertificateRequestConditionReasonInitializing,
+						Message:            fieldOwner + " has started reconciling this CertificateRequest",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+		},
+
+		// If denied, set Ready condition status to false and reason to denied.
+		{
+			name: "set-ready-denied",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+					Type:   cmapi.CertificateRequestConditionDenied,
+					Status: cmmeta.ConditionTrue,
+					Reason: "",
+				})),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonDenied,
+						Message:            "The CertificateRequest was denied by an approval controller",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Normal DetectedDenied Detected that the CR is denied, will update Ready condition",
+			},
+		},
+
+		// If issuer is missing, set Ready condition status to false and reason to pending.
+		{
+			name: "set-ready-pending-missing-issuer",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+				}),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerNotCreated,
+						Message:            "simpleissuers.testing.cert-manager.io \"issuer-1\" not found. Waiting for it to be created.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "simpleissuers.testing.cert-manager.io \"issuer-1\" not found. Waiting for it to be created.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal WaitingForIssuerExist Waiting for the issuer to exist",
+			},
+		},
+
+		// A pending request without the IssuerNotFound condition (eg. created by an
+		// older version of the controller) waits for its issuer to be created, the
+		// issuer is not treated as deleted.
+		{
+			name: "set-ready-pending-missing-issuer-not-recorded-as-found",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "simpleissuers.testing.cert-manager.io \"issuer-1\" not found. Waiting for it to be created.",
+						LastTransitionTime: &fakeTimeObj1,
+					}),
+				),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerNotCreated,
+						Message:            "simpleissuers.testing.cert-manager.io \"issuer-1\" not found. Waiting for it to be created.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "simpleissuers.testing.cert-manager.io \"issuer-1\" not found. Waiting for it to be created.",
+						LastTransitionTime: &fakeTimeObj1,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal WaitingForIssuerExist Waiting for the issuer to exist",
+			},
+		},
+
+		// If the issuer is deleted while the request is pending, set the IssuerNotFound
+		// condition and wait for the grace period.
+		{
+			name: "set-ready-pending-issuer-deleted",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "Issuer is not Ready yet. No ready condition found. Waiting for it to become ready.",
+						LastTransitionTime: &fakeTimeObj1,
+					}),
+					cmgen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj1,
+					}),
+				),
+			},
+			expectedResult: reconcile.Result{
+				RequeueAfter: DefaultIssuerDeletedGracePeriod,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerDeleted,
+						Message:            "simpleissuers.testing.cert-manager.io \"issuer-1\" not found. The issuer was deleted while the request was being processed.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            fmt.Sprintf("simpleissuers.testing.cert-manager.io \"issuer-1\" not found. The issuer was deleted, waiting until %s for it to be recreated.", fakeTime2.Add(DefaultIssuerDeletedGracePeriod).UTC().Format(time.RFC3339)),
+						LastTransitionTime: &fakeTimeObj1,
+					},
+				},
+			},
+			expectedEvents: []string{
+				fmt.Sprintf("Warning IssuerDeleted The issuer was deleted, waiting until %s for it to be recreated", fakeTime2.Add(DefaultIssuerDeletedGracePeriod).UTC().Format(time.RFC3339)),
+			},
+		},
+
+		// If the issuer is not recreated within the grace period, fail the request.
+		{
+			name: "set-ready-failed-issuer-deleted-grace-period-passed",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "[message]",
+						LastTransitionTime: &fakeTimeObj1,
+					}),
+					cmgen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerDeleted,
+						Message:            "[message]",
+						LastTransitionTime: &fakeTimeObj1,
+					}),
+				),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerDeleted,
+						Message:            "simpleissuers.testing.cert-manager.io \"issuer-1\" not found. The issuer was deleted while the request was being processed.",
+						LastTransitionTime: &fakeTimeObj1,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: simpleissuers.testing.cert-manager.io \"issuer-1\" not found. The issuer was deleted and was not recreated within 1h0m0s.",
+						LastTransitionTime: &fakeTimeObj1,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning IssuerDeleted The issuer was deleted and was not recreated within 1h0m0s, not signing the request",
+			},
+		},
+
+		// If the grace period is negative, fail the request as soon as the issuer
+		// deletion is noticed.
+		{
+			name:               "set-ready-failed-issuer-deleted-no-grace-period",
+			issuerDeletedGrace: -1,
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "[message]",
+						LastTransitionTime: &fakeTimeObj1,
+					}),
+					cmgen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj1,
+					}),
+				),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerDeleted,
+						Message:            "simpleissuers.testing.cert-manager.io \"issuer-1\" not found. The issuer was deleted while the request was being processed.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: simpleissuers.testing.cert-manager.io \"issuer-1\" not found. The issuer was deleted and was not recreated within 0s.",
+						LastTransitionTime: &fakeTimeObj1,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning IssuerDeleted The issuer was deleted and was not recreated within 0s, not signing the request",
+			},
+		},
+
+		// If issuer has no ready condition, set Ready condition status to false and reason to
+		// pending.
+		{
+			name: "set-ready-pending-issuer-has-no-ready-condition",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1,
+					func(si *api.SimpleIssuer) {
+						si.Status.Conditions = nil
+					},
+				),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "Issuer is not Ready yet. No ready condition found. Waiting for it to become ready.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal WaitingForIssuerReady Waiting for the issuer to become ready",
+			},
+		},
+
+		// If the resync interval is set, requeue the pending CertificateRequest in
+		// case the issuer event that should trigger it is missed.
+		{
+			name:          "set-ready-pending-issuer-has-no-ready-condition-resync",
+			pendingResync: 10 * time.Minute,
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1,
+					func(si *api.SimpleIssuer) {
+						si.Status.Conditions = nil
+					},
+				),
+			},
+			expectedResult: reconcile.Result{
+				RequeueAfter: 10 * time.Minute,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "Issuer is not Ready yet. No ready condition found. Waiting for it to become ready.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal WaitingForIssuerReady Waiting for the issuer to become ready",
+			},
+		},
+
+		// If issuer is not ready, set Ready condition status to false and reason to pending.
+		{
+			name: "set-ready-pending-issuer-is-not-ready",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionFalse,
+						"[REASON]",
+						"[MESSAGE]",
+					),
+				),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "Issuer is not Ready yet. Current ready condition is \"[REASON]\": [MESSAGE]. Waiting for it to become ready.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal WaitingForIssuerReady Waiting for the issuer to become ready",
+			},
+		},
+
+		// If a FormatPendingMessage function is configured, use it to create the
+		// message of the Pending Ready condition.
+		{
+			name: "set-ready-pending-issuer-is-not-ready-custom-message",
+			formatPending: func(issuerReady *cmapi.IssuerCondition) string {
+				return fmt.Sprintf("Waiting for issuer (%s)", issuerReady.Reason)
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionFalse,
+						"[REASON]",
+						"[MESSAGE]",
+					),
+				),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "Waiting for issuer ([REASON])",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal WaitingForIssuerReady Waiting for the issuer to become ready",
+			},
+		},
+
+		// If issuer's ready condition is outdated, set Ready condition status to false and reason
+		// to pending.
+		{
+			name: "set-ready-pending-issuer-ready-outdated",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(issuer1.Generation+1),
+				),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "Issuer is not Ready yet. Current ready condition is outdated. Waiting for it to become ready.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal WaitingForIssuerReady Waiting for the issuer to become ready",
+			},
+		},
+
+		// The HTTP client of the HTTPClientProvider is passed to the Sign function
+		// using the context.
+		{
+			name: "success-http-client-from-context",
+			sign: func(ctx context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				if signer.HTTPClientFromContext(ctx) != testHTTPClient {
+					return signer.PEMBundle{}, signer.PermanentError{Err: fmt.Errorf("unexpected HTTP client")}
+				}
+				return signer.PEMBundle{ChainPEM: []byte("a-signed-certificate")}, nil
+			},
+			httpClientProvider: func() *http.Client { return testHTTPClient },
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+				}),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// The APIReader is passed to the Sign function using the context, so it
+		// can read objects in other namespaces (eg. a ConfigMap of the CA).
+		{
+			name: "success-api-reader-from-context",
+			sign: func(ctx context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				reader := signer.APIReaderFromContext(ctx)
+				if reader == nil {
+					return signer.PEMBundle{}, signer.PermanentError{Err: fmt.Errorf("no API reader")}
+				}
+				var configMap corev1.ConfigMap
+				if err := reader.Get(ctx, types.NamespacedName{Namespace: "ca-namespace", Name: "ca-config"}, &configMap); err != nil {
+					return signer.PEMBundle{}, err
+				}
+				return signer.PEMBundle{ChainPEM: []byte(configMap.Data["certificate"])}, nil
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+				}),
+				testutil.SimpleIssuerFrom(issuer1),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ca-namespace", Name: "ca-config"},
+					Data:       map[string]string{"certificate": "a-signed-certificate"},
+				},
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// If the request contains a subjectAltName that is not allowed by the name
+		// constraints, set the Ready condition to Failed without calling Sign.
+		{
+			name: "name-constraints-violation",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, fmt.Errorf("sign should not be called")
+			},
+			nameConstraints: &signer.NameConstraints{
+				PermittedDNSNames: []string{`.*\.internal\.example\.com`},
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.SetCertificateRequestCSR(nameConstraintsCSR),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: DNS name \"foo.example.com\" is not permitted by the name constraints",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to sign CertificateRequest: DNS name \"foo.example.com\" is not permitted by the name constraints",
+			},
+		},
+
+		// Duplicate SANs are removed from the request that is passed to Sign.
+		{
+			name: "deduplicate-sans",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				template, _, _, err := cr.GetRequest()
+				if err != nil {
+					return signer.PEMBundle{}, err
+				}
+				if len(template.DNSNames) != 2 {
+					return signer.PEMBundle{}, signer.PermanentError{Err: fmt.Errorf("unexpected DNS names: %v", template.DNSNames)}
+				}
+				return signer.PEMBundle{ChainPEM: []byte("a-signed-certificate")}, nil
+			},
+			deduplicateSANs: true,
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.SetCertificateRequestCSR(duplicateSANsCSR),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal SANsDeduplicated Removed duplicate SANs from the request before signing: DNS:FOO.example.com",
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// A request with conflicting SANs is failed permanently without calling
+		// Sign.
+		{
+			name: "reject-conflicting-sans",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, fmt.Errorf("sign should not be called")
+			},
+			rejectConflicting: true,
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.SetCertificateRequestCSR(conflictingSANsCSR),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: DNS name \"10.0.0.1\" is an IP address, request it as an IP address SAN instead",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to sign CertificateRequest: DNS name \"10.0.0.1\" is an IP address, request it as an IP address SAN instead",
+			},
+		},
+
+		// A Sign error that is classified as permanent by the IsPermanent
+		// function fails the request, without it wrapping a PermanentError.
+		{
+			name: "is-permanent-custom-sentinel",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, fmt.Errorf("backend: %w", errPolicyRejected)
+			},
+			isPermanent: func(err error) bool {
+				return errors.Is(err, errPolicyRejected)
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+				}),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: backend: rejected by policy",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to sign CertificateRequest: backend: rejected by policy",
+			},
+		},
+
+		// A PermanentError is still permanent if the IsPermanent function does
+		// not classify it as permanent.
+		{
+			name: "is-permanent-keeps-permanent-error",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.PermanentError{Err: fmt.Errorf("invalid CSR")}
+			},
+			isPermanent: func(err error) bool {
+				return errors.Is(err, errPolicyRejected)
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+				}),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: invalid CSR",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to sign CertificateRequest: invalid CSR",
+			},
+		},
+
+		// If all subjectAltNames are allowed by the name constraints, sign the request.
+		{
+			name: "name-constraints-allowed",
+			sign: successSigner("a-signed-certificate"),
+			nameConstraints: &signer.NameConstraints{
+				PermittedDNSNames: []string{`.*\.example\.com`},
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.SetCertificateRequestCSR(nameConstraintsCSR),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// The DefaultUsages of the issuer are used for a request that doesn't
+		// specify any usages.
+		{
+			name: "success-default-usages",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				template, _, _, err := cr.GetRequest()
+				if err != nil {
+					return signer.PEMBundle{}, err
+				}
+				if template.KeyUsage != x509.KeyUsageDigitalSignature ||
+					len(template.ExtKeyUsage) != 1 || template.ExtKeyUsage[0] != x509.ExtKeyUsageServerAuth {
+					return signer.PEMBundle{}, signer.PermanentError{Err: fmt.Errorf("unexpected usages")}
+				}
+				return signer.PEMBundle{ChainPEM: []byte("a-signed-certificate")}, nil
+			},
+			defaultUsages: func(_ v1alpha1.Issuer) (x509.KeyUsage, []x509.ExtKeyUsage) {
+				return x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.SetCertificateRequestCSR(nameConstraintsCSR),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// Ignore the CertificateRequest if its issuer is owned by a different
+		// controller.
+		{
+			name:         "ignore-issuer-owned-by-other-controller",
+			sign:         successSigner("a-signed-certificate"),
+			ignoreIssuer: signer.IssuerOwnedBy("example.com/controller", "blue"),
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1, func(si *api.SimpleIssuer) {
+					si.Labels = map[string]string{"example.com/controller": "green"}
+				}),
+			},
+			expectedStatusPatch: nil,
+		},
+
+		// Emit a Warning event for each warning returned by a successful Sign,
+		// the CertificateRequest is still marked as issued.
+		{
+			name: "success-sign-warnings",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{
+					ChainPEM: []byte("a-signed-certificate"),
+					Warnings: []string{
+						"The requested duration was clamped to 90 days",
+						"The deprecated profile \"legacy\" was used",
+					},
+				}, nil
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning SignWarning The requested duration was clamped to 90 days",
+				"Warning SignWarning The deprecated profile \"legacy\" was used",
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// The labels and annotations that cert-manager copies from the Certificate
+		// to the CertificateRequest are available to the sign function.
+		{
+			name: "success-sign-sees-certificate-metadata",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				if cr.GetAnnotations()["example.com/tenant-id"] != "tenant-1" ||
+					cr.GetAnnotations()[cmapi.CertificateNameKey] != "certificate-1" ||
+					cr.GetLabels()["example.com/team"] != "team-1" {
+					return signer.PEMBundle{}, signer.PermanentError{Err: fmt.Errorf("missing certificate metadata")}
+				}
+				return signer.PEMBundle{ChainPEM: []byte("a-signed-certificate")}, nil
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						"example.com/tenant-id":  "tenant-1",
+						cmapi.CertificateNameKey: "certificate-1",
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.Labels = map[string]string{"example.com/team": "team-1"}
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// If the sign function returns an error & it's too late for a retry, set the Ready
+		// condition to Failed.
+		{
+			name: "timeout-permanent-error",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, fmt.Errorf("a specific error")
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: a specific error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to sign CertificateRequest: a specific error",
+			},
+		},
+
+		// If the deadline requested by the client using the max-wait annotation
+		// passes while Sign is running, the Sign context is cancelled and the
+		// request is failed permanently.
+		{
+			name: "max-wait-deadline-exceeded-during-sign",
+			sign: func(ctx context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				<-ctx.Done()
+				return signer.PEMBundle{}, ctx.Err()
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						v1alpha1.CertificateRequestMaxWaitAnnotation: "30050ms",
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-30 * time.Second))
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            fmt.Sprintf("CertificateRequest has failed permanently: the request deadline was exceeded: the client requested the certificate before %s: context deadline exceeded", fakeTime2.UTC().Format(time.RFC3339)),
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				fmt.Sprintf("Warning RequestDeadlineExceeded Failed permanently to sign CertificateRequest: the request deadline was exceeded: the client requested the certificate before %s: context deadline exceeded", fakeTime2.UTC().Format(time.RFC3339)),
+			},
+		},
+
+		// If the deadline requested using the max-wait annotation has already
+		// passed, the request is failed permanently without calling Sign.
+		{
+			name: "max-wait-deadline-already-passed",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, fmt.Errorf("sign should not be called")
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						v1alpha1.CertificateRequestMaxWaitAnnotation: "10s",
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-30 * time.Second))
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            fmt.Sprintf("CertificateRequest has failed permanently: the request deadline was exceeded: the client requested the certificate before %s", fakeTime2.Add(-20*time.Second).UTC().Format(time.RFC3339)),
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				fmt.Sprintf("Warning RequestDeadlineExceeded Failed permanently to sign CertificateRequest: the request deadline was exceeded: the client requested the certificate before %s", fakeTime2.Add(-20*time.Second).UTC().Format(time.RFC3339)),
+			},
+		},
+
+		// If the deadline requested using the max-wait annotation passes while
+		// the request waits for its issuer to become ready, the request is failed
+		// permanently.
+		{
+			name: "max-wait-deadline-passed-while-issuer-not-ready",
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						v1alpha1.CertificateRequestMaxWaitAnnotation: "10s",
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-30 * time.Second))
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionFalse,
+						"[REASON]",
+						"[MESSAGE]",
+					),
+				),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            fmt.Sprintf("CertificateRequest has failed permanently: the request deadline was exceeded: the client requested the certificate before %s", fakeTime2.Add(-20*time.Second).UTC().Format(time.RFC3339)),
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				fmt.Sprintf("Warning RequestDeadlineExceeded Failed permanently to sign CertificateRequest: the request deadline was exceeded: the client requested the certificate before %s", fakeTime2.Add(-20*time.Second).UTC().Format(time.RFC3339)),
+			},
+		},
+
+		// A request that waits for a missing issuer is requeued at the deadline
+		// requested using the max-wait annotation, if that comes before the
+		// resync interval.
+		{
+			name:          "max-wait-requeue-at-deadline-while-issuer-missing",
+			pendingResync: 10 * time.Minute,
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						v1alpha1.CertificateRequestMaxWaitAnnotation: "1m",
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-30 * time.Second))
+					},
+				),
+			},
+			expectedResult: reconcile.Result{
+				RequeueAfter: 30 * time.Second,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerNotCreated,
+						Message:            "simpleissuers.testing.cert-manager.io \"issuer-1\" not found. Waiting for it to be created.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "simpleissuers.testing.cert-manager.io \"issuer-1\" not found. Waiting for it to be created.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal WaitingForIssuerExist Waiting for the issuer to exist",
+			},
+		},
+
+		// The deadline requested using the max-wait annotation is capped by the
+		// SignTimeout. If only the SignTimeout expires, the request is retried.
+		{
+			name:        "max-wait-capped-by-sign-timeout",
+			signTimeout: 10 * time.Millisecond,
+			sign: func(ctx context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				<-ctx.Done()
+				return signer.PEMBundle{}, ctx.Err()
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						v1alpha1.CertificateRequestMaxWaitAnnotation: "1h",
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-30 * time.Second))
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedResult: reconcile.Result{
+				Requeue: true,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: context deadline exceeded",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: context deadline exceeded",
+			},
+		},
+
+		// If the sign function returns a Pending error, set the Ready condition to Pending (even if
+		// the MaxRetryDuration has been exceeded).
+		{
+			name: "retry-on-pending-error",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.PendingError{Err: fmt.Errorf("pending error")}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			// instead of returning an error, we trigger a new reconciliation by setting requeue=true
+			validateError: errormatch.NoError(),
+			expectedResult: reconcile.Result{
+				Requeue: true,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: pending error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: pending error",
+			},
+		},
+
+		// If the sign function returns a Pending error with a RequeueAfter duration, requeue the
+		// request after that duration instead of using exponential backoff.
+		{
+			name: "retry-on-pending-error-requeue-after",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.PendingError{Err: fmt.Errorf("pending error"), RequeueAfter: 90 * time.Second}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			validateError: errormatch.NoError(),
+			expectedResult: reconcile.Result{
+				RequeueAfter: 90 * time.Second,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: pending error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: pending error",
+			},
+		},
+
+		// If the sign function returns an SetCertificateRequestConditionError error with a condition
+		// type that is *not present* in the status, the new condition is *added* to the
+		// CertificateRequest.
+		// Additionally, if the error wrapped by SetCertificateRequestConditionError is not one of the
+		// supported 'signer API' errors an we still *have time left* to retry, set the Ready
+		// condition to *Pending*.
+		{
+			name: "error-set-certificate-request-condition-should-add-new-condition-and-retry",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
+					Err:           fmt.Errorf("test error"),
+					ConditionType: "[condition type]",
+					Status:        cmmeta.ConditionTrue,
+					Reason:        "[reason]",
+				}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = fakeTimeObj2
+					},
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			// no error should be returned because the reconciliation should be triggered
+			// when the custom condition is added
+			validateError: errormatch.NoError(),
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               "[condition type]",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "[reason]",
+						Message:            "test error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: test error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: test error",
+			},
+		},
+
+		// If the quota check fails, set the QuotaExceeded condition and retry (even if the
+		// MaxRetryDuration has been exceeded), without calling the sign function.
+		{
+			name: "quota-exceeded",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, fmt.Errorf("sign should not be called")
+			},
+			quotaCheck: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) error {
+				return fmt.Errorf("namespace ns1 already got 10 certificates today")
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
+					},
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			validateError: errormatch.NoError(),
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               v1alpha1.CertificateRequestConditionQuotaExceeded,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonQuotaExceeded,
+						Message:            "quota exceeded: namespace ns1 already got 10 certificates today",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: quota exceeded: namespace ns1 already got 10 certificates today",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: quota exceeded: namespace ns1 already got 10 certificates today",
+			},
+		},
+
+		// If the quota check returns a Pending error with a RequeueAfter duration, the request
+		// is retried after that duration (eg. at the start of the next quota window).
+		{
+			name: "quota-exceeded-requeue-after",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, fmt.Errorf("sign should not be called")
+			},
+			quotaCheck: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) error {
+				return signer.PendingError{Err: fmt.Errorf("daily quota reached"), RequeueAfter: time.Hour}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = fakeTimeObj2
+					},
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			validateError: errormatch.NoError(),
+			expectedResult: reconcile.Result{
+				RequeueAfter: time.Hour,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               v1alpha1.CertificateRequestConditionQuotaExceeded,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonQuotaExceeded,
+						Message:            "quota exceeded: daily quota reached",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: quota exceeded: daily quota reached",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: quota exceeded: daily quota reached",
+			},
+		},
+
+		// If the sign function returns an SetCertificateRequestConditionError error with a condition
+		// type that is *already present* in the status, the existing condition is *updated* with
+		// the values specified in the error.
+		// Additionally, if the error wrapped by SetCertificateRequestConditionError is not one of the
+		// supported 'signer API' errors an we still *have time left* to retry, set the Ready
+		// condition to *Pending*.
+		{
+			name: "error-set-certificate-request-condition-should-update-existing-condition-and-retry",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
+					Err:           fmt.Errorf("test error2"),
+					ConditionType: "[condition type]",
+					Status:        cmmeta.ConditionTrue,
+					Reason:        "[reason]",
+				}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = fakeTimeObj2
+					},
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					cmgen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               "[condition type]",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "[reason]",
+						Message:            "test error",
+						LastTransitionTime: &fakeTimeObj2,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			// instead of returning an error, we trigger a new reconciliation by setting requeue=true
+			validateError: errormatch.NoError(),
+			expectedResult: reconcile.Result{
+				Requeue: true,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               "[condition type]",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "[reason]",
+						Message:            "test error2",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: test error2",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: test error2",
+			},
+		},
+
+		// If the sign function returns an SetCertificateRequestConditionError error with a condition
+		// type that is *not present* in the status, the new condition is *added* to the
+		// CertificateRequest.
+		// Additionally, if the error wrapped by SetCertificateRequestConditionError is not one of the
+		// supported 'signer API' errors an we have *no time left* to retry, set the Ready condition
+		// to *Failed*.
+		{
+			name: "error-set-certificate-request-condition-should-add-new-condition-and-timeout",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
+					Err:           fmt.Errorf("test error"),
+					ConditionType: "[condition type]",
+					Status:        cmmeta.ConditionTrue,
+					Reason:        "[reason]",
+				}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			// no error should be returned because the reconciliation should be triggered when the
+			// custom condition is added
+			validateError: errormatch.NoError(),
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               "[condition type]",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "[reason]",
+						Message:            "test error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: test error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to sign CertificateRequest: test error",
+			},
+		},
+
+		// If the sign function returns an SetCertificateRequestConditionError error with a condition
+		// type that is *already present* in the status, the existing condition is *updated* with
+		// the values specified in the error.
+		// Additionally, if the error wrapped by SetCertificateRequestConditionError is not one of the
+		// supported 'signer API' errors an we have *no time left* to retry, set the Ready condition
+		// to *Failed*.
+		{
+			name: "error-set-certificate-request-condition-should-update-existing-condition-and-timeout",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
+					Err:           fmt.Errorf("test error2"),
+					ConditionType: "[condition type]",
+					Status:        cmmeta.ConditionTrue,
+					Reason:        "[reason]",
+				}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
+					},
+					cmgen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               "[condition type]",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "[reason]",
+						Message:            "test error",
+						LastTransitionTime: &fakeTimeObj1,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			// since we got into a permanent failure state, we should not return an error
+			validateError: errormatch.NoError(),
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               "[condition type]",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "[reason]",
+						Message:            "test error2",
+						LastTransitionTime: &fakeTimeObj1, // since the status is not updated, the LastTransitionTime is not updated either
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: test error2",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to sign CertificateRequest: test error2",
+			},
+		},
+
+		// If the sign function returns an SetCertificateRequestConditionError, the specified
+		// conditions value is updated/ added to the CertificateRequest status.
+		// Additionally, if the error wrapped by SetCertificateRequestConditionError is a PendingError
+		// error, the Ready condition is set to Pending (even if the MaxRetryDuration has been
+		// exceeded).
+		{
+			name: "error-set-certificate-request-condition-should-not-timeout-if-pending",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
+					Err:           signer.PendingError{Err: fmt.Errorf("test error")},
+					ConditionType: "[condition type]",
+					Status:        cmmeta.ConditionTrue,
+					Reason:        "[reason]",
+				}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+					func(cr *cmapi.CertificateRequest) {
 						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
 					},
-				),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			// no error should be returned because the reconciliation should be triggered
+			// when the custom condition is added
+			validateError: errormatch.NoError(),
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               "[condition type]",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "[reason]",
+						Message:            "test error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: test error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: test error",
+			},
+		},
+
+		// If the sign function returns an SetCertificateRequestConditionError, the specified
+		// conditions value is updated/ added to the CertificateRequest status.
+		// Additionally, if the error wrapped by SetCertificateRequestConditionError is a PendingError
+		// error, the Ready condition is set to Failed (even if the MaxRetryDuration has NOT been
+		// exceeded).
+		{
+			name: "error-set-certificate-request-condition-should-not-retry-on-permanent-error",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
+					Err:           signer.PermanentError{Err: fmt.Errorf("test error")},
+					ConditionType: "[condition type]",
+					Status:        cmmeta.ConditionTrue,
+					Reason:        "[reason]",
+				}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			// no error should be returned because we are in a permanent failure state no further
+			// retries should be made
+			validateError: errormatch.NoError(),
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               "[condition type]",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "[reason]",
+						Message:            "test error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: test error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to sign CertificateRequest: test error",
+			},
+		},
+
+		// Set the Ready condition to Failed if the sign function returns a permanent error.
+		{
+			name: "fail-on-permanent-error",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.PermanentError{Err: fmt.Errorf("a specific error")}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  issuer1.Name,
+						Group: api.SchemeGroupVersion.Group,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: a specific error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to sign CertificateRequest: a specific error",
+			},
+		},
+
+		// Retry a permanent error if TreatAllErrorsAsRetryable is set.
+		{
+			name:         "retry-permanent-error-if-all-errors-retryable",
+			allRetryable: true,
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.PermanentError{Err: fmt.Errorf("a specific error")}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = fakeTimeObj2
+					},
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedResult: reconcile.Result{
+				Requeue: true,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: a specific error",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: a specific error",
+			},
+			expectedAnnotations: map[string]string{
+				v1alpha1.CertificateRequestRetryDeadlineAnnotation: fakeTime2.Add(time.Minute).UTC().Format(time.RFC3339),
+			},
+		},
+
+		// Set the Ready condition to Pending if sign returns an error and we still have time left
+		// to retry.
+		{
+			name: "retry-on-error",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, errors.New("waiting for approval")
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = fakeTimeObj2
+					},
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			// instead of returning an error, we trigger a new reconciliation by setting requeue=true
+			validateError: errormatch.NoError(),
+			expectedResult: reconcile.Result{
+				Requeue: true,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: waiting for approval",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: waiting for approval",
+			},
+			expectedAnnotations: map[string]string{
+				v1alpha1.CertificateRequestRetryDeadlineAnnotation: fakeTime2.Add(time.Minute).UTC().Format(time.RFC3339),
+			},
+		},
+
+		// If the sign function returns an IssuerError, the error is reported to the
+		// issuer, the Ready condition is set to Pending and a dedicated event is emitted.
+		{
+			name: "issuer-error-reported",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.IssuerError{Err: errors.New("[issuer problem]")}
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = fakeTimeObj2
+					},
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			validateError: errormatch.NoError(),
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "Issuer is not Ready yet. Current ready condition is outdated. Waiting for it to become ready.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning IssuerErrorReported Sign reported an issuer error, waiting for the issuer to become ready again: [issuer problem]",
+			},
+		},
+
+		// If EscalateToIssuer is set, it decides which errors are reported to the
+		// issuer, also errors that don't wrap an IssuerError.
+		{
+			name: "escalate-to-issuer-custom-error",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, fmt.Errorf("backend: %w", errCAUnavailable)
+			},
+			escalateToIssuer: func(err error) bool {
+				return errors.Is(err, errCAUnavailable)
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = fakeTimeObj2
+					},
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			validateError: errormatch.NoError(),
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "Issuer is not Ready yet. Current ready condition is outdated. Waiting for it to become ready.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning IssuerErrorReported Sign reported an issuer error, waiting for the issuer to become ready again: backend: CA unavailable",
+			},
+		},
+
+		// Errors that EscalateToIssuer does not escalate are handled on the
+		// request only, even if they wrap an IssuerError.
+		{
+			name: "escalate-to-issuer-not-escalated",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.IssuerError{Err: errors.New("[invalid request]")}
+			},
+			escalateToIssuer: func(err error) bool {
+				return errors.Is(err, errCAUnavailable)
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.CreationTimestamp = fakeTimeObj2
+					},
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			validateError: errormatch.NoError(),
+			expectedResult: reconcile.Result{
+				Requeue: true,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: [invalid request]",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: [invalid request]",
+			},
+		},
+
+		{
+			name: "success-issuer",
+			sign: successSigner("a-signed-certificate"),
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+				}),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		{
+			name: "success-clusterissuer",
+			sign: successSigner("a-signed-certificate"),
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = clusterIssuer1.Name
+					cr.Spec.IssuerRef.Kind = clusterIssuer1.Kind
+				}),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// Add the annotations returned by the signer to the CertificateRequest,
+		// but never overwrite the annotations used by cert-manager itself.
+		{
+			name: "success-sets-signer-annotations",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{
+					ChainPEM: []byte("a-signed-certificate"),
+					Annotations: map[string]string{
+						"example.com/one-time-certificate": "true",
+						cmapi.CertificateNameKey:           "other-certificate",
+					},
+				}, nil
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+					cr.Annotations = map[string]string{
+						cmapi.CertificateNameKey: "certificate-1",
+					}
+				}),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning IgnoredAnnotations Ignored annotations with the reserved \"cert-manager.io/\" prefix: [\"cert-manager.io/certificate-name\"]",
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+			expectedAnnotations: map[string]string{
+				"example.com/one-time-certificate": "true",
+				cmapi.CertificateNameKey:           "certificate-1",
+			},
+		},
+
+		// Store the additional artifacts returned by the signer in annotations on the
+		// CertificateRequest and ignore the artifacts with an invalid name.
+		{
+			name: "success-sets-artifact-annotations",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{
+					ChainPEM: []byte("a-signed-certificate"),
+					AdditionalArtifacts: map[string][]byte{
+						"delegated-credential": []byte("a-delegated-credential"),
+						"invalid/name":         []byte("ignored"),
+					},
+				}, nil
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+				}),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning IgnoredArtifacts Ignored artifacts with a name that is not a valid annotation name: [\"invalid/name\"]",
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+			expectedAnnotations: map[string]string{
+				v1alpha1.ArtifactAnnotationPrefix + "delegated-credential": "YS1kZWxlZ2F0ZWQtY3JlZGVudGlhbA==",
+			},
+		},
+
+		// Keep the signed certificate in the status if AfterSign fails, but don't
+		// mark the CertificateRequest as Ready yet.
+		{
+			name: "after-sign-error-stores-certificate-and-retries",
+			sign: successSigner("a-signed-certificate"),
+			afterSign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer, _ signer.PEMBundle) error {
+				return errors.New("inventory unavailable")
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+					cr.CreationTimestamp = fakeTimeObj2
+				}),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedResult: reconcile.Result{
+				Requeue: true,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest was signed, but AfterSign failed: inventory unavailable",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning AfterSignError Failed to run AfterSign, will retry: inventory unavailable",
+			},
+			expectedAnnotations: map[string]string{
+				v1alpha1.CertificateRequestRetryDeadlineAnnotation: fakeTime2.Add(time.Minute).UTC().Format(time.RFC3339),
+			},
+		},
+
+		// Fail the CertificateRequest if AfterSign still fails once the
+		// MaxRetryDuration has passed, like for Sign errors.
+		{
+			name: "after-sign-error-fails-after-max-retry-duration",
+			sign: successSigner("a-signed-certificate"),
+			afterSign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer, _ signer.PEMBundle) error {
+				return errors.New("inventory unavailable")
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+					cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
+				}),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: AfterSign failed: inventory unavailable",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				FailureTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to run AfterSign: inventory unavailable",
+			},
+		},
+
+		// Retry AfterSign using the stored certificate, without calling Sign again.
+		{
+			name: "after-sign-retry-does-not-sign-again",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.PermanentError{Err: errors.New("sign should not be called")}
+			},
+			afterSign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer, bundle signer.PEMBundle) error {
+				if string(bundle.ChainPEM) != "a-signed-certificate" {
+					return fmt.Errorf("unexpected certificate: %q", bundle.ChainPEM)
+				}
+				return nil
+			},
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1,
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+						cr.Status.Certificate = []byte("a-signed-certificate")
+					},
+					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest was signed, but AfterSign failed: inventory unavailable",
+						LastTransitionTime: &fakeTimeObj1,
+					}),
+				),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// Approve the CertificateRequest if the ApproveCertificateRequest function approves it.
+		{
+			name:    "approve-unapproved",
+			approve: approver(true, "PolicyMatched", "Approved by the trusted namespace policy"),
+			objects: []client.Object{
+				unapprovedCr1,
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               cmapi.CertificateRequestConditionApproved,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "PolicyMatched",
+						Message:            "Approved by the trusted namespace policy",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal Approved Approved by the trusted namespace policy",
+			},
+		},
+
+		// Deny the CertificateRequest if the ApproveCertificateRequest function
+		// does not approve it, use a default reason and message if none is provided.
+		{
+			name:    "deny-unapproved",
+			approve: approver(false, "", ""),
+			objects: []client.Object{
+				unapprovedCr1,
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               cmapi.CertificateRequestConditionDenied,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             fieldOwner,
+						Message:            "CertificateRequest has been denied by " + fieldOwner,
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal Denied CertificateRequest has been denied by " + fieldOwner,
+			},
+		},
+
+		// Don't approve the CertificateRequest before the issuer exists.
+		{
+			name: "approve-waits-for-issuer",
+			approve: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (bool, string, string, error) {
+				return false, "", "", errors.New("approve should not be called")
+			},
+			objects: []client.Object{
+				unapprovedCr1,
+			},
+		},
+
+		// Retry with backoff if the ApproveCertificateRequest function fails.
+		{
+			name: "approve-error",
+			approve: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (bool, string, string, error) {
+				return false, "", "", errors.New("policy unavailable")
+			},
+			objects: []client.Object{
+				unapprovedCr1,
 				testutil.SimpleIssuerFrom(issuer1),
 			},
-			// instead of returning an error, we trigger a new reconciliation by setting requeue=true
-			validateError: errormatch.NoError(),
-			expectedResult: reconcile.Result{
-				Requeue: true,
+			validateError: errormatch.ErrorContains("failed to approve or deny CertificateRequest: policy unavailable"),
+			expectedEvents: []string{
+				"Warning ApprovalError Failed to approve or deny CertificateRequest, will retry: policy unavailable",
+			},
+		},
+
+		// Keep the approval condition in the patch once the CertificateRequest
+		// is approved, so the condition is not removed by the apply patch.
+		{
+			name:    "approved-condition-is-kept",
+			approve: approver(false, "", ""),
+			sign:    successSigner("a-signed-certificate"),
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+				}),
+				testutil.SimpleIssuerFrom(issuer1),
 			},
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
 				Conditions: []cmapi.CertificateRequestCondition{
 					{
-						Type:               cmapi.CertificateRequestConditionReady,
+						Type:               cmapi.CertificateRequestConditionApproved,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "ApprovedReason",
+						Message:            "ApprovedMessage",
+						LastTransitionTime: &fakeTimeObj1,
+					},
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
 						Status:             cmmeta.ConditionFalse,
-						Reason:             cmapi.CertificateRequestReasonPending,
-						Message:            "CertificateRequest is not ready yet: pending error",
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
 			},
 			expectedEvents: []string{
-				"Warning RetryableError Failed to sign CertificateRequest, will retry: pending error",
+				"Normal Issued Succeeded signing the CertificateRequest",
 			},
 		},
 
-		// If the sign function returns an SetCertificateRequestConditionError error with a condition
-		// type that is *not present* in the status, the new condition is *added* to the
-		// CertificateRequest.
-		// Additionally, if the error wrapped by SetCertificateRequestConditionError is not one of the
-		// supported 'signer API' errors an we still *have time left* to retry, set the Ready
-		// condition to *Pending*.
+		// Set the IncompleteChain condition if the returned chain is incomplete.
 		{
-			name: "error-set-certificate-request-condition-should-add-new-condition-and-retry",
-			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
-				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
-					Err:           fmt.Errorf("test error"),
-					ConditionType: "[condition type]",
-					Status:        cmmeta.ConditionTrue,
-					Reason:        "[reason]",
-				}
-			},
+			name:       "incomplete-chain-sets-condition",
+			sign:       successSigner(string(chain.leafPEM)),
+			checkChain: true,
 			objects: []client.Object{
-				cmgen.CertificateRequestFrom(cr1,
-					func(cr *cmapi.CertificateRequest) {
-						cr.CreationTimestamp = fakeTimeObj2
-					},
-					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
-						Name:  issuer1.Name,
-						Group: api.SchemeGroupVersion.Group,
-					}),
-				),
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+				}),
 				testutil.SimpleIssuerFrom(issuer1),
 			},
-			// no error should be returned because the reconciliation should be triggered
-			// when the custom condition is added
-			validateError: errormatch.NoError(),
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: chain.leafPEM,
 				Conditions: []cmapi.CertificateRequestCondition{
 					{
-						Type:               "[condition type]",
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               v1alpha1.CertificateRequestConditionIncompleteChain,
 						Status:             cmmeta.ConditionTrue,
-						Reason:             "[reason]",
-						Message:            "test error",
+						Reason:             v1alpha1.CertificateRequestConditionReasonIncompleteChain,
+						Message:            "incomplete certificate chain: x509: certificate signed by unknown authority",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning IncompleteChain The signed certificate chain is incomplete: incomplete certificate chain: x509: certificate signed by unknown authority",
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// Retry if the returned chain is incomplete and RetryIncompleteChain is set.
+		{
+			name:            "incomplete-chain-retry",
+			sign:            successSigner(string(chain.leafPEM)),
+			checkChain:      true,
+			retryIncomplete: true,
+			objects: []client.Object{
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+					cr.CreationTimestamp = fakeTimeObj2
+				}),
+				testutil.SimpleIssuerFrom(issuer1),
+			},
+			expectedResult: reconcile.Result{
+				Requeue: true,
+			},
+			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
 						Status:             cmmeta.ConditionFalse,
 						Reason:             cmapi.CertificateRequestReasonPending,
-						Message:            "CertificateRequest is not ready yet: test error",
+						Message:            "CertificateRequest is not ready yet: incomplete certificate chain: x509: certificate signed by unknown authority",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
 			},
 			expectedEvents: []string{
-				"Warning RetryableError Failed to sign CertificateRequest, will retry: test error",
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: incomplete certificate chain: x509: certificate signed by unknown authority",
 			},
 		},
 
-		// If the sign function returns an SetCertificateRequestConditionError error with a condition
-		// type that is *already present* in the status, the existing condition is *updated* with
-		// the values specified in the error.
-		// Additionally, if the error wrapped by SetCertificateRequestConditionError is not one of the
-		// supported 'signer API' errors an we still *have time left* to retry, set the Ready
-		// condition to *Pending*.
+		// Retry if the NotAfter returned by the signer does not match the certificate.
 		{
-			name: "error-set-certificate-request-condition-should-update-existing-condition-and-retry",
-			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
-				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
-					Err:           fmt.Errorf("test error2"),
-					ConditionType: "[condition type]",
-					Status:        cmmeta.ConditionTrue,
-					Reason:        "[reason]",
-				}
+			name: "not-after-mismatch-retry",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{
+					ChainPEM: chain.leafPEM,
+					NotAfter: fakeTime2.Add(2 * time.Hour),
+				}, nil
 			},
 			objects: []client.Object{
-				cmgen.CertificateRequestFrom(cr1,
-					func(cr *cmapi.CertificateRequest) {
-						cr.CreationTimestamp = fakeTimeObj2
-					},
-					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
-						Name:  issuer1.Name,
-						Group: api.SchemeGroupVersion.Group,
-					}),
-					cmgen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
-						Type:               "[condition type]",
-						Status:             cmmeta.ConditionTrue,
-						Reason:             "[reason]",
-						Message:            "test error",
-						LastTransitionTime: &fakeTimeObj2,
-					}),
-				),
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+					cr.CreationTimestamp = fakeTimeObj2
+				}),
 				testutil.SimpleIssuerFrom(issuer1),
 			},
-			// instead of returning an error, we trigger a new reconciliation by setting requeue=true
-			validateError: errormatch.NoError(),
 			expectedResult: reconcile.Result{
 				Requeue: true,
 			},
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
 				Conditions: []cmapi.CertificateRequestCondition{
 					{
-						Type:               "[condition type]",
-						Status:             cmmeta.ConditionTrue,
-						Reason:             "[reason]",
-						Message:            "test error2",
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
 						Status:             cmmeta.ConditionFalse,
 						Reason:             cmapi.CertificateRequestReasonPending,
-						Message:            "CertificateRequest is not ready yet: test error2",
+						Message:            fmt.Sprintf("CertificateRequest is not ready yet: %s", notAfterMismatch),
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
 			},
 			expectedEvents: []string{
-				"Warning RetryableError Failed to sign CertificateRequest, will retry: test error2",
+				fmt.Sprintf("Warning RetryableError Failed to sign CertificateRequest, will retry: %s", notAfterMismatch),
 			},
 		},
 
-		// If the sign function returns an SetCertificateRequestConditionError error with a condition
-		// type that is *not present* in the status, the new condition is *added* to the
-		// CertificateRequest.
-		// Additionally, if the error wrapped by SetCertificateRequestConditionError is not one of the
-		// supported 'signer API' errors an we have *no time left* to retry, set the Ready condition
-		// to *Failed*.
+		// Fail if the returned certificate has no embedded SCTs and RequireSCT is set.
 		{
-			name: "error-set-certificate-request-condition-should-add-new-condition-and-timeout",
-			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
-				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
-					Err:           fmt.Errorf("test error"),
-					ConditionType: "[condition type]",
-					Status:        cmmeta.ConditionTrue,
-					Reason:        "[reason]",
-				}
-			},
+			name:       "require-sct-missing",
+			sign:       successSigner(string(chain.leafPEM)),
+			requireSCT: true,
 			objects: []client.Object{
-				cmgen.CertificateRequestFrom(cr1,
-					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
-						Name:  issuer1.Name,
-						Group: api.SchemeGroupVersion.Group,
-					}),
-					func(cr *cmapi.CertificateRequest) {
-						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
-					},
-				),
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+					cr.CreationTimestamp = fakeTimeObj2
+				}),
 				testutil.SimpleIssuerFrom(issuer1),
 			},
-			// no error should be returned because the reconciliation should be triggered when the
-			// custom condition is added
-			validateError: errormatch.NoError(),
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
 				Conditions: []cmapi.CertificateRequestCondition{
 					{
-						Type:               "[condition type]",
-						Status:             cmmeta.ConditionTrue,
-						Reason:             "[reason]",
-						Message:            "test error",
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
 						Status:             cmmeta.ConditionFalse,
 						Reason:             cmapi.CertificateRequestReasonFailed,
-						Message:            "CertificateRequest has failed permanently: test error",
+						Message:            "CertificateRequest has failed permanently: the certificate contains 0 embedded signed certificate timestamps, at least 1 are required",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
 				FailureTime: &fakeTimeObj2,
 			},
 			expectedEvents: []string{
-				"Warning PermanentError Failed permanently to sign CertificateRequest: test error",
+				"Warning PermanentError Failed permanently to sign CertificateRequest: the certificate contains 0 embedded signed certificate timestamps, at least 1 are required",
 			},
 		},
 
-		// If the sign function returns an SetCertificateRequestConditionError error with a condition
-		// type that is *already present* in the status, the existing condition is *updated* with
-		// the values specified in the error.
-		// Additionally, if the error wrapped by SetCertificateRequestConditionError is not one of the
-		// supported 'signer API' errors an we have *no time left* to retry, set the Ready condition
-		// to *Failed*.
+		// Retry if the returned certificate contains a SAN that was not requested
+		// and RequireExactSANs is set.
 		{
-			name: "error-set-certificate-request-condition-should-update-existing-condition-and-timeout",
-			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
-				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
-					Err:           fmt.Errorf("test error2"),
-					ConditionType: "[condition type]",
-					Status:        cmmeta.ConditionTrue,
-					Reason:        "[reason]",
-				}
-			},
+			name:             "require-exact-sans-extra-san",
+			sign:             successSigner(string(newTestSANCertificate(t, "foo.example.com", "extra.example.com"))),
+			requireExactSANs: true,
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1,
 					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
 						Name:  issuer1.Name,
 						Group: api.SchemeGroupVersion.Group,
 					}),
+					cmgen.SetCertificateRequestCSR(nameConstraintsCSR),
 					func(cr *cmapi.CertificateRequest) {
-						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
+						cr.CreationTimestamp = fakeTimeObj2
 					},
-					cmgen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
-						Type:               "[condition type]",
-						Status:             cmmeta.ConditionTrue,
-						Reason:             "[reason]",
-						Message:            "test error",
-						LastTransitionTime: &fakeTimeObj1,
-					}),
 				),
 				testutil.SimpleIssuerFrom(issuer1),
 			},
-			// since we got into a permanent failure state, we should not return an error
-			validateError: errormatch.NoError(),
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
 				Conditions: []cmapi.CertificateRequestCondition{
 					{
-						Type:               "[condition type]",
-						Status:             cmmeta.ConditionTrue,
-						Reason:             "[reason]",
-						Message:            "test error2",
-						LastTransitionTime: &fakeTimeObj1, // since the status is not updated, the LastTransitionTime is not updated either
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
 					},
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
 						Status:             cmmeta.ConditionFalse,
-						Reason:             cmapi.CertificateRequestReasonFailed,
-						Message:            "CertificateRequest has failed permanently: test error2",
+						Reason:             cmapi.CertificateRequestReasonPending,
+						Message:            "CertificateRequest is not ready yet: the subject alternative names of the certificate don't match the request: unexpected DNS:extra.example.com",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
-				FailureTime: &fakeTimeObj2,
 			},
+			expectedResult: reconcile.Result{Requeue: true},
 			expectedEvents: []string{
-				"Warning PermanentError Failed permanently to sign CertificateRequest: test error2",
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: the subject alternative names of the certificate don't match the request: unexpected DNS:extra.example.com",
 			},
 		},
 
-		// If the sign function returns an SetCertificateRequestConditionError, the specified
-		// conditions value is updated/ added to the CertificateRequest status.
-		// Additionally, if the error wrapped by SetCertificateRequestConditionError is a PendingError
-		// error, the Ready condition is set to Pending (even if the MaxRetryDuration has been
-		// exceeded).
+		// Preserve the certificate of a CertificateRequest that already has one,
+		// without calling Sign again.
 		{
-			name: "error-set-certificate-request-condition-should-not-timeout-if-pending",
-			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
-				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
-					Err:           signer.PendingError{Err: fmt.Errorf("test error")},
-					ConditionType: "[condition type]",
-					Status:        cmmeta.ConditionTrue,
-					Reason:        "[reason]",
-				}
+			name:                "preserve-existing-certificate",
+			preserveExistingCrt: true,
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, fmt.Errorf("sign should not be called")
 			},
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1,
-					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
-						Name:  issuer1.Name,
-						Group: api.SchemeGroupVersion.Group,
-					}),
 					func(cr *cmapi.CertificateRequest) {
-						cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+						cr.Status.Certificate = []byte("an-existing-certificate")
 					},
 				),
 				testutil.SimpleIssuerFrom(issuer1),
 			},
-			// no error should be returned because the reconciliation should be triggered
-			// when the custom condition is added
-			validateError: errormatch.NoError(),
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte("an-existing-certificate"),
 				Conditions: []cmapi.CertificateRequestCondition{
 					{
-						Type:               "[condition type]",
-						Status:             cmmeta.ConditionTrue,
-						Reason:             "[reason]",
-						Message:            "test error",
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
-						Status:             cmmeta.ConditionFalse,
-						Reason:             cmapi.CertificateRequestReasonPending,
-						Message:            "CertificateRequest is not ready yet: test error",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
 			},
 			expectedEvents: []string{
-				"Warning RetryableError Failed to sign CertificateRequest, will retry: test error",
+				"Normal Issued Preserved the existing certificate of the CertificateRequest",
 			},
 		},
 
-		// If the sign function returns an SetCertificateRequestConditionError, the specified
-		// conditions value is updated/ added to the CertificateRequest status.
-		// Additionally, if the error wrapped by SetCertificateRequestConditionError is a PendingError
-		// error, the Ready condition is set to Failed (even if the MaxRetryDuration has NOT been
-		// exceeded).
+		// Set an existing IncompleteChain condition to False once the chain is complete.
 		{
-			name: "error-set-certificate-request-condition-should-not-retry-on-permanent-error",
-			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
-				return signer.PEMBundle{}, signer.SetCertificateRequestConditionError{
-					Err:           signer.PermanentError{Err: fmt.Errorf("test error")},
-					ConditionType: "[condition type]",
-					Status:        cmmeta.ConditionTrue,
-					Reason:        "[reason]",
-				}
-			},
+			name:       "complete-chain-resets-condition",
+			sign:       successSigner(string(chain.leafPEM) + string(chain.intermediatePEM) + string(chain.rootPEM)),
+			checkChain: true,
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1,
-					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
-						Name:  issuer1.Name,
-						Group: api.SchemeGroupVersion.Group,
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+					cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:               v1alpha1.CertificateRequestConditionIncompleteChain,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIncompleteChain,
+						Message:            "incomplete certificate chain: x509: certificate signed by unknown authority",
+						LastTransitionTime: &fakeTimeObj1,
 					}),
 				),
 				testutil.SimpleIssuerFrom(issuer1),
 			},
-			// no error should be returned because we are in a permanent failure state no further
-			// retries should be made
-			validateError: errormatch.NoError(),
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
+				Certificate: []byte(string(chain.leafPEM) + string(chain.intermediatePEM) + string(chain.rootPEM)),
 				Conditions: []cmapi.CertificateRequestCondition{
 					{
-						Type:               "[condition type]",
-						Status:             cmmeta.ConditionTrue,
-						Reason:             "[reason]",
-						Message:            "test error",
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 					{
-						Type:               cmapi.CertificateRequestConditionReady,
+						Type:               v1alpha1.CertificateRequestConditionIncompleteChain,
 						Status:             cmmeta.ConditionFalse,
-						Reason:             cmapi.CertificateRequestReasonFailed,
-						Message:            "CertificateRequest has failed permanently: test error",
+						Reason:             v1alpha1.CertificateRequestConditionReasonCompleteChain,
+						Message:            "The certificate chain is complete",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+					{
+						Type:               cmapi.CertificateRequestConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonIssued,
+						Message:            "issued",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
-				FailureTime: &fakeTimeObj2,
 			},
 			expectedEvents: []string{
-				"Warning PermanentError Failed permanently to sign CertificateRequest: test error",
+				"Normal Issued Succeeded signing the CertificateRequest",
 			},
 		},
 
-		// Set the Ready condition to Failed if the sign function returns a permanent error.
+		// Fail the request without signing it if it is older than MaxRequestAge.
 		{
-			name: "fail-on-permanent-error",
-			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
-				return signer.PEMBundle{}, signer.PermanentError{Err: fmt.Errorf("a specific error")}
+			name: "request-expired",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, errors.New("sign should not be called")
 			},
+			maxRequestAge: time.Hour,
 			objects: []client.Object{
-				cmgen.CertificateRequestFrom(cr1,
-					cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
-						Name:  issuer1.Name,
-						Group: api.SchemeGroupVersion.Group,
-					}),
-				),
+				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+					cr.CreationTimestamp = fakeTimeObj1
+				}),
 				testutil.SimpleIssuerFrom(issuer1),
 			},
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
@@ -824,29 +3593,27 @@ func TestCertificateRequestReconcilerReconcile(t *testing.T) {
 						Type:               cmapi.CertificateRequestConditionReady,
 						Status:             cmmeta.ConditionFalse,
 						Reason:             cmapi.CertificateRequestReasonFailed,
-						Message:            "CertificateRequest has failed permanently: a specific error",
+						Message:            "CertificateRequest has failed permanently: the request is older than the maximum request age of 1h0m0s",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
 				FailureTime: &fakeTimeObj2,
 			},
 			expectedEvents: []string{
-				"Warning PermanentError Failed permanently to sign CertificateRequest: a specific error",
+				"Warning RequestExpired The request is older than the maximum request age of 1h0m0s, not signing it",
 			},
 		},
 
-		// Set the Ready condition to Pending if sign returns an error and we still have time left
-		// to retry.
+		// Fail the request without signing it if the CSR is larger than MaxCSRSize.
 		{
-			name: "retry-on-error",
-			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
-				return signer.PEMBundle{}, errors.New("waiting for approval")
+			name: "csr-too-large",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, errors.New("sign should not be called")
 			},
+			maxCSRSize: 1024,
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1,
-					func(cr *cmapi.CertificateRequest) {
-						cr.CreationTimestamp = fakeTimeObj2
-					},
+					cmgen.SetCertificateRequestCSR(bytes.Repeat([]byte("a"), 1025)),
 					func(cr *cmapi.CertificateRequest) {
 						cr.Spec.IssuerRef.Name = issuer1.Name
 						cr.Spec.IssuerRef.Kind = issuer1.Kind
@@ -854,67 +3621,79 @@ func TestCertificateRequestReconcilerReconcile(t *testing.T) {
 				),
 				testutil.SimpleIssuerFrom(issuer1),
 			},
-			// instead of returning an error, we trigger a new reconciliation by setting requeue=true
-			validateError: errormatch.NoError(),
-			expectedResult: reconcile.Result{
-				Requeue: true,
-			},
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
 				Conditions: []cmapi.CertificateRequestCondition{
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
 						Status:             cmmeta.ConditionFalse,
-						Reason:             cmapi.CertificateRequestReasonPending,
-						Message:            "CertificateRequest is not ready yet: waiting for approval",
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: the CSR is 1025 bytes, which is larger than the maximum size of 1024 bytes",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
+				FailureTime: &fakeTimeObj2,
 			},
 			expectedEvents: []string{
-				"Warning RetryableError Failed to sign CertificateRequest, will retry: waiting for approval",
+				"Warning RequestTooLarge The CSR is 1025 bytes, which is larger than the maximum size of 1024 bytes, not signing it",
 			},
 		},
 
+		// The DefaultMaxCSRSize is used if MaxCSRSize is not set.
 		{
-			name: "success-issuer",
-			sign: successSigner("a-signed-certificate"),
+			name: "csr-too-large-default",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, errors.New("sign should not be called")
+			},
 			objects: []client.Object{
-				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
-					cr.Spec.IssuerRef.Name = issuer1.Name
-					cr.Spec.IssuerRef.Kind = issuer1.Kind
-				}),
+				cmgen.CertificateRequestFrom(cr1,
+					cmgen.SetCertificateRequestCSR(bytes.Repeat([]byte("a"), DefaultMaxCSRSize+1)),
+					func(cr *cmapi.CertificateRequest) {
+						cr.Spec.IssuerRef.Name = issuer1.Name
+						cr.Spec.IssuerRef.Kind = issuer1.Kind
+					},
+				),
 				testutil.SimpleIssuerFrom(issuer1),
 			},
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
-				Certificate: []byte("a-signed-certificate"),
 				Conditions: []cmapi.CertificateRequestCondition{
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
-						Status:             cmmeta.ConditionTrue,
-						Reason:             cmapi.CertificateRequestReasonIssued,
-						Message:            "issued",
+						Status:             cmmeta.ConditionFalse,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: the CSR is 262145 bytes, which is larger than the maximum size of 262144 bytes",
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
+				FailureTime: &fakeTimeObj2,
 			},
 			expectedEvents: []string{
-				"Normal Issued Succeeded signing the CertificateRequest",
+				"Warning RequestTooLarge The CSR is 262145 bytes, which is larger than the maximum size of 262144 bytes, not signing it",
 			},
 		},
 
+		// Sign the request if it is younger than MaxRequestAge.
 		{
-			name: "success-clusterissuer",
-			sign: successSigner("a-signed-certificate"),
+			name:          "request-not-expired",
+			sign:          successSigner("a-signed-certificate"),
+			maxRequestAge: 5 * time.Hour,
 			objects: []client.Object{
 				cmgen.CertificateRequestFrom(cr1, func(cr *cmapi.CertificateRequest) {
-					cr.Spec.IssuerRef.Name = clusterIssuer1.Name
-					cr.Spec.IssuerRef.Kind = clusterIssuer1.Kind
+					cr.Spec.IssuerRef.Name = issuer1.Name
+					cr.Spec.IssuerRef.Kind = issuer1.Kind
+					cr.CreationTimestamp = fakeTimeObj1
 				}),
-				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+				testutil.SimpleIssuerFrom(issuer1),
 			},
 			expectedStatusPatch: &cmapi.CertificateRequestStatus{
 				Certificate: []byte("a-signed-certificate"),
 				Conditions: []cmapi.CertificateRequestCondition{
+					{
+						Type:               v1alpha1.CertificateRequestConditionIssuerNotFound,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.CertificateRequestConditionReasonIssuerFound,
+						Message:            "The issuer was found.",
+						LastTransitionTime: &fakeTimeObj2,
+					},
 					{
 						Type:               cmapi.CertificateRequestConditionReady,
 						Status:             cmmeta.ConditionTrue,
@@ -938,6 +3717,7 @@ func TestCertificateRequestReconcilerReconcile(t *testing.T) {
 			scheme := runtime.NewScheme()
 			require.NoError(t, setupCertificateRequestReconcilerScheme(scheme))
 			require.NoError(t, api.AddToScheme(scheme))
+			require.NoError(t, corev1.AddToScheme(scheme))
 			fakeClient := fake.NewClientBuilder().
 				WithScheme(scheme).
 				WithObjects(tc.objects...).
@@ -965,8 +3745,48 @@ func TestCertificateRequestReconcilerReconcile(t *testing.T) {
 				EventSource:        kubeutil.NewEventStore(),
 				Client:             fakeClient,
 				Sign:               tc.sign,
+				AfterSign:          tc.afterSign,
 				EventRecorder:      fakeRecorder,
 				Clock:              fakeClock2,
+
+				ApproveCertificateRequest: tc.approve,
+				IgnoreIssuer:              tc.ignoreIssuer,
+				IgnoreCertificateRequest:  tc.ignoreCR,
+
+				CheckChainCompleteness: tc.checkChain,
+				RetryIncompleteChain:   tc.retryIncomplete,
+				RequireSCT:             tc.requireSCT,
+				RequireExactSANs:       tc.requireExactSANs,
+				DeduplicateSANs:        tc.deduplicateSANs,
+				RejectConflictingSANs:  tc.rejectConflicting,
+
+				PreserveExistingCertificate: tc.preserveExistingCrt,
+
+				TreatAllErrorsAsRetryable: tc.allRetryable,
+				IsPermanent:               tc.isPermanent,
+				EscalateToIssuer:          tc.escalateToIssuer,
+
+				MaxRequestAge: tc.maxRequestAge,
+				SignTimeout:   tc.signTimeout,
+				MaxCSRSize:    tc.maxCSRSize,
+
+				IssuerDeletedGracePeriod: tc.issuerDeletedGrace,
+
+				PendingCertificateRequestResyncInterval: tc.pendingResync,
+				FormatPendingMessage:                    tc.formatPending,
+				ForceReissueAnnotation:                  tc.forceReissue,
+
+				HTTPClientProvider: tc.httpClientProvider,
+				APIReader:          fakeClient,
+
+				QuotaCheck:    tc.quotaCheck,
+				DefaultUsages: tc.defaultUsages,
+			}
+
+			if tc.nameConstraints != nil {
+				controller.GetNameConstraints = func(_ context.Context, _ v1alpha1.Issuer) (*signer.NameConstraints, error) {
+					return tc.nameConstraints, nil
+				}
 			}
 
 			err = controller.setIssuersGroupVersionKind(scheme)
@@ -984,13 +3804,339 @@ func TestCertificateRequestReconcilerReconcile(t *testing.T) {
 			} else {
 				assert.Equal(t, tc.expectedEvents, allEvents)
 			}
+
+			if tc.expectedAnnotations != nil {
+				var crAfter cmapi.CertificateRequest
+				require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, &crAfter))
+				assert.Equal(t, tc.expectedAnnotations, crAfter.Annotations)
+			}
 		})
 	}
 }
 
+// TestCertificateRequestReconcilerPermanentDenialIsTerminal verifies that a
+// CertificateRequest that is denied permanently (eg. by the name constraints
+// of the issuer) is not requeued and that later reconciles of the Failed
+// CertificateRequest are no-ops, so no work is wasted on rejected requests.
+func TestCertificateRequestReconcilerPermanentDenialIsTerminal(t *testing.T) {
+	t.Parallel()
+
+	fakeTime := randomTime().Truncate(time.Second)
+	fakeClock := clocktesting.NewFakeClock(fakeTime)
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace("ns1"),
+		testutil.SetSimpleIssuerGeneration(70),
+		testutil.SetSimpleIssuerStatusCondition(
+			fakeClock,
+			cmapi.IssuerConditionReady,
+			cmmeta.ConditionTrue,
+			v1alpha1.IssuerConditionReasonChecked,
+			"Succeeded checking the issuer",
+		),
+	)
+
+	csr, _, err := cmgen.CSR(x509.ECDSA, cmgen.SetCSRDNSNames("foo.example.com"))
+	require.NoError(t, err)
+
+	cr := cmgen.CertificateRequest(
+		"cr1",
+		cmgen.SetCertificateRequestNamespace("ns1"),
+		cmgen.SetCertificateRequestCSR(csr),
+		cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Name:  issuer.Name,
+			Group: api.SchemeGroupVersion.Group,
+		}),
+		cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionApproved,
+			Status: cmmeta.ConditionTrue,
+			Reason: "ApprovedReason",
+		}),
+	)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, setupCertificateRequestReconcilerScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(cr, issuer).
+		Build()
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)}
+	logger := logrtesting.NewTestLoggerWithOptions(t, logrtesting.Options{LogTimestamp: true, Verbosity: 10})
+
+	signCalls := 0
+	controller := CertificateRequestReconciler{
+		IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+		ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+		FieldOwner:         "test-certificate-request-reconciler-permanent-denial",
+		MaxRetryDuration:   time.Minute,
+		EventSource:        kubeutil.NewEventStore(),
+		Client:             fakeClient,
+		Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+			signCalls++
+			return signer.PEMBundle{ChainPEM: []byte("a-signed-certificate")}, nil
+		},
+		GetNameConstraints: func(_ context.Context, _ v1alpha1.Issuer) (*signer.NameConstraints, error) {
+			return &signer.NameConstraints{ExcludedDNSNames: []string{`.*\.example\.com`}}, nil
+		},
+		EventRecorder: record.NewFakeRecorder(100),
+		Clock:         fakeClock,
+	}
+	require.NoError(t, controller.setIssuersGroupVersionKind(scheme))
+
+	// The first reconcile initializes the Ready condition, the second one
+	// denies the request.
+	for i := 0; i < 4; i++ {
+		result, crStatusPatch, err := controller.reconcileStatusPatch(logger, context.TODO(), req)
+		require.NoError(t, err)
+		assert.Equal(t, reconcile.Result{}, result, "reconcile %d requeued the request", i)
+
+		if i >= 2 {
+			assert.Nil(t, crStatusPatch, "reconcile %d of the Failed request changed its status", i)
+			continue
+		}
+		require.NotNil(t, crStatusPatch)
+
+		// Store the patched status, like the status patch would.
+		var current cmapi.CertificateRequest
+		require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, &current))
+		for _, condition := range crStatusPatch.Conditions {
+			cmutil.SetCertificateRequestCondition(&current, condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+		current.Status.FailureTime = crStatusPatch.FailureTime
+		require.NoError(t, fakeClient.Update(context.TODO(), &current))
+	}
+
+	var current cmapi.CertificateRequest
+	require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, &current))
+	readyCondition := cmutil.GetCertificateRequestCondition(&current, cmapi.CertificateRequestConditionReady)
+	require.NotNil(t, readyCondition)
+	assert.Equal(t, cmapi.CertificateRequestReasonFailed, readyCondition.Reason)
+	assert.Zero(t, signCalls, "Sign was called for a denied request")
+}
+
+// TestCertificateRequestReconcilerRetryDeadlineAnnotationUnchanged verifies
+// that the retry deadline annotation is only patched once, not on every
+// retry.
+func TestCertificateRequestReconcilerRetryDeadlineAnnotationUnchanged(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := clocktesting.NewFakeClock(randomTime().Truncate(time.Second))
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace("ns1"),
+		testutil.SetSimpleIssuerStatusCondition(
+			fakeClock,
+			cmapi.IssuerConditionReady,
+			cmmeta.ConditionTrue,
+			v1alpha1.IssuerConditionReasonChecked,
+			"Succeeded checking the issuer",
+		),
+	)
+
+	cr := cmgen.CertificateRequest(
+		"cr1",
+		cmgen.SetCertificateRequestNamespace("ns1"),
+		cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Name:  issuer.Name,
+			Kind:  "SimpleIssuer",
+			Group: api.SchemeGroupVersion.Group,
+		}),
+		cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionApproved,
+			Status: cmmeta.ConditionTrue,
+			Reason: "ApprovedReason",
+		}),
+		cmgen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionReady,
+			Status: cmmeta.ConditionUnknown,
+			Reason: v1alpha1.CertificateRequestConditionReasonInitializing,
+		}),
+		func(cr *cmapi.CertificateRequest) {
+			cr.CreationTimestamp = metav1.NewTime(fakeClock.Now())
+		},
+	)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, setupCertificateRequestReconcilerScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+
+	patches := 0
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(cr, issuer).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patches++
+				return cl.Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)}
+	logger := logrtesting.NewTestLoggerWithOptions(t, logrtesting.Options{LogTimestamp: true, Verbosity: 10})
+
+	controller := CertificateRequestReconciler{
+		IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+		ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+		FieldOwner:         "test-certificate-request-reconciler-retry-deadline",
+		MaxRetryDuration:   time.Hour,
+		EventSource:        kubeutil.NewEventStore(),
+		Client:             fakeClient,
+		Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+			return signer.PEMBundle{}, fmt.Errorf("[retryable error]")
+		},
+		EventRecorder: record.NewFakeRecorder(100),
+		Clock:         fakeClock,
+	}
+	require.NoError(t, controller.setIssuersGroupVersionKind(scheme))
+
+	for i := 0; i < 3; i++ {
+		fakeClock.Step(time.Minute)
+
+		result, crStatusPatch, err := controller.reconcileStatusPatch(logger, context.TODO(), req)
+		require.NoError(t, err)
+		require.NotNil(t, crStatusPatch)
+		assert.True(t, result.Requeue)
+	}
+
+	var current cmapi.CertificateRequest
+	require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, &current))
+	assert.Equal(t, cr.CreationTimestamp.Add(time.Hour).UTC().Format(time.RFC3339), current.Annotations[v1alpha1.CertificateRequestRetryDeadlineAnnotation])
+	assert.Equal(t, 1, patches)
+}
+
+// TestCertificateRequestReconcilerIssuerDeletedTwice verifies that an issuer
+// that is deleted, recreated and deleted again starts a new grace period for
+// the second deletion, instead of reusing the start of the first one.
+func TestCertificateRequestReconcilerIssuerDeletedTwice(t *testing.T) {
+	t.Parallel()
+
+	fakeTime := randomTime().Truncate(time.Second)
+	fakeClock := clocktesting.NewFakeClock(fakeTime)
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace("ns1"),
+		testutil.SetSimpleIssuerStatusCondition(
+			fakeClock,
+			cmapi.IssuerConditionReady,
+			cmmeta.ConditionFalse,
+			v1alpha1.IssuerConditionReasonPending,
+			"Issuer is not ready yet",
+		),
+	)
+
+	cr := cmgen.CertificateRequest(
+		"cr1",
+		cmgen.SetCertificateRequestNamespace("ns1"),
+		cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Name:  issuer.Name,
+			Kind:  "SimpleIssuer",
+			Group: api.SchemeGroupVersion.Group,
+		}),
+		cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionApproved,
+			Status: cmmeta.ConditionTrue,
+			Reason: "ApprovedReason",
+		}),
+		cmgen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:    cmapi.CertificateRequestConditionReady,
+			Status:  cmmeta.ConditionFalse,
+			Reason:  cmapi.CertificateRequestReasonPending,
+			Message: "Issuer is not Ready yet.",
+		}),
+		cmgen.AddCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:    v1alpha1.CertificateRequestConditionIssuerNotFound,
+			Status:  cmmeta.ConditionFalse,
+			Reason:  v1alpha1.CertificateRequestConditionReasonIssuerFound,
+			Message: "The issuer was found.",
+		}),
+	)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, setupCertificateRequestReconcilerScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(cr).
+		Build()
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)}
+	logger := logrtesting.NewTestLoggerWithOptions(t, logrtesting.Options{LogTimestamp: true, Verbosity: 10})
+
+	controller := CertificateRequestReconciler{
+		IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+		ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+		FieldOwner:         "test-certificate-request-reconciler-issuer-deleted-twice",
+		MaxRetryDuration:   time.Minute,
+		EventSource:        kubeutil.NewEventStore(),
+		Client:             fakeClient,
+		Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+			return signer.PEMBundle{}, fmt.Errorf("sign should not be called")
+		},
+		EventRecorder: record.NewFakeRecorder(100),
+		Clock:         fakeClock,
+	}
+	require.NoError(t, controller.setIssuersGroupVersionKind(scheme))
+
+	// reconcile reconciles the request and stores the patched status like the
+	// server-side apply patch would: the conditions that are owned by the
+	// controller (all except the Approved condition) are replaced.
+	reconcile := func() *cmapi.CertificateRequest {
+		_, crStatusPatch, err := controller.reconcileStatusPatch(logger, context.TODO(), req)
+		require.NoError(t, err)
+		require.NotNil(t, crStatusPatch)
+
+		var current cmapi.CertificateRequest
+		require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, &current))
+		approved := cmutil.GetCertificateRequestCondition(&current, cmapi.CertificateRequestConditionApproved)
+		current.Status.Conditions = append([]cmapi.CertificateRequestCondition{*approved}, crStatusPatch.Conditions...)
+		current.Status.FailureTime = crStatusPatch.FailureTime
+		require.NoError(t, fakeClient.Update(context.TODO(), &current))
+		return &current
+	}
+
+	// The issuer is deleted while the request is pending.
+	current := reconcile()
+	notFoundCondition := cmutil.GetCertificateRequestCondition(current, v1alpha1.CertificateRequestConditionIssuerNotFound)
+	require.NotNil(t, notFoundCondition)
+	assert.Equal(t, cmmeta.ConditionTrue, notFoundCondition.Status)
+	assert.Equal(t, v1alpha1.CertificateRequestConditionReasonIssuerDeleted, notFoundCondition.Reason)
+	assert.Equal(t, fakeTime, notFoundCondition.LastTransitionTime.Time)
+
+	// The issuer is recreated within the grace period.
+	fakeClock.Step(30 * time.Minute)
+	require.NoError(t, fakeClient.Create(context.TODO(), issuer))
+	current = reconcile()
+	notFoundCondition = cmutil.GetCertificateRequestCondition(current, v1alpha1.CertificateRequestConditionIssuerNotFound)
+	require.NotNil(t, notFoundCondition)
+	assert.Equal(t, cmmeta.ConditionFalse, notFoundCondition.Status)
+	assert.Equal(t, v1alpha1.CertificateRequestConditionReasonIssuerFound, notFoundCondition.Reason)
+
+	// The issuer is deleted again, after the grace period of the first
+	// deletion has passed. The request waits for a new grace period.
+	fakeClock.Step(time.Hour)
+	require.NoError(t, fakeClient.Delete(context.TODO(), issuer))
+	current = reconcile()
+	notFoundCondition = cmutil.GetCertificateRequestCondition(current, v1alpha1.CertificateRequestConditionIssuerNotFound)
+	require.NotNil(t, notFoundCondition)
+	assert.Equal(t, cmmeta.ConditionTrue, notFoundCondition.Status)
+	assert.Equal(t, v1alpha1.CertificateRequestConditionReasonIssuerDeleted, notFoundCondition.Reason)
+	assert.Equal(t, fakeClock.Now(), notFoundCondition.LastTransitionTime.Time)
+
+	readyCondition := cmutil.GetCertificateRequestCondition(current, cmapi.CertificateRequestConditionReady)
+	require.NotNil(t, readyCondition)
+	assert.Equal(t, cmapi.CertificateRequestReasonPending, readyCondition.Reason)
+}
+
 func chanToSlice(ch <-chan string) []string {
-	out := make([]string, 0, len(ch))
-	for i := 0; i < len(ch); i++ {
+	n := len(ch)
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
 		out = append(out, <-ch)
 	}
 	return out
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -60,20 +61,185 @@ type CertificateSigningRequestReconciler struct {
 	MaxRetryDuration time.Duration
 	EventSource      kubeutil.EventSource
 
+	// MaxRequestAge is the maximum age of a request (based on its creation
+	// timestamp) that will still be signed. Older requests that have not been
+	// signed yet are failed permanently without calling Sign. This is useful
+	// to not sign requests that have been waiting for approval for too long.
+	// This is disabled by default.
+	MaxRequestAge time.Duration
+
+	// SignTimeout is the maximum duration of a single Sign call, the context
+	// passed to Sign is cancelled afterwards and the request is retried. If
+	// zero, the duration of a Sign call is not limited.
+	SignTimeout time.Duration
+
+	// MaxCSRSize is the maximum size in bytes of the PEM encoded CSR of a
+	// request, see CertificateRequestReconciler. If zero, DefaultMaxCSRSize
+	// is used.
+	MaxCSRSize int
+
+	// KubernetesSignerNames maps well-known Kubernetes signerNames (eg.
+	// "kubernetes.io/kubelet-serving") to a signerName in the format
+	// "<issuer-type-id>/<issuer-id>". This allows a ClusterIssuer to sign
+	// CertificateSigningRequests for the built-in Kubernetes signers. Before
+	// signing such a request, the constraints that Kubernetes mandates for the
+	// signer are validated. Requests that don't meet these constraints are
+	// marked as Failed.
+	KubernetesSignerNames map[string]string
+
+	// VirtualIssuers are signers that are not backed by an issuer resource,
+	// the Kubernetes CSRs are matched using only their signerName. See
+	// VirtualIssuer.
+	VirtualIssuers []VirtualIssuer
+
+	// PendingCertificateRequestResyncInterval is the interval at which requests
+	// that are waiting for their issuer to exist or to become Ready are
+	// reconciled again. Normally, these requests are reconciled as soon as the
+	// issuer changes, this resync is a low-frequency backstop in case that
+	// trigger was missed (eg. during a controller restart). It should be set
+	// to a large value (eg. 10 minutes). This is disabled by default.
+	PendingCertificateRequestResyncInterval time.Duration
+
 	// Client is a controller-runtime client used to get and set K8S API resources
 	client.Client
 	// Sign connects to a CA and returns a signed certificate for the supplied CertificateRequest.
 	signer.Sign
+	// AfterSign is an optional function that is called after a successful Sign,
+	// before the CertificateRequest is marked as Ready.
+	signer.AfterSign
 	// IgnoreCertificateRequest is an optional function that can prevent the CertificateRequest
 	// and Kubernetes CSR controllers from reconciling a CertificateRequest resource.
 	signer.IgnoreCertificateRequest
+	// IgnoreIssuer is an optional function that can prevent the issuer controllers from
+	// reconciling an issuer resource. The requests that reference an ignored issuer
+	// are ignored too.
+	signer.IgnoreIssuer
+	// GetNameConstraints is an optional function that returns the name constraints
+	// of an issuer, which are validated before calling Sign.
+	signer.GetNameConstraints
+
+	// QuotaCheck is an optional function that is called before the Sign
+	// function to enforce a quota (see signer.QuotaCheck). If the quota is
+	// exceeded, the QuotaExceeded condition is set and the request is retried.
+	signer.QuotaCheck
+
+	// HTTPClientProvider is an optional function that returns a pre-configured
+	// HTTP client (eg. with proxy, TLS trust and timeout settings) that is
+	// passed to the Sign and AfterSign functions using the context. The client can be retrieved using
+	// signer.HTTPClientFromContext.
+	HTTPClientProvider func() *http.Client
+
+	// APIReader is an optional reader that is passed to the Sign and AfterSign
+	// functions using the context, so they can read Kubernetes objects using the
+	// credentials of the controller. It can be retrieved using
+	// signer.APIReaderFromContext. The CombinedController sets it to the
+	// (uncached) API reader of the manager.
+	APIReader client.Reader
 
 	// EventRecorder is used for creating Kubernetes events on resources.
 	EventRecorder record.EventRecorder
 
-	// Clock is used to mock condition transition times in tests.
+	// Clock is used to mock condition transition times in tests. It is also
+	// used to determine whether the MaxRetryDuration has passed, so tests can
+	// expire it deterministically using a fake clock.
 	Clock clock.PassiveClock
 
+	// CheckChainCompleteness is used to verify that the certificate chain
+	// returned by the Sign function can be verified up to a self-signed root
+	// certificate that is part of the returned bundle. If the chain is
+	// incomplete, the IncompleteChain condition is set and a Warning event is
+	// emitted. This is disabled by default.
+	CheckChainCompleteness bool
+
+	// RetryIncompleteChain is used to handle an incomplete chain as a retryable
+	// Sign error, instead of only setting the IncompleteChain condition. This
+	// option is only used if CheckChainCompleteness is enabled.
+	RetryIncompleteChain bool
+
+	// RequireSCT is used to verify that the certificate returned by the Sign
+	// function contains embedded signed certificate timestamps (SCTs), as is
+	// required for publicly trusted certificates. A certificate without enough
+	// SCTs is rejected and the request is marked as Failed. This is disabled by
+	// default.
+	RequireSCT bool
+
+	// MinimumSCTs is the minimum number of embedded SCTs that is required when
+	// RequireSCT is enabled. Defaults to 1.
+	MinimumSCTs int
+
+	// RequireExactSANs is used to verify that the certificate returned by the
+	// Sign function contains exactly the subject alternative names that were
+	// requested. A certificate with added or missing SANs is handled as a
+	// retryable Sign error, which catches CAs that are misconfigured to
+	// silently change the SANs. This is disabled by default.
+	RequireExactSANs bool
+
+	// DeduplicateSANs removes duplicate subject alternative names (eg. the same
+	// DNS name twice, or with a different case) from the request that is passed
+	// to the Sign function, see signer.DeduplicateSANs. The removed SANs are
+	// recorded in a "SANsDeduplicated" event. The raw CSR is not changed.
+	DeduplicateSANs bool
+
+	// RejectConflictingSANs fails requests with conflicting subject alternative
+	// names (eg. an IP address requested as a DNS name) permanently, without
+	// calling the Sign function, see signer.ConflictingSANs.
+	RejectConflictingSANs bool
+
+	// IsPermanent is an optional function that classifies additional Sign
+	// errors as permanent, in addition to the errors that wrap a
+	// signer.PermanentError. This can be used to handle the sentinel errors of
+	// a backend library without wrapping them.
+	IsPermanent func(error) bool
+
+	// EscalateToIssuer is an optional function that decides which Sign errors
+	// are reported to the issuer, see CertificateRequestReconciler. Errors of
+	// virtual issuers are never reported, because they have no status.
+	EscalateToIssuer func(error) bool
+
+	// TreatAllErrorsAsRetryable is a debugging option that handles all Sign
+	// errors as retryable errors, including PermanentErrors. Requests are
+	// then only failed once MaxRetryDuration has passed. This is useful while
+	// developing a new signer, but it is unsafe to use in production: requests
+	// that can never succeed are retried until they time out.
+	TreatAllErrorsAsRetryable bool
+
+	// Logger is an optional logger that is used instead of the manager's logger.
+	// This can be used to inject a pre-configured logger, eg. to filter the
+	// verbosity levels used by the controller (see README.md).
+	Logger logr.Logger
+
+	// DisableForceApply is used to apply status patches without forcing the
+	// ownership of fields that are managed by another field manager. When this
+	// option is set, such conflicts are returned as errors instead of being
+	// overwritten. Forcing is enabled by default.
+	DisableForceApply bool
+
+	// CacheSyncTimeout is the maximum duration that the controller waits for
+	// its informer caches to sync when it starts. This can be increased on
+	// large clusters where the initial sync takes longer. If zero, the manager's
+	// controller options are used, which default to 2 minutes.
+	CacheSyncTimeout time.Duration
+
+	// MaxBackoff caps the exponential backoff of the controller's rate limiter,
+	// so that eg. a CA that becomes available again is detected within a
+	// bounded time. If zero, the backoff of the default rate limiter is used,
+	// which grows up to 1000 seconds.
+	MaxBackoff time.Duration
+
+	// AuditResyncInterval is the interval at which all the outstanding
+	// Kubernetes CSRs are reconciled again, see CertificateRequestReconciler.
+	AuditResyncInterval time.Duration
+
+	// PreSetupWithManager is an optional function that is called before the
+	// controller is built. It can be used to customize the controller builder,
+	// eg. to set a custom rate limiter using WithOptions or to add event filters.
+	// The Logger, CacheSyncTimeout and MaxBackoff options of the reconciler
+	// are set on the builder before the hook is called. WithOptions replaces
+	// all the options, so a hook that calls WithOptions has to set these
+	// options again if it wants to keep them.
+	PreSetupWithManager func(context.Context, schema.GroupVersionKind, ctrl.Manager, *builder.Builder) error
+	// PostSetupWithManager is an optional function that is called after the
+	// controller is built, eg. to add extra watches or health checks.
 	PostSetupWithManager func(context.Context, schema.GroupVersionKind, ctrl.Manager, controller.Controller) error
 }
 
@@ -93,7 +259,7 @@ func (r *CertificateSigningRequestReconciler) Reconcile(ctx context.Context, req
 		if err := r.Client.Status().Patch(ctx, &cr, patch, &client.SubResourcePatchOptions{
 			PatchOptions: client.PatchOptions{
 				FieldManager: r.FieldOwner,
-				Force:        ptr.To(true),
+				Force:        ptr.To(!r.DisableForceApply),
 			},
 		}); err != nil {
 			if err := client.IgnoreNotFound(err); err != nil {
@@ -111,6 +277,13 @@ func (r *CertificateSigningRequestReconciler) reconcileStatusPatch(
 	ctx context.Context,
 	req ctrl.Request,
 ) (result ctrl.Result, csrStatusPatch *certificatesv1.CertificateSigningRequestStatus, returnedError error) {
+	if r.HTTPClientProvider != nil {
+		ctx = signer.ContextWithHTTPClient(ctx, r.HTTPClientProvider())
+	}
+	if r.APIReader != nil {
+		ctx = signer.ContextWithAPIReader(ctx, r.APIReader)
+	}
+
 	var csr certificatesv1.CertificateSigningRequest
 	if err := r.Client.Get(ctx, req.NamespacedName, &csr); err != nil && apierrors.IsNotFound(err) {
 		logger.V(1).Info("Not found. Ignoring.")
@@ -126,17 +299,33 @@ func (r *CertificateSigningRequestReconciler) reconcileStatusPatch(
 		return result, nil, nil // done
 	}
 
-	// Select first matching issuer type and construct an issuerObject and issuerName
-	issuerObject, issuerName, err := r.matchIssuerType(&csr)
-	// Ignore CertificateRequest if issuerRef doesn't match one of our issuer Types
-	if err != nil {
-		logger.V(1).Info("Foreign issuer. Ignoring.", "error", err)
-		return result, nil, nil // done
+	var issuerObject v1alpha1.Issuer
+	var issuerName types.NamespacedName
+	var err error
+	sign := r.Sign
+	virtualIssuer := r.matchVirtualIssuer(&csr)
+	if virtualIssuer != nil {
+		issuerObject = virtualIssuer.Issuer.DeepCopyObject().(v1alpha1.Issuer)
+		issuerName = types.NamespacedName{Name: csr.Spec.SignerName}
+		sign = virtualIssuer.Sign
+	} else {
+		// Select first matching issuer type and construct an issuerObject and issuerName
+		issuerObject, issuerName, err = r.matchIssuerType(&csr)
+		// Ignore CertificateRequest if issuerRef doesn't match one of our issuer Types
+		if err != nil {
+			logger.V(1).Info("Foreign issuer. Ignoring.", "error", err)
+			return result, nil, nil // done
+		}
 	}
 	issuerGvk := issuerObject.GetObjectKind().GroupVersionKind()
 
-	// Ignore CertificateRequest if it is already Ready
+	// Ignore CertificateRequest if it is already Ready, unless the AfterSign
+	// function still has to be retried for its certificate.
 	if len(csr.Status.Certificate) > 0 {
+		if r.AfterSign != nil && afterSignPending(&csr) {
+			return r.retryAfterSign(logger, ctx, &csr, issuerObject, issuerName, virtualIssuer != nil)
+		}
+
 		logger.V(1).Info("CertificateSigningRequest is Ready. Ignoring.")
 		return result, nil, nil // done
 	}
@@ -168,43 +357,152 @@ func (r *CertificateSigningRequestReconciler) reconcileStatusPatch(
 	// for updating its Status.
 	csrStatusPatch = &certificatesv1.CertificateSigningRequestStatus{}
 
-	if err := r.Client.Get(ctx, issuerName, issuerObject); err != nil && apierrors.IsNotFound(err) {
-		logger.V(1).Info("Issuer not found. Waiting for it to be created")
-		r.EventRecorder.Eventf(&csr, corev1.EventTypeNormal, "WaitingForIssuerExist", "Waiting for the issuer to exist")
+	// Fail permanently if the request is too old to still be signed.
+	if r.MaxRequestAge > 0 && r.Clock.Now().After(csr.CreationTimestamp.Add(r.MaxRequestAge)) {
+		logger.V(1).Info("CertificateSigningRequest is older than the maximum request age. Marking as failed.")
+		conditions.SetCertificateSigningRequestStatusCondition(
+			r.Clock,
+			csr.Status.Conditions,
+			&csrStatusPatch.Conditions,
+			certificatesv1.CertificateFailed,
+			corev1.ConditionTrue,
+			reasonRequestExpired,
+			fmt.Sprintf("CertificateRequest has failed permanently: the request is older than the maximum request age of %s", r.MaxRequestAge),
+		)
+		r.EventRecorder.Eventf(&csr, corev1.EventTypeWarning, reasonRequestExpired, "The request is older than the maximum request age of %s, not signing it", r.MaxRequestAge)
 		return result, csrStatusPatch, nil // done, apply patch
-	} else if err != nil {
-		r.EventRecorder.Eventf(&csr, corev1.EventTypeWarning, "UnexpectedError", "Got an unexpected error while processing the CR")
-		return result, nil, fmt.Errorf("unexpected get error: %v", err) // retry
 	}
 
-	readyCondition := conditions.GetIssuerStatusCondition(
-		issuerObject.GetStatus().Conditions,
-		cmapi.IssuerConditionReady,
-	)
-	if (readyCondition == nil) ||
-		(readyCondition.Status != cmmeta.ConditionTrue) ||
-		(readyCondition.ObservedGeneration < issuerObject.GetGeneration()) {
-
-		logger.V(1).Info("Issuer is not Ready yet. Waiting for it to become ready.", "issuer ready condition", readyCondition)
-		r.EventRecorder.Eventf(&csr, corev1.EventTypeNormal, "WaitingForIssuerReady", "Waiting for the issuer to become ready")
+	// Fail permanently if the CSR is too large, before it is parsed.
+	if maxCSRSize := maxCSRSizeOrDefault(r.MaxCSRSize); len(csr.Spec.Request) > maxCSRSize {
+		logger.V(1).Info("CertificateSigningRequest has a CSR that is larger than the maximum size. Marking as failed.", "size", len(csr.Spec.Request), "maxSize", maxCSRSize)
+		conditions.SetCertificateSigningRequestStatusCondition(
+			r.Clock,
+			csr.Status.Conditions,
+			&csrStatusPatch.Conditions,
+			certificatesv1.CertificateFailed,
+			corev1.ConditionTrue,
+			reasonRequestTooLarge,
+			fmt.Sprintf("CertificateRequest has failed permanently: the CSR is %d bytes, which is larger than the maximum size of %d bytes", len(csr.Spec.Request), maxCSRSize),
+		)
+		r.EventRecorder.Eventf(&csr, corev1.EventTypeWarning, reasonRequestTooLarge, "The CSR is %d bytes, which is larger than the maximum size of %d bytes, not signing it", len(csr.Spec.Request), maxCSRSize)
 		return result, csrStatusPatch, nil // done, apply patch
 	}
 
-	signedCertificate, err := r.Sign(log.IntoContext(ctx, logger), signer.CertificateRequestObjectFromCertificateSigningRequest(&csr), issuerObject)
+	if virtualIssuer != nil {
+		// There is no issuer resource and thus no Ready condition, the
+		// optional Check function is called instead.
+		if virtualIssuer.Check != nil {
+			if err := virtualIssuer.Check(log.IntoContext(ctx, logger), issuerObject); err != nil {
+				logger.V(1).Error(err, "Virtual issuer is not ready. Will retry.")
+				r.EventRecorder.Eventf(&csr, corev1.EventTypeWarning, "VirtualIssuerNotReady", "The virtual issuer is not ready, will retry: %s", err)
+				result.Requeue = true
+				return result, csrStatusPatch, nil // requeue with backoff, apply patch
+			}
+		}
+	} else {
+		if err := r.Client.Get(ctx, issuerName, issuerObject); err != nil && apierrors.IsNotFound(err) {
+			logger.V(1).Info("Issuer not found. Waiting for it to be created")
+			result.RequeueAfter = r.PendingCertificateRequestResyncInterval // resync backstop
+			r.EventRecorder.Eventf(&csr, corev1.EventTypeNormal, "WaitingForIssuerExist", "Waiting for the issuer to exist")
+			return result, csrStatusPatch, nil // done, apply patch
+		} else if err != nil {
+			r.EventRecorder.Eventf(&csr, corev1.EventTypeWarning, "UnexpectedError", "Got an unexpected error while processing the CR")
+			return result, nil, fmt.Errorf("unexpected get error: %v", err) // retry
+		}
+
+		if r.IgnoreIssuer != nil {
+			ignore, err := r.IgnoreIssuer(ctx, issuerObject)
+			if err != nil {
+				return result, nil, fmt.Errorf("failed to check if issuer should be ignored: %v", err) // retry
+			}
+			if ignore {
+				// The issuer is handled by a different controller, and so are the
+				// requests that reference it.
+				logger.V(1).Info("IgnoreIssuer() returned true for the issuer. Ignoring.")
+				return result, nil, nil // done
+			}
+		}
+
+		readyCondition := conditions.GetIssuerStatusCondition(
+			issuerObject.GetStatus().Conditions,
+			cmapi.IssuerConditionReady,
+		)
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionTrue) ||
+			(readyCondition.ObservedGeneration < issuerObject.GetGeneration()) {
+
+			logger.V(1).Info("Issuer is not Ready yet. Waiting for it to become ready.", "issuer ready condition", readyCondition)
+			result.RequeueAfter = r.PendingCertificateRequestResyncInterval // resync backstop
+			r.EventRecorder.Eventf(&csr, corev1.EventTypeNormal, "WaitingForIssuerReady", "Waiting for the issuer to become ready")
+			return result, csrStatusPatch, nil // done, apply patch
+		}
+	}
+
+	// Validate the request against the constraints of the well-known Kubernetes
+	// signer it is addressed to, the name constraints of the issuer and the
+	// SAN normalization options. All violations are reported together and fail
+	// the request permanently.
+	var signedCertificate signer.PEMBundle
+	csrObject, removedSANs, sanErr := normalizeSANs(signer.CertificateRequestObjectFromCertificateSigningRequest(&csr), r.DeduplicateSANs, r.RejectConflictingSANs)
+	err = joinValidationErrors(
+		validateKubernetesSignerConstraints(&csr),
+		checkNameConstraints(ctx, r.GetNameConstraints, csrObject, issuerObject),
+		sanErr,
+	)
+	if err == nil && len(removedSANs) > 0 {
+		r.EventRecorder.Eventf(&csr, corev1.EventTypeNormal, "SANsDeduplicated", "Removed duplicate SANs from the request before signing: %s", strings.Join(removedSANs, ", "))
+	}
+	if err == nil {
+		err = checkQuota(ctx, r.QuotaCheck, csrObject, issuerObject)
+	}
+	if err == nil {
+		signCtx := log.IntoContext(ctx, logger)
+		if r.SignTimeout > 0 {
+			var cancel context.CancelFunc
+			signCtx, cancel = context.WithTimeout(signCtx, r.SignTimeout)
+			defer cancel()
+		}
+
+		signedCertificate, err = sign(signCtx, csrObject, issuerObject)
+	}
+	if err == nil && !signedCertificate.NotAfter.IsZero() {
+		// Verify that the NotAfter returned by the signer matches the certificate.
+		_, err = signedCertificate.CertificateNotAfter()
+	}
+	if err == nil && r.RequireSCT {
+		if sctErr := verifySCTs(signedCertificate, r.MinimumSCTs); sctErr != nil {
+			// Retrying won't help, the CA profile has to be fixed.
+			err = signer.PermanentError{Err: sctErr}
+		}
+	}
+	if err == nil && r.RequireExactSANs {
+		err = verifyExactSANs(signedCertificate, csrObject) // handled as a retryable Sign error below
+	}
+	if err == nil && r.CheckChainCompleteness {
+		chainErr := verifyChainCompleteness(signedCertificate, r.Clock.Now())
+		if chainErr != nil && r.RetryIncompleteChain {
+			err = chainErr // handled as a retryable Sign error below
+		} else {
+			r.setIncompleteChainCondition(logger, &csr, csrStatusPatch, chainErr)
+		}
+	}
 	if err != nil {
 		// An error in the issuer part of the operator should trigger a reconcile
-		// of the issuer's state.
-		if issuerError := new(signer.IssuerError); errors.As(err, issuerError) {
+		// of the issuer's state. Virtual issuers have no state, so for them it
+		// is handled like any other error.
+		if issuerError, escalate := escalatedIssuerError(err, r.EscalateToIssuer); virtualIssuer == nil && escalate {
 			if reportError := r.EventSource.ReportError(
 				issuerGvk, client.ObjectKeyFromObject(issuerObject),
-				issuerError.Err,
+				issuerError,
 			); reportError != nil {
 				err = utilerrors.NewAggregate([]error{err, reportError})
 			}
 
 			logger.V(1).Error(err, "Temporary CertificateRequest error.")
 
-			r.EventRecorder.Eventf(&csr, corev1.EventTypeWarning, "WaitingForIssuerReady", "Waiting for the issuer to become ready")
+			result.RequeueAfter = r.PendingCertificateRequestResyncInterval // resync backstop
+			r.EventRecorder.Eventf(&csr, corev1.EventTypeWarning, "IssuerErrorReported", "Sign reported an issuer error, waiting for the issuer to become ready again: %v", issuerError)
 			return result, csrStatusPatch, nil // done, apply patch
 		}
 
@@ -228,8 +526,9 @@ func (r *CertificateSigningRequestReconciler) reconcileStatusPatch(
 		}
 
 		// Check if we have still time to requeue & retry
-		isPendingError := errors.As(err, &signer.PendingError{})
-		isPermanentError := errors.As(err, &signer.PermanentError{})
+		pendingError := signer.PendingError{}
+		isPendingError := errors.As(err, &pendingError)
+		isPermanentError := !r.TreatAllErrorsAsRetryable && isPermanentError(err, r.IsPermanent)
 		pastMaxRetryDuration := r.Clock.Now().After(csr.CreationTimestamp.Add(r.MaxRetryDuration))
 		if !isPendingError && (isPermanentError || pastMaxRetryDuration) {
 			// fail permanently
@@ -251,6 +550,12 @@ func (r *CertificateSigningRequestReconciler) reconcileStatusPatch(
 			logger.V(1).Error(err, "Retryable CertificateRequest error.")
 
 			r.EventRecorder.Eventf(&csr, corev1.EventTypeWarning, "RetryableError", "Failed to sign CertificateRequest, will retry: %s", err)
+			if isPendingError && pendingError.RequeueAfter > 0 {
+				// the signer indicated when the request should be reconciled again
+				result.RequeueAfter = pendingError.RequeueAfter
+				return result, csrStatusPatch, nil // requeue after the requested duration, apply patch
+			}
+
 			if didCustomConditionTransition {
 				// the reconciliation loop will be retriggered because of the added/ changed custom condition
 				return result, csrStatusPatch, nil // done, apply patch
@@ -269,19 +574,201 @@ func (r *CertificateSigningRequestReconciler) reconcileStatusPatch(
 		}
 	}
 
+	for _, warning := range signedCertificate.Warnings {
+		r.EventRecorder.Event(&csr, corev1.EventTypeWarning, reasonSignWarning, warning)
+	}
+
+	annotations, invalidArtifacts := signerAnnotations(signedCertificate)
+	if len(invalidArtifacts) > 0 {
+		logger.V(1).Info("Ignoring artifacts with an invalid name.", "artifacts", invalidArtifacts)
+		r.EventRecorder.Eventf(&csr, corev1.EventTypeWarning, "IgnoredArtifacts", "Ignored artifacts with a name that is not a valid annotation name: %q", invalidArtifacts)
+	}
+
+	ignoredAnnotations, err := applySignerAnnotations(ctx, r.Client, &csr, annotations)
+	if err != nil {
+		return result, nil, fmt.Errorf("failed to set annotations: %v", err) // retry
+	}
+	if len(ignoredAnnotations) > 0 {
+		logger.V(1).Info("Ignoring annotations with a reserved prefix.", "annotations", ignoredAnnotations)
+		r.EventRecorder.Eventf(&csr, corev1.EventTypeWarning, "IgnoredAnnotations", "Ignored annotations with the reserved %q prefix: %q", reservedAnnotationPrefix, ignoredAnnotations)
+	}
+
 	csrStatusPatch.Certificate = signedCertificate.ChainPEM
 
+	if r.AfterSign != nil {
+		if err := r.AfterSign(log.IntoContext(ctx, logger), signer.CertificateRequestObjectFromCertificateSigningRequest(&csr), issuerObject, signedCertificate); err != nil {
+			// A Kubernetes CSR is issued as soon as the certificate is set in
+			// the status, so AfterSign is retried in later reconciles using
+			// the stored certificate.
+			result = r.setAfterSignError(logger, &csr, csrStatusPatch, err)
+		}
+	}
+
 	logger.V(1).Info("Successfully finished the reconciliation.")
 	r.EventRecorder.Eventf(&csr, corev1.EventTypeNormal, "Issued", "Succeeded signing the CertificateRequest")
 	return result, csrStatusPatch, nil // done, apply patch
 }
 
+// afterSignPending returns true if the AfterSign function failed for the
+// certificate in the status of the CSR and has to be retried.
+func afterSignPending(csr *certificatesv1.CertificateSigningRequest) bool {
+	condition := conditions.GetCertificateSigningRequestStatusCondition(csr.Status.Conditions, v1alpha1.CertificateSigningRequestConditionAfterSignPending)
+	return (condition != nil) && (condition.Status == corev1.ConditionTrue)
+}
+
+// retryAfterSign calls the AfterSign function again for the certificate that
+// is stored in the status of the CSR. The certificate is not signed again.
+func (r *CertificateSigningRequestReconciler) retryAfterSign(
+	logger logr.Logger,
+	ctx context.Context,
+	csr *certificatesv1.CertificateSigningRequest,
+	issuerObject v1alpha1.Issuer,
+	issuerName types.NamespacedName,
+	virtualIssuer bool,
+) (result ctrl.Result, csrStatusPatch *certificatesv1.CertificateSigningRequestStatus, returnedError error) {
+	if !virtualIssuer {
+		if err := r.Client.Get(ctx, issuerName, issuerObject); err != nil {
+			return result, nil, fmt.Errorf("failed to get the issuer to retry AfterSign: %v", err) // retry
+		}
+	}
+
+	// The certificate and the IncompleteChain condition would be removed if
+	// they were omitted from the patch.
+	csrStatusPatch = &certificatesv1.CertificateSigningRequestStatus{
+		Certificate: csr.Status.Certificate,
+	}
+	if condition := conditions.GetCertificateSigningRequestStatusCondition(csr.Status.Conditions, v1alpha1.CertificateRequestConditionIncompleteChain); condition != nil {
+		csrStatusPatch.Conditions = append(csrStatusPatch.Conditions, *condition)
+	}
+
+	logger.V(1).Info("Certificate was already signed, retrying AfterSign.")
+	bundle := signer.PEMBundle{ChainPEM: csr.Status.Certificate}
+	if err := r.AfterSign(log.IntoContext(ctx, logger), signer.CertificateRequestObjectFromCertificateSigningRequest(csr), issuerObject, bundle); err != nil {
+		return r.setAfterSignError(logger, csr, csrStatusPatch, err), csrStatusPatch, nil // apply patch
+	}
+
+	logger.V(1).Info("Successfully retried AfterSign.")
+	conditions.SetCertificateSigningRequestStatusCondition(
+		r.Clock,
+		csr.Status.Conditions,
+		&csrStatusPatch.Conditions,
+		v1alpha1.CertificateSigningRequestConditionAfterSignPending,
+		corev1.ConditionFalse,
+		v1alpha1.CertificateSigningRequestConditionReasonAfterSignSucceeded,
+		"Succeeded running AfterSign",
+	)
+	r.EventRecorder.Event(csr, corev1.EventTypeNormal, v1alpha1.CertificateSigningRequestConditionReasonAfterSignSucceeded, "Succeeded running AfterSign")
+	return result, csrStatusPatch, nil // done, apply patch
+}
+
+// setAfterSignError sets the AfterSignPending condition for an AfterSign
+// error. Like Sign errors, AfterSign errors are retried until the
+// MaxRetryDuration has passed, unless they are permanent. The certificate is
+// issued anyway, so it is kept in the status.
+func (r *CertificateSigningRequestReconciler) setAfterSignError(
+	logger logr.Logger,
+	csr *certificatesv1.CertificateSigningRequest,
+	csrStatusPatch *certificatesv1.CertificateSigningRequestStatus,
+	err error,
+) ctrl.Result {
+	isPermanentError := !r.TreatAllErrorsAsRetryable && isPermanentError(err, r.IsPermanent)
+	pastMaxRetryDuration := r.Clock.Now().After(csr.CreationTimestamp.Add(r.MaxRetryDuration))
+	if isPermanentError || pastMaxRetryDuration {
+		logger.V(1).Error(err, "Permanent AfterSign error. Not retrying.")
+		conditions.SetCertificateSigningRequestStatusCondition(
+			r.Clock,
+			csr.Status.Conditions,
+			&csrStatusPatch.Conditions,
+			v1alpha1.CertificateSigningRequestConditionAfterSignPending,
+			corev1.ConditionFalse,
+			v1alpha1.CertificateSigningRequestConditionReasonAfterSignFailed,
+			fmt.Sprintf("AfterSign has failed permanently: %s", err),
+		)
+		r.EventRecorder.Eventf(csr, corev1.EventTypeWarning, "PermanentError", "Failed permanently to run AfterSign: %s", err)
+		return ctrl.Result{} // done
+	}
+
+	logger.V(1).Error(err, "AfterSign error.")
+	conditions.SetCertificateSigningRequestStatusCondition(
+		r.Clock,
+		csr.Status.Conditions,
+		&csrStatusPatch.Conditions,
+		v1alpha1.CertificateSigningRequestConditionAfterSignPending,
+		corev1.ConditionTrue,
+		v1alpha1.CertificateSigningRequestConditionReasonAfterSignError,
+		fmt.Sprintf("CertificateSigningRequest was signed, but AfterSign failed: %s", err),
+	)
+	r.EventRecorder.Eventf(csr, corev1.EventTypeWarning, "AfterSignError", "Failed to run AfterSign, will retry: %s", err)
+	return ctrl.Result{Requeue: true} // requeue with backoff
+}
+
+// setIncompleteChainCondition sets the IncompleteChain condition if the chain
+// is incomplete. If the chain is complete, an existing IncompleteChain
+// condition is set to False.
+func (r *CertificateSigningRequestReconciler) setIncompleteChainCondition(
+	logger logr.Logger,
+	csr *certificatesv1.CertificateSigningRequest,
+	csrStatusPatch *certificatesv1.CertificateSigningRequestStatus,
+	chainErr error,
+) {
+	conditionType := certificatesv1.RequestConditionType(v1alpha1.CertificateRequestConditionIncompleteChain)
+
+	if chainErr == nil {
+		if conditions.GetCertificateSigningRequestStatusCondition(csr.Status.Conditions, conditionType) != nil {
+			conditions.SetCertificateSigningRequestStatusCondition(
+				r.Clock,
+				csr.Status.Conditions,
+				&csrStatusPatch.Conditions,
+				conditionType,
+				corev1.ConditionFalse,
+				v1alpha1.CertificateRequestConditionReasonCompleteChain,
+				"The certificate chain is complete",
+			)
+		}
+		return
+	}
+
+	logger.V(1).Info("Certificate chain is incomplete.", "error", chainErr)
+	conditions.SetCertificateSigningRequestStatusCondition(
+		r.Clock,
+		csr.Status.Conditions,
+		&csrStatusPatch.Conditions,
+		conditionType,
+		corev1.ConditionTrue,
+		v1alpha1.CertificateRequestConditionReasonIncompleteChain,
+		chainErr.Error(),
+	)
+	r.EventRecorder.Eventf(csr, corev1.EventTypeWarning, "IncompleteChain", "The signed certificate chain is incomplete: %s", chainErr)
+}
+
 func (r *CertificateSigningRequestReconciler) setIssuersGroupVersionKind(scheme *runtime.Scheme) error {
 	for _, issuerType := range r.allIssuerTypes() {
 		if err := kubeutil.SetGroupVersionKind(scheme, issuerType); err != nil {
 			return err
 		}
 	}
+	for _, virtualIssuer := range r.VirtualIssuers {
+		if err := kubeutil.SetGroupVersionKind(scheme, virtualIssuer.Issuer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchVirtualIssuer returns the VirtualIssuer that signs the signerName of the
+// CertificateSigningRequest, or nil if there is none.
+func (r *CertificateSigningRequestReconciler) matchVirtualIssuer(csr *certificatesv1.CertificateSigningRequest) *VirtualIssuer {
+	if csr == nil {
+		return nil
+	}
+
+	for i := range r.VirtualIssuers {
+		for _, signerName := range r.VirtualIssuers[i].SignerNames {
+			if signerName == csr.Spec.SignerName {
+				return &r.VirtualIssuers[i]
+			}
+		}
+	}
 	return nil
 }
 
@@ -292,14 +779,20 @@ func (r *CertificateSigningRequestReconciler) setIssuersGroupVersionKind(scheme
 // "<issuer-type-id>/<issuer-id>". The issuer-type-id is obtained from the
 // GetIssuerTypeIdentifier function of the IssuerType.
 // The issuer-id is "<name>" for a ClusterIssuer resource.
+// Well-known Kubernetes signerNames are first mapped using KubernetesSignerNames.
 func (r *CertificateSigningRequestReconciler) matchIssuerType(csr *certificatesv1.CertificateSigningRequest) (v1alpha1.Issuer, types.NamespacedName, error) {
 	if csr == nil {
 		return nil, types.NamespacedName{}, fmt.Errorf("invalid signer name, should have format <issuer-type-id>/<issuer-id>")
 	}
 
-	split := strings.Split(csr.Spec.SignerName, "/")
+	signerName := csr.Spec.SignerName
+	if mappedSignerName, ok := r.KubernetesSignerNames[signerName]; ok {
+		signerName = mappedSignerName
+	}
+
+	split := strings.Split(signerName, "/")
 	if len(split) != 2 {
-		return nil, types.NamespacedName{}, fmt.Errorf("invalid signer name, should have format <issuer-type-id>/<issuer-id>: %q", csr.Spec.SignerName)
+		return nil, types.NamespacedName{}, fmt.Errorf("invalid signer name, should have format <issuer-type-id>/<issuer-id>: %q", signerName)
 	}
 
 	issuerTypeIdentifier := split[0]
@@ -327,7 +820,7 @@ func (r *CertificateSigningRequestReconciler) matchIssuerType(csr *certificatesv
 		return issuerObject, issuerName, nil
 	}
 
-	return nil, types.NamespacedName{}, fmt.Errorf("no issuer found for signer name: %q", csr.Spec.SignerName)
+	return nil, types.NamespacedName{}, fmt.Errorf("no issuer found for signer name: %q", signerName)
 }
 
 func (r *CertificateSigningRequestReconciler) allIssuerTypes() []v1alpha1.Issuer {
@@ -337,6 +830,46 @@ func (r *CertificateSigningRequestReconciler) allIssuerTypes() []v1alpha1.Issuer
 	return issuers
 }
 
+// issuerGroupKind returns the GroupKind of the issuer type that is responsible
+// for the CertificateSigningRequest, it is used to select the event recorder.
+func (r *CertificateSigningRequestReconciler) issuerGroupKind(object runtime.Object) (schema.GroupKind, bool) {
+	csr, ok := object.(*certificatesv1.CertificateSigningRequest)
+	if !ok {
+		return schema.GroupKind{}, false
+	}
+
+	if virtualIssuer := r.matchVirtualIssuer(csr); virtualIssuer != nil {
+		return virtualIssuer.Issuer.GetObjectKind().GroupVersionKind().GroupKind(), true
+	}
+
+	issuerType, _, err := r.matchIssuerType(csr)
+	if err != nil {
+		return schema.GroupKind{}, false
+	}
+
+	return issuerType.GetObjectKind().GroupVersionKind().GroupKind(), true
+}
+
+// issuerKey returns a key that identifies the issuer that the Kubernetes CSR
+// is addressed to.
+func (r *CertificateSigningRequestReconciler) issuerKey(object runtime.Object) (string, bool) {
+	csr, ok := object.(*certificatesv1.CertificateSigningRequest)
+	if !ok {
+		return "", false
+	}
+
+	if virtualIssuer := r.matchVirtualIssuer(csr); virtualIssuer != nil {
+		return "virtual/" + csr.Spec.SignerName, true
+	}
+
+	issuerType, issuerName, err := r.matchIssuerType(csr)
+	if err != nil {
+		return "", false
+	}
+
+	return issuerType.GetObjectKind().GroupVersionKind().GroupKind().String() + "/" + issuerName.String(), true
+}
+
 // SetupWithManager sets up the controller with the Manager.
 //
 // It ensures that the Manager scheme has all the types that are needed by this controller.
@@ -357,6 +890,18 @@ func (r *CertificateSigningRequestReconciler) SetupWithManager(ctx context.Conte
 		return err
 	}
 
+	for _, issuerType := range r.allIssuerTypes() {
+		if err := ValidateIssuerType(mgr.GetScheme(), issuerType); err != nil {
+			return err
+		}
+	}
+
+	for i := range r.VirtualIssuers {
+		if err := r.VirtualIssuers[i].validate(mgr.GetScheme()); err != nil {
+			return err
+		}
+	}
+
 	if err := r.setIssuersGroupVersionKind(mgr.GetScheme()); err != nil {
 		return err
 	}
@@ -369,8 +914,11 @@ func (r *CertificateSigningRequestReconciler) SetupWithManager(ctx context.Conte
 			// certificaterequest, this also prevents us to get in fast reconcile loop
 			// when setting the status to Pending causing the resource to update, while
 			// we only want to re-reconcile with backoff/ when a resource becomes available.
+			// Resources that have not been approved or denied yet are ignored by the
+			// reconciler, so we also filter out their events.
 			builder.WithPredicates(
 				predicate.ResourceVersionChangedPredicate{},
+				CertificateSigningRequestApprovedPredicate{},
 				CertificateSigningRequestPredicate{},
 			),
 		)
@@ -391,7 +939,10 @@ func (r *CertificateSigningRequestReconciler) SetupWithManager(ctx context.Conte
 		//
 		// The defaulting logic is based on:
 		// https://github.com/kubernetes-sigs/controller-runtime/blob/30eae58f1b984c1b8139dd9b9f68dd2d530ed429/pkg/controller/controller.go#L138-L144
-		timeout := mgr.GetControllerOptions().CacheSyncTimeout
+		timeout := r.CacheSyncTimeout
+		if timeout == 0 {
+			timeout = mgr.GetControllerOptions().CacheSyncTimeout
+		}
 		if timeout == 0 {
 			timeout = 2 * time.Minute
 		}
@@ -430,6 +981,31 @@ func (r *CertificateSigningRequestReconciler) SetupWithManager(ctx context.Conte
 		)
 	}
 
+	if r.AuditResyncInterval > 0 {
+		logger := mgr.GetLogger()
+		if r.Logger.GetSink() != nil {
+			logger = r.Logger
+		}
+
+		auditBuild, err := setupAuditResync(mgr, build, logger, "certificatesigningrequest", r.AuditResyncInterval, r.auditResyncObjects)
+		if err != nil {
+			return err
+		}
+		build = auditBuild
+	}
+
+	// The options are set before the PreSetupWithManager hook, so that the
+	// hook can override them.
+	build = build.WithOptions(controllerOptions(r.Logger, crType.GroupVersionKind(), r.CacheSyncTimeout, r.MaxBackoff))
+
+	if r.PreSetupWithManager != nil {
+		err := r.PreSetupWithManager(ctx, crType.GroupVersionKind(), mgr, build)
+		r.PreSetupWithManager = nil // free setup function
+		if err != nil {
+			return err
+		}
+	}
+
 	if controller, err := build.Build(r); err != nil {
 		return err
 	} else if r.PostSetupWithManager != nil {
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"testing"
+	"time"
+
+	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+	"github.com/cert-manager/issuer-lib/internal/kubeutil"
+	"github.com/cert-manager/issuer-lib/internal/tests/testcontext"
+	"github.com/cert-manager/issuer-lib/internal/tests/testresource"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+)
+
+// TestCertificateSigningRequestControllerIntegrationVirtualIssuer runs the
+// CertificateSigningRequestController against a real Kubernetes API server and
+// shows that a Kubernetes CSR that is addressed to a virtual issuer is signed,
+// without an issuer resource being created.
+func TestCertificateSigningRequestControllerIntegrationVirtualIssuer(t *testing.T) {
+	t.Parallel()
+
+	fieldOwner := "virtual-issuer"
+	signerName := "example.com/virtual-ca"
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	certificatePEM := newTestSANCertificate(t, "virtual.example.com")
+
+	ctx = setupControllersAPIServerAndClient(t, ctx, kubeClients,
+		func(mgr ctrl.Manager) controllerInterface {
+			return &CertificateSigningRequestReconciler{
+				FieldOwner:       fieldOwner,
+				MaxRetryDuration: time.Minute,
+				EventSource:      kubeutil.NewEventStore(),
+				Client:           mgr.GetClient(),
+				VirtualIssuers: []VirtualIssuer{
+					{
+						SignerNames: []string{signerName},
+						Issuer: &api.SimpleClusterIssuer{
+							ObjectMeta: metav1.ObjectMeta{Name: "virtual-ca"},
+						},
+						Sign: func(_ context.Context, _ signer.CertificateRequestObject, issuerObject v1alpha1.Issuer) (signer.PEMBundle, error) {
+							if issuerObject.GetName() != "virtual-ca" {
+								return signer.PEMBundle{}, fmt.Errorf("unexpected issuer: %s", issuerObject.GetName())
+							}
+							return signer.PEMBundle{
+								ChainPEM: certificatePEM,
+							}, nil
+						},
+					},
+				},
+				EventRecorder: record.NewFakeRecorder(100),
+				Clock:         clock.RealClock{},
+			}
+		},
+	)
+
+	csrPEM, _, err := cmgen.CSR(x509.ECDSA, cmgen.SetCSRDNSNames("virtual.example.com"))
+	require.NoError(t, err)
+
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "virtual-csr",
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    csrPEM,
+			SignerName: signerName,
+			Usages:     []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature},
+		},
+	}
+
+	checkComplete := kubeClients.StartObjectWatch(t, ctx, csr)
+	t.Log("Creating & approving the CertificateSigningRequest")
+	require.NoError(t, kubeClients.Client.Create(ctx, csr))
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:    certificatesv1.CertificateApproved,
+		Status:  corev1.ConditionTrue,
+		Reason:  "ApprovedReason",
+		Message: "ApprovedMessage",
+	})
+	require.NoError(t, kubeClients.Client.SubResource("approval").Update(ctx, csr))
+
+	t.Log("Waiting for the controller to sign the CertificateSigningRequest")
+	err = checkComplete(func(obj runtime.Object) error {
+		if len(obj.(*certificatesv1.CertificateSigningRequest).Status.Certificate) == 0 {
+			return fmt.Errorf("certificate is not set (yet)")
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"testing"
@@ -58,11 +60,18 @@ func TestCertificateSigningRequestReconcilerReconcile(t *testing.T) {
 	type testCase struct {
 		name                string
 		sign                signer.Sign
+		afterSign           signer.AfterSign
+		maxRequestAge       time.Duration
+		maxCSRSize          int
+		signTimeout         time.Duration
+		pendingResync       time.Duration
+		virtualIssuers      []VirtualIssuer
 		objects             []client.Object
 		validateError       *errormatch.Matcher
 		expectedResult      reconcile.Result
 		expectedStatusPatch *certificatesv1.CertificateSigningRequestStatus
 		expectedEvents      []string
+		expectedAnnotations map[string]string
 	}
 
 	randTime := randomTime()
@@ -116,6 +125,14 @@ func TestCertificateSigningRequestReconcilerReconcile(t *testing.T) {
 		},
 	)
 
+	kubeletServingRequest, _, err := cmgen.CSR(
+		x509.ECDSA,
+		setCSROrganization("system:nodes"),
+		cmgen.SetCSRCommonName("system:node:node-1"),
+		cmgen.SetCSRDNSNames("node-1.example.com"),
+	)
+	require.NoError(t, err)
+
 	successSigner := func(cert string) signer.Sign {
 		return func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
 			return signer.PEMBundle{
@@ -240,6 +257,32 @@ func TestCertificateSigningRequestReconcilerReconcile(t *testing.T) {
 			},
 		},
 
+		// If the resync interval is set, requeue the pending CertificateSigningRequest
+		// in case the issuer event that should trigger it is missed.
+		{
+			name:          "set-ready-pending-issuer-has-no-ready-condition-resync",
+			pendingResync: 10 * time.Minute,
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1, func(cr *certificatesv1.CertificateSigningRequest) {
+					cr.Spec.SignerName = fmt.Sprintf("%s/%s", clusterIssuer1.GetIssuerTypeIdentifier(), clusterIssuer1.Name)
+				}),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1,
+					func(si *api.SimpleClusterIssuer) {
+						si.Status.Conditions = nil
+					},
+				),
+			},
+			expectedResult: reconcile.Result{
+				RequeueAfter: 10 * time.Minute,
+			},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Conditions: nil,
+			},
+			expectedEvents: []string{
+				"Normal WaitingForIssuerReady Waiting for the issuer to become ready",
+			},
+		},
+
 		// If issuer is not ready, set Ready condition status to false and reason to pending.
 		{
 			name: "set-ready-pending-issuer-is-not-ready",
@@ -731,6 +774,65 @@ func TestCertificateSigningRequestReconcilerReconcile(t *testing.T) {
 			},
 		},
 
+		// Cancel the context of the sign function after the SignTimeout, the
+		// request is retried.
+		{
+			name:        "retry-on-sign-timeout",
+			signTimeout: 10 * time.Millisecond,
+			sign: func(ctx context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				<-ctx.Done()
+				return signer.PEMBundle{}, ctx.Err()
+			},
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1,
+					func(cr *certificatesv1.CertificateSigningRequest) {
+						cr.Spec.SignerName = fmt.Sprintf("%s/%s", clusterIssuer1.GetIssuerTypeIdentifier(), clusterIssuer1.Name)
+					},
+					func(cr *certificatesv1.CertificateSigningRequest) {
+						cr.CreationTimestamp = fakeTimeObj2
+					},
+				),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			validateError: errormatch.NoError(),
+			expectedResult: reconcile.Result{
+				Requeue: true,
+			},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Conditions: nil,
+			},
+			expectedEvents: []string{
+				"Warning RetryableError Failed to sign CertificateRequest, will retry: context deadline exceeded",
+			},
+		},
+
+		// If the sign function returns an IssuerError, the error is reported to the
+		// issuer and a dedicated event is emitted.
+		{
+			name: "issuer-error-reported",
+			sign: func(_ context.Context, cr signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, signer.IssuerError{Err: errors.New("[issuer problem]")}
+			},
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1,
+					func(cr *certificatesv1.CertificateSigningRequest) {
+						cr.Spec.SignerName = fmt.Sprintf("%s/%s", clusterIssuer1.GetIssuerTypeIdentifier(), clusterIssuer1.Name)
+					},
+					func(cr *certificatesv1.CertificateSigningRequest) {
+						cr.CreationTimestamp = fakeTimeObj2
+					},
+				),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			validateError: errormatch.NoError(),
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Conditions: nil,
+			},
+			expectedEvents: []string{
+				"Warning IssuerErrorReported Sign reported an issuer error, waiting for the issuer to become ready again: [issuer problem]",
+			},
+		},
+
 		{
 			name: "success-issuer",
 			sign: successSigner("a-signed-certificate"),
@@ -761,6 +863,368 @@ func TestCertificateSigningRequestReconcilerReconcile(t *testing.T) {
 				"Normal Issued Succeeded signing the CertificateRequest",
 			},
 		},
+
+		// Store the renewal time recommended by the CA in an annotation on the
+		// signed CSR.
+		{
+			name: "success-renewal-time",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{
+					ChainPEM:    []byte("a-signed-certificate"),
+					RenewalTime: fakeTime2.Add(30 * 24 * time.Hour),
+				}, nil
+			},
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1, func(cr *certificatesv1.CertificateSigningRequest) {
+					cr.Spec.SignerName = fmt.Sprintf("%s/%s", clusterIssuer1.GetIssuerTypeIdentifier(), clusterIssuer1.Name)
+				}),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions:  nil,
+			},
+			expectedEvents: []string{
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+			expectedAnnotations: map[string]string{
+				v1alpha1.RenewalTimeAnnotation: fakeTime2.Add(30 * 24 * time.Hour).UTC().Format(time.RFC3339),
+			},
+		},
+
+		// Sign a request that is addressed to a virtual issuer, which is not
+		// backed by an issuer resource.
+		{
+			name: "success-virtual-issuer",
+			virtualIssuers: []VirtualIssuer{
+				{
+					SignerNames: []string{"example.com/virtual-ca"},
+					Issuer:      testutil.SimpleClusterIssuer("virtual-ca"),
+					Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+						return nil
+					},
+					Sign: func(_ context.Context, _ signer.CertificateRequestObject, issuerObject v1alpha1.Issuer) (signer.PEMBundle, error) {
+						return signer.PEMBundle{
+							ChainPEM: []byte("signed-by-" + issuerObject.GetName()),
+						}, nil
+					},
+				},
+			},
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1, func(cr *certificatesv1.CertificateSigningRequest) {
+					cr.Spec.SignerName = "example.com/virtual-ca"
+				}),
+			},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Certificate: []byte("signed-by-virtual-ca"),
+				Conditions:  nil,
+			},
+			expectedEvents: []string{
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// Retry a request that is addressed to a virtual issuer for which the
+		// Check function fails, without calling Sign.
+		{
+			name: "virtual-issuer-check-error",
+			virtualIssuers: []VirtualIssuer{
+				{
+					SignerNames: []string{"example.com/virtual-ca"},
+					Issuer:      testutil.SimpleClusterIssuer("virtual-ca"),
+					Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+						return errors.New("the CA is down")
+					},
+					Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+						return signer.PEMBundle{}, errors.New("sign should not be called")
+					},
+				},
+			},
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1, func(cr *certificatesv1.CertificateSigningRequest) {
+					cr.Spec.SignerName = "example.com/virtual-ca"
+				}),
+			},
+			expectedResult: reconcile.Result{Requeue: true},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Conditions: nil,
+			},
+			expectedEvents: []string{
+				"Warning VirtualIssuerNotReady The virtual issuer is not ready, will retry: the CA is down",
+			},
+		},
+
+		// An AfterSign error does not prevent the certificate from being issued,
+		// the AfterSignPending condition is set and the request is requeued.
+		{
+			name: "after-sign-error-is-retried",
+			sign: successSigner("a-signed-certificate"),
+			afterSign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer, _ signer.PEMBundle) error {
+				return errors.New("inventory unavailable")
+			},
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1, func(cr *certificatesv1.CertificateSigningRequest) {
+					cr.Spec.SignerName = fmt.Sprintf("%s/%s", clusterIssuer1.GetIssuerTypeIdentifier(), clusterIssuer1.Name)
+					cr.CreationTimestamp = fakeTimeObj2
+				}),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			expectedResult: reconcile.Result{Requeue: true},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []certificatesv1.CertificateSigningRequestCondition{
+					{
+						Type:               v1alpha1.CertificateSigningRequestConditionAfterSignPending,
+						Status:             v1.ConditionTrue,
+						Reason:             v1alpha1.CertificateSigningRequestConditionReasonAfterSignError,
+						Message:            "CertificateSigningRequest was signed, but AfterSign failed: inventory unavailable",
+						LastTransitionTime: fakeTimeObj2,
+						LastUpdateTime:     fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning AfterSignError Failed to run AfterSign, will retry: inventory unavailable",
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// AfterSign is retried using the stored certificate while the
+		// AfterSignPending condition is True, the certificate is not signed again.
+		{
+			name: "after-sign-retry-succeeds",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, errors.New("sign should not be called")
+			},
+			afterSign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer, bundle signer.PEMBundle) error {
+				if string(bundle.ChainPEM) != "a-signed-certificate" {
+					return errors.New("unexpected certificate")
+				}
+				return nil
+			},
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1, func(cr *certificatesv1.CertificateSigningRequest) {
+					cr.Spec.SignerName = fmt.Sprintf("%s/%s", clusterIssuer1.GetIssuerTypeIdentifier(), clusterIssuer1.Name)
+					cr.CreationTimestamp = fakeTimeObj2
+					cr.Status.Certificate = []byte("a-signed-certificate")
+					conditions.SetCertificateSigningRequestStatusCondition(
+						fakeClock1,
+						cr.Status.Conditions,
+						&cr.Status.Conditions,
+						v1alpha1.CertificateSigningRequestConditionAfterSignPending,
+						v1.ConditionTrue,
+						v1alpha1.CertificateSigningRequestConditionReasonAfterSignError,
+						"CertificateSigningRequest was signed, but AfterSign failed: inventory unavailable",
+					)
+				}),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []certificatesv1.CertificateSigningRequestCondition{
+					{
+						Type:               v1alpha1.CertificateSigningRequestConditionAfterSignPending,
+						Status:             v1.ConditionFalse,
+						Reason:             v1alpha1.CertificateSigningRequestConditionReasonAfterSignSucceeded,
+						Message:            "Succeeded running AfterSign",
+						LastTransitionTime: fakeTimeObj2,
+						LastUpdateTime:     fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Normal AfterSignSucceeded Succeeded running AfterSign",
+			},
+		},
+
+		// AfterSign is no longer retried once the MaxRetryDuration has passed,
+		// the certificate is kept in the status.
+		{
+			name: "after-sign-retry-past-max-retry-duration",
+			afterSign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer, _ signer.PEMBundle) error {
+				return errors.New("inventory unavailable")
+			},
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1, func(cr *certificatesv1.CertificateSigningRequest) {
+					cr.Spec.SignerName = fmt.Sprintf("%s/%s", clusterIssuer1.GetIssuerTypeIdentifier(), clusterIssuer1.Name)
+					cr.CreationTimestamp = metav1.NewTime(fakeTimeObj2.Add(-2 * time.Minute))
+					cr.Status.Certificate = []byte("a-signed-certificate")
+					conditions.SetCertificateSigningRequestStatusCondition(
+						fakeClock1,
+						cr.Status.Conditions,
+						&cr.Status.Conditions,
+						v1alpha1.CertificateSigningRequestConditionAfterSignPending,
+						v1.ConditionTrue,
+						v1alpha1.CertificateSigningRequestConditionReasonAfterSignError,
+						"CertificateSigningRequest was signed, but AfterSign failed: inventory unavailable",
+					)
+				}),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions: []certificatesv1.CertificateSigningRequestCondition{
+					{
+						Type:               v1alpha1.CertificateSigningRequestConditionAfterSignPending,
+						Status:             v1.ConditionFalse,
+						Reason:             v1alpha1.CertificateSigningRequestConditionReasonAfterSignFailed,
+						Message:            "AfterSign has failed permanently: inventory unavailable",
+						LastTransitionTime: fakeTimeObj2,
+						LastUpdateTime:     fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to run AfterSign: inventory unavailable",
+			},
+		},
+
+		// A request whose AfterSign was given up on is ignored.
+		{
+			name: "after-sign-failed-is-ignored",
+			afterSign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer, _ signer.PEMBundle) error {
+				return errors.New("after sign should not be called")
+			},
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1, func(cr *certificatesv1.CertificateSigningRequest) {
+					cr.Spec.SignerName = fmt.Sprintf("%s/%s", clusterIssuer1.GetIssuerTypeIdentifier(), clusterIssuer1.Name)
+					cr.Status.Certificate = []byte("a-signed-certificate")
+					conditions.SetCertificateSigningRequestStatusCondition(
+						fakeClock1,
+						cr.Status.Conditions,
+						&cr.Status.Conditions,
+						v1alpha1.CertificateSigningRequestConditionAfterSignPending,
+						v1.ConditionFalse,
+						v1alpha1.CertificateSigningRequestConditionReasonAfterSignFailed,
+						"AfterSign has failed permanently: inventory unavailable",
+					)
+				}),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			expectedStatusPatch: nil,
+		},
+
+		// Well-known Kubernetes signerNames are mapped to an issuer and are signed
+		// if the request meets the constraints of the signer.
+		{
+			name: "success-kubelet-serving",
+			sign: successSigner("a-signed-certificate"),
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1,
+					cmgen.SetCertificateSigningRequestSignerName(certificatesv1.KubeletServingSignerName),
+					cmgen.SetCertificateSigningRequestRequest(kubeletServingRequest),
+					cmgen.SetCertificateSigningRequestUsages([]certificatesv1.KeyUsage{
+						certificatesv1.UsageDigitalSignature,
+						certificatesv1.UsageServerAuth,
+					}),
+				),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Certificate: []byte("a-signed-certificate"),
+				Conditions:  nil,
+			},
+			expectedEvents: []string{
+				"Normal Issued Succeeded signing the CertificateRequest",
+			},
+		},
+
+		// Fail permanently if the request does not meet the constraints of the
+		// well-known Kubernetes signer.
+		{
+			name: "fail-kubelet-serving-constraints",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, errors.New("sign should not be called")
+			},
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1,
+					cmgen.SetCertificateSigningRequestSignerName(certificatesv1.KubeletServingSignerName),
+					cmgen.SetCertificateSigningRequestRequest(kubeletServingRequest),
+					cmgen.SetCertificateSigningRequestUsages([]certificatesv1.KeyUsage{
+						certificatesv1.UsageDigitalSignature,
+						certificatesv1.UsageClientAuth,
+					}),
+				),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Conditions: []certificatesv1.CertificateSigningRequestCondition{
+					{
+						Type:               certificatesv1.CertificateFailed,
+						Status:             v1.ConditionTrue,
+						Reason:             cmapi.CertificateRequestReasonFailed,
+						Message:            "CertificateRequest has failed permanently: 2 validation errors: kubernetes.io/kubelet-serving: usages must include \"digital signature\" and \"server auth\"; kubernetes.io/kubelet-serving: usages [\"client auth\"] are not allowed",
+						LastTransitionTime: fakeTimeObj2,
+						LastUpdateTime:     fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning PermanentError Failed permanently to sign CertificateRequest: 2 validation errors: kubernetes.io/kubelet-serving: usages must include \"digital signature\" and \"server auth\"; kubernetes.io/kubelet-serving: usages [\"client auth\"] are not allowed",
+			},
+		},
+
+		// Fail the request without signing it if it is older than MaxRequestAge.
+		{
+			name: "request-expired",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, errors.New("sign should not be called")
+			},
+			maxRequestAge: time.Hour,
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1, func(cr *certificatesv1.CertificateSigningRequest) {
+					cr.Spec.SignerName = fmt.Sprintf("%s/%s", clusterIssuer1.GetIssuerTypeIdentifier(), clusterIssuer1.Name)
+					cr.CreationTimestamp = fakeTimeObj1
+				}),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Conditions: []certificatesv1.CertificateSigningRequestCondition{
+					{
+						Type:               certificatesv1.CertificateFailed,
+						Status:             v1.ConditionTrue,
+						Reason:             "RequestExpired",
+						Message:            "CertificateRequest has failed permanently: the request is older than the maximum request age of 1h0m0s",
+						LastTransitionTime: fakeTimeObj2,
+						LastUpdateTime:     fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RequestExpired The request is older than the maximum request age of 1h0m0s, not signing it",
+			},
+		},
+
+		// Fail the request without signing it if the CSR is larger than MaxCSRSize.
+		{
+			name: "csr-too-large",
+			sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+				return signer.PEMBundle{}, errors.New("sign should not be called")
+			},
+			maxCSRSize: 1024,
+			objects: []client.Object{
+				cmgen.CertificateSigningRequestFrom(cr1, func(cr *certificatesv1.CertificateSigningRequest) {
+					cr.Spec.SignerName = fmt.Sprintf("%s/%s", clusterIssuer1.GetIssuerTypeIdentifier(), clusterIssuer1.Name)
+					cr.Spec.Request = bytes.Repeat([]byte("a"), 1025)
+				}),
+				testutil.SimpleClusterIssuerFrom(clusterIssuer1),
+			},
+			expectedStatusPatch: &certificatesv1.CertificateSigningRequestStatus{
+				Conditions: []certificatesv1.CertificateSigningRequestCondition{
+					{
+						Type:               certificatesv1.CertificateFailed,
+						Status:             v1.ConditionTrue,
+						Reason:             "RequestTooLarge",
+						Message:            "CertificateRequest has failed permanently: the CSR is 1025 bytes, which is larger than the maximum size of 1024 bytes",
+						LastTransitionTime: fakeTimeObj2,
+						LastUpdateTime:     fakeTimeObj2,
+					},
+				},
+			},
+			expectedEvents: []string{
+				"Warning RequestTooLarge The CSR is 1025 bytes, which is larger than the maximum size of 1024 bytes, not signing it",
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -796,10 +1260,22 @@ func TestCertificateSigningRequestReconcilerReconcile(t *testing.T) {
 				FieldOwner:         fieldOwner,
 				MaxRetryDuration:   time.Minute,
 				EventSource:        kubeutil.NewEventStore(),
-				Client:             fakeClient,
-				Sign:               tc.sign,
-				EventRecorder:      fakeRecorder,
-				Clock:              fakeClock2,
+				KubernetesSignerNames: map[string]string{
+					certificatesv1.KubeletServingSignerName: fmt.Sprintf("%s/%s", clusterIssuer1.GetIssuerTypeIdentifier(), clusterIssuer1.Name),
+				},
+				Client:        fakeClient,
+				Sign:          tc.sign,
+				AfterSign:     tc.afterSign,
+				EventRecorder: fakeRecorder,
+				Clock:         fakeClock2,
+
+				MaxRequestAge: tc.maxRequestAge,
+				MaxCSRSize:    tc.maxCSRSize,
+				SignTimeout:   tc.signTimeout,
+
+				PendingCertificateRequestResyncInterval: tc.pendingResync,
+
+				VirtualIssuers: tc.virtualIssuers,
 			}
 
 			err = controller.setIssuersGroupVersionKind(scheme)
@@ -817,6 +1293,12 @@ func TestCertificateSigningRequestReconcilerReconcile(t *testing.T) {
 			} else {
 				assert.Equal(t, tc.expectedEvents, allEvents)
 			}
+
+			if tc.expectedAnnotations != nil {
+				var crAfter certificatesv1.CertificateSigningRequest
+				require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, &crAfter))
+				assert.Equal(t, tc.expectedAnnotations, crAfter.Annotations)
+			}
 		})
 	}
 }
@@ -903,6 +1385,25 @@ func TestCertificateSigningRequestMatchIssuerType(t *testing.T) {
 			expectedIssuerType: &api.SimpleClusterIssuer{},
 			expectedIssuerName: types.NamespacedName{Name: "name.test"},
 		},
+		{
+			name:               "kubernetes signer name",
+			issuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+			clusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+			csr:                createCsr("kubernetes.io/kubelet-serving"),
+
+			expectedIssuerType: &api.SimpleClusterIssuer{},
+			expectedIssuerName: types.NamespacedName{Name: "kubelet-issuer"},
+		},
+		{
+			name:               "unmapped kubernetes signer name",
+			issuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+			clusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+			csr:                createCsr("kubernetes.io/kube-apiserver-client"),
+
+			expectedIssuerType: nil,
+			expectedIssuerName: types.NamespacedName{},
+			expectedError:      errormatch.ErrorContains("no issuer found for signer name: \"kubernetes.io/kube-apiserver-client\""),
+		},
 		{
 			name:               "cluster issuer with empty name",
 			issuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
@@ -925,6 +1426,9 @@ func TestCertificateSigningRequestMatchIssuerType(t *testing.T) {
 			crr := &CertificateSigningRequestReconciler{
 				IssuerTypes:        tc.issuerTypes,
 				ClusterIssuerTypes: tc.clusterIssuerTypes,
+				KubernetesSignerNames: map[string]string{
+					"kubernetes.io/kubelet-serving": "simpleclusterissuers.issuer.cert-manager.io/kubelet-issuer",
+				},
 			}
 
 			require.NoError(t, crr.setIssuersGroupVersionKind(scheme))
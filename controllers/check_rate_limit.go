@@ -0,0 +1,40 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/time/rate"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// newRateLimitedCheck returns a Check function that waits for the limiter
+// before calling check. The limiter is shared by all the issuers, so that the
+// Check calls are paced regardless of when each issuer is reconciled.
+func newRateLimitedCheck(check signer.Check, limiter *rate.Limiter) signer.Check {
+	return func(ctx context.Context, issuerObject v1alpha1.Issuer) error {
+		if err := limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("waiting for the global check rate limiter: %w", err)
+		}
+
+		return check(ctx, issuerObject)
+	}
+}
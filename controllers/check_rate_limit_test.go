@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/testutil"
+)
+
+func TestRateLimitedCheck(t *testing.T) {
+	t.Parallel()
+
+	const (
+		issuerCount = 5
+		interval    = 50 * time.Millisecond
+	)
+
+	var mu sync.Mutex
+	var callTimes []time.Time
+	check := newRateLimitedCheck(func(_ context.Context, _ v1alpha1.Issuer) error {
+		mu.Lock()
+		defer mu.Unlock()
+		callTimes = append(callTimes, time.Now())
+		return nil
+	}, rate.NewLimiter(rate.Every(interval), 1))
+
+	// All issuers are checked at the same time, the checks must be spaced by
+	// the interval of the shared limiter.
+	var wg sync.WaitGroup
+	for i := 0; i < issuerCount; i++ {
+		issuer := testutil.SimpleIssuer(fmt.Sprintf("issuer-%d", i))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, check(context.TODO(), issuer))
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, callTimes, issuerCount)
+	sort.Slice(callTimes, func(i, j int) bool { return callTimes[i].Before(callTimes[j]) })
+	// Allow for some imprecision of the timers, a goroutine might be woken up
+	// later than the time that was reserved for it.
+	for i := 1; i < len(callTimes); i++ {
+		assert.GreaterOrEqual(t, callTimes[i].Sub(callTimes[i-1]), interval/2, "check %d was not spaced", i)
+	}
+	assert.GreaterOrEqual(t, callTimes[len(callTimes)-1].Sub(callTimes[0]), (issuerCount-1)*interval-interval/2)
+}
+
+func TestRateLimitedCheckContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	limiter := rate.NewLimiter(rate.Every(time.Hour), 1)
+	require.True(t, limiter.Allow()) // use the only token
+
+	called := false
+	check := newRateLimitedCheck(func(_ context.Context, _ v1alpha1.Issuer) error {
+		called = true
+		return nil
+	}, limiter)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	err := check(ctx, testutil.SimpleIssuer("issuer-1"))
+	assert.ErrorContains(t, err, "waiting for the global check rate limiter")
+	assert.False(t, called)
+}
@@ -19,13 +19,23 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	v1alpha1 "github.com/cert-manager/issuer-lib/api/v1alpha1"
 	"github.com/cert-manager/issuer-lib/controllers/signer"
@@ -40,24 +50,165 @@ type CombinedController struct {
 
 	MaxRetryDuration time.Duration
 
+	// MaxRequestAge is the maximum age of a request (based on its creation
+	// timestamp) that will still be signed. See CertificateRequestReconciler.
+	// This is disabled by default.
+	MaxRequestAge time.Duration
+
+	// SignTimeout is the maximum duration of a single Sign call for
+	// CertificateRequests and Kubernetes CSRs, it also caps the deadline
+	// requested using the max-wait annotation of CertificateRequests. See
+	// CertificateRequestReconciler. If zero, the duration of a Sign call is
+	// not limited.
+	SignTimeout time.Duration
+
+	// MaxCSRSize is the maximum size in bytes of the PEM encoded CSR of a
+	// request, larger requests are failed permanently without calling Sign.
+	// See CertificateRequestReconciler. If zero, DefaultMaxCSRSize is used.
+	MaxCSRSize int
+
+	// IssuerDeletedGracePeriod is the duration that a CertificateRequest waits
+	// for its issuer to be recreated, if the issuer is deleted while the request
+	// is being processed. See CertificateRequestReconciler.
+	IssuerDeletedGracePeriod time.Duration
+
+	// PendingCertificateRequestResyncInterval is the interval at which requests
+	// that are waiting for their issuer are reconciled again, as a backstop in
+	// case the issuer event that triggers them was missed. See
+	// CertificateRequestReconciler. This is disabled by default.
+	PendingCertificateRequestResyncInterval time.Duration
+
+	// FormatPendingMessage is an optional function that returns the message of
+	// the Pending Ready condition of CertificateRequests that are waiting for
+	// their issuer to become Ready. See CertificateRequestReconciler.
+	FormatPendingMessage func(issuerReady *cmapi.IssuerCondition) string
+
+	// ForceReissueAnnotation is the name of an annotation that can be changed
+	// to reset the failed state of a CertificateRequest and to sign it again.
+	// See CertificateRequestReconciler. This is disabled by default.
+	ForceReissueAnnotation string
+
+	// QueueDepthWarningThreshold is the workqueue depth of the CertificateRequest
+	// and Kubernetes CSR controllers above which a warning is logged, if the
+	// depth stays above the threshold for more than a minute (eg. because the
+	// CA is slow). The warnings are throttled to one per minute per controller.
+	// The workqueue depth itself is always exposed by the "workqueue_depth"
+	// metric of controller-runtime. This is disabled by default.
+	QueueDepthWarningThreshold int
+
+	// KubernetesSignerNames maps well-known Kubernetes signerNames (eg.
+	// "kubernetes.io/kubelet-serving") to a signerName in the format
+	// "<issuer-type-id>/<issuer-id>". See CertificateSigningRequestReconciler
+	// for the constraints that are enforced for these signers.
+	KubernetesSignerNames map[string]string
+
+	// VirtualIssuers are signers for Kubernetes CSRs that are not backed by an
+	// issuer resource, see VirtualIssuer. If VirtualIssuers are configured, the
+	// Kubernetes CSR controller is set up even if no ClusterIssuerTypes are
+	// configured.
+	VirtualIssuers []VirtualIssuer
+
 	// Check connects to a CA and checks if it is available
 	signer.Check
 	// Sign connects to a CA and returns a signed certificate for the supplied CertificateRequest.
 	signer.Sign
+	// AfterSign is an optional function that is called after a successful Sign,
+	// before the CertificateRequest is marked as Ready.
+	signer.AfterSign
 
 	// IgnoreCertificateRequest is an optional function that can prevent the CertificateRequest
 	// and Kubernetes CSR controllers from reconciling a CertificateRequest resource.
 	signer.IgnoreCertificateRequest
+	// ApproveCertificateRequest is an optional function that approves or denies
+	// cert-manager CertificateRequests that have not been Approved or Denied yet.
+	// It is not used for Kubernetes CSRs.
+	signer.ApproveCertificateRequest
+	// GetNameConstraints is an optional function that returns the name constraints
+	// of an issuer, which are validated before calling Sign.
+	signer.GetNameConstraints
+
+	// QuotaCheck is an optional function that is called before the Sign
+	// function to enforce a quota (see signer.QuotaCheck). If the quota is
+	// exceeded, the QuotaExceeded condition is set and the request is retried.
+	signer.QuotaCheck
+	// DefaultUsages is an optional function that returns the usages that an
+	// issuer applies to CertificateRequests that don't specify any usages.
+	// It is not used for Kubernetes CSRs, which always specify their usages.
+	signer.DefaultUsages
 	// IgnoreIssuer is an optional function that can prevent the issuer controllers from
-	// reconciling an issuer resource.
+	// reconciling an issuer resource. The requests that reference an ignored issuer
+	// are ignored too.
 	signer.IgnoreIssuer
 
+	// IssuerConfigMapRefs is an optional function that returns the ConfigMaps
+	// that an issuer takes its configuration from. The issuer is checked again
+	// when one of these ConfigMaps changes, see IssuerReconciler.
+	IssuerConfigMapRefs func(issuerObject v1alpha1.Issuer) []types.NamespacedName
+
+	// OnIssuerBootstrap is an optional function that is called once per
+	// issuer, before it is checked for the first time, see IssuerReconciler.
+	OnIssuerBootstrap func(ctx context.Context, issuerObject v1alpha1.Issuer) error
+
+	// MaxIssuerConditions is the maximum number of conditions that are set on
+	// the issuer status, see IssuerReconciler.
+	MaxIssuerConditions int
+
+	// HTTPClientProvider is an optional function that returns a pre-configured
+	// HTTP client (eg. with proxy, TLS trust and timeout settings) that is
+	// passed to the Check, Sign and AfterSign functions using the context. The client can be retrieved using
+	// signer.HTTPClientFromContext.
+	HTTPClientProvider func() *http.Client
+
+	// APIReader is the reader that is passed to the Check, Sign and AfterSign
+	// functions using the context, see signer.APIReaderFromContext. If it is not
+	// set, the API reader of the manager is used, which reads directly from the
+	// API server (no cache). Set it to the manager's client to use cached reads
+	// instead (an informer is then started for every type that is read).
+	APIReader client.Reader
+
 	// EventRecorder is used for creating Kubernetes events on resources.
+	// If it is not set, an event recorder is created for each issuer type using
+	// the manager's event broadcaster. The events are then attributed to the
+	// component "<lowercase issuer kind>.<issuer group>", eg.
+	// "simpleissuer.testing.cert-manager.io", so they can be filtered per
+	// issuer type.
 	EventRecorder record.EventRecorder
 
-	// Clock is used to mock condition transition times in tests.
+	// MaxIdenticalRequestEvents is the maximum number of identical events (same
+	// type, reason and message) that are recorded per
+	// IdenticalRequestEventsInterval for the CertificateRequests and Kubernetes
+	// CSRs that reference the same issuer. Additional identical events are
+	// dropped, this prevents flooding the API server with events when eg. the CA
+	// is down and many requests fail with the same error. The status of each
+	// request is still updated. This is disabled by default.
+	MaxIdenticalRequestEvents int
+
+	// IdenticalRequestEventsInterval is the interval that is used by
+	// MaxIdenticalRequestEvents. Defaults to DefaultIdenticalRequestEventsInterval.
+	IdenticalRequestEventsInterval time.Duration
+
+	// ResourceLabeler is an optional function that returns labels (eg. a
+	// tenant label) for the object that an event is recorded on: an issuer, a
+	// CertificateRequest or a Kubernetes CSR. The labels are added to all the
+	// events that are recorded by the controllers, so that platform tooling can
+	// filter the events. Because the client-go event recorder doesn't support
+	// labels on events, they are added as annotations of the events.
+	ResourceLabeler func(obj client.Object) map[string]string
+
+	// Clock is used to mock condition transition times in tests. It is also
+	// used to determine whether the MaxRetryDuration has passed when a request
+	// is reconciled. Only the Now method is used: requeues and backoff still
+	// use real timers, so stepping a fake clock does not trigger a reconcile
+	// by itself.
 	Clock clock.PassiveClock
 
+	// ComponentInfo is the optional name and version of the controller that
+	// is built using issuer-lib. If it is set, it is included in the component
+	// name of the emitted events, eg. "simpleissuer.testing.cert-manager.io
+	// (my-issuer@v1.2.3)", and in the log line that is logged on setup, which
+	// also contains the issuer-lib version.
+	ComponentInfo ComponentInfo
+
 	// SetCAOnCertificateRequest is used to enable setting the CA status field on
 	// the CertificateRequest resource. This is disabled by default.
 	// Deprecated: this option is for backwards compatibility only. The use of
@@ -65,12 +216,20 @@ type CombinedController struct {
 	// separately using a tool such as trust-manager.
 	SetCAOnCertificateRequest bool
 
+	// PreserveExistingCertificate is used to take over CertificateRequests that
+	// already have a certificate, see CertificateRequestReconciler.
+	PreserveExistingCertificate bool
+
 	// DisableCertificateRequestController is used to disable the CertificateRequest
 	// controller. This controller is enabled by default.
 	// You should only disable this controller if you eg. don't want to rely on the cert-manager
 	// CRDs to be installed.
 	// Note: in the future, we might remove this option and always enable the CertificateRequest
 	// controller.
+	// If both the CertificateRequest and Kubernetes CSR controllers are disabled,
+	// only the issuer controllers are run. This can be used if a separate component
+	// is responsible for signing, but issuer-lib should still manage the readiness
+	// of the issuers. The Sign function is not required in that mode.
 	DisableCertificateRequestController bool
 
 	// DisableKubernetesCSRController is used to disable the Kubernetes CSR controller.
@@ -79,12 +238,162 @@ type CombinedController struct {
 	// Kubernetes CSRs.
 	// Note: in the future, we might remove this option and always enable the Kubernetes CSR
 	// controller.
+	// Kubernetes CSRs can only be signed by cluster-scoped issuers, so the Kubernetes CSR
+	// controller is also not set up if no ClusterIssuerTypes are configured. In that case,
+	// no cluster-scoped resources are watched and no RBAC permissions for Kubernetes CSRs
+	// are needed.
 	DisableKubernetesCSRController bool
 
+	// CheckChainCompleteness is used to verify that the certificate chain
+	// returned by the Sign function can be verified up to a self-signed root
+	// certificate that is part of the returned bundle. If the chain is
+	// incomplete, the IncompleteChain condition is set and a Warning event is
+	// emitted. This is disabled by default.
+	CheckChainCompleteness bool
+
+	// RetryIncompleteChain is used to handle an incomplete chain as a retryable
+	// Sign error, instead of only setting the IncompleteChain condition. This
+	// option is only used if CheckChainCompleteness is enabled.
+	RetryIncompleteChain bool
+
+	// RequireSCT is used to verify that the certificate returned by the Sign
+	// function contains embedded signed certificate timestamps (SCTs), as is
+	// required for publicly trusted certificates. A certificate without enough
+	// SCTs is rejected and the request is marked as Failed. This is disabled by
+	// default.
+	RequireSCT bool
+
+	// MinimumSCTs is the minimum number of embedded SCTs that is required when
+	// RequireSCT is enabled. Defaults to 1.
+	MinimumSCTs int
+
+	// RequireExactSANs is used to verify that the certificate returned by the
+	// Sign function contains exactly the subject alternative names that were
+	// requested. A certificate with added or missing SANs is handled as a
+	// retryable Sign error, which catches CAs that are misconfigured to
+	// silently change the SANs. This is disabled by default.
+	RequireExactSANs bool
+
+	// DeduplicateSANs removes duplicate subject alternative names (eg. the same
+	// DNS name twice, or with a different case) from the request that is passed
+	// to the Sign function, see signer.DeduplicateSANs. The removed SANs are
+	// recorded in a "SANsDeduplicated" event. The raw CSR is not changed.
+	DeduplicateSANs bool
+
+	// RejectConflictingSANs fails requests with conflicting subject alternative
+	// names (eg. an IP address requested as a DNS name) permanently, without
+	// calling the Sign function, see signer.ConflictingSANs.
+	RejectConflictingSANs bool
+
+	// IsPermanent is an optional function that classifies additional Check and
+	// Sign errors as permanent, in addition to the errors that wrap a
+	// signer.PermanentError.
+	IsPermanent func(error) bool
+
+	// EscalateToIssuer is an optional function that decides which Sign errors
+	// of CertificateRequests and Kubernetes CSRs are reported to the issuer.
+	// By default, only errors that wrap a signer.IssuerError are escalated.
+	// See CertificateRequestReconciler.
+	EscalateToIssuer func(error) bool
+
+	// TreatAllErrorsAsRetryable is a debugging option that retries all Sign
+	// errors until MaxRetryDuration has passed, including PermanentErrors.
+	// It must not be used in production, see CertificateRequestReconciler.
+	TreatAllErrorsAsRetryable bool
+
+	// Logger is an optional logger that is used by all controllers instead of
+	// the manager's logger.
+	Logger logr.Logger
+
+	// DisableForceApply is used to apply status patches without forcing the
+	// ownership of fields that are managed by another field manager. When this
+	// option is set, such conflicts are returned as errors instead of being
+	// overwritten. Forcing is enabled by default.
+	DisableForceApply bool
+
+	// CacheSyncTimeout is the maximum duration that the controller waits for
+	// its informer caches to sync when it starts. This can be increased on
+	// large clusters where the initial sync takes longer. If zero, the manager's
+	// controller options are used, which default to 2 minutes.
+	CacheSyncTimeout time.Duration
+
+	// SignWorkerPool optionally bounds the number of concurrent and waiting
+	// Sign calls of the CertificateRequest and Kubernetes CSR controllers,
+	// which share the same pool. See SignWorkerPool.
+	SignWorkerPool SignWorkerPool
+
+	// GlobalCheckRate limits the rate (in calls per second) at which the Check
+	// function is called, across all the managed issuers. This prevents many
+	// issuers that point at the same CA from overwhelming it with simultaneous
+	// checks. Check calls wait for their turn, so the issuer controllers are
+	// slowed down accordingly. If zero, the Check calls are not limited.
+	GlobalCheckRate rate.Limit
+
+	// MaxBackoff caps the exponential backoff of the rate limiters of all
+	// controllers, see IssuerReconciler and CertificateRequestReconciler.
+	MaxBackoff time.Duration
+
+	// AuditResyncInterval is the interval at which all the issuers and all
+	// the outstanding CertificateRequests and Kubernetes CSRs are reconciled
+	// again, independent of watch events and of the informer resync. Each
+	// controller writes a log line for every cycle. If zero, this is disabled.
+	AuditResyncInterval time.Duration
+
+	// StatusEndpointPath is an optional path on the metrics server of the
+	// manager on which the readiness of all managed issuers is served as JSON,
+	// for external monitoring that doesn't use the Kubernetes API. See
+	// NewIssuerStatusHandler for the served document. This is disabled by
+	// default.
+	StatusEndpointPath string
+
+	// PreSetupWithManager and PostSetupWithManager are optional functions that
+	// are called before and after each of the controllers is built, see
+	// IssuerReconciler and CertificateRequestReconciler.
+	PreSetupWithManager  func(context.Context, schema.GroupVersionKind, ctrl.Manager, *builder.Builder) error
 	PostSetupWithManager func(context.Context, schema.GroupVersionKind, ctrl.Manager, controller.Controller) error
+
+	// managedIssuerGVKs contains the GVKs of the issuer types for which an
+	// issuer controller was set up, it is set by SetupWithManager.
+	managedIssuerGVKs []schema.GroupVersionKind
+}
+
+// ManagedIssuerGVKs returns the GroupVersionKinds of all the issuer types that
+// are managed by the controller, both namespaced and cluster-scoped. This can
+// be used to generate webhook configurations or RBAC rules dynamically.
+// The GVKs are only known after SetupWithManager has been called, before that
+// nil is returned.
+func (r *CombinedController) ManagedIssuerGVKs() []schema.GroupVersionKind {
+	return append([]schema.GroupVersionKind(nil), r.managedIssuerGVKs...)
 }
 
 func (r *CombinedController) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	issuerOnly := r.DisableCertificateRequestController && r.DisableKubernetesCSRController
+	if !issuerOnly && r.Sign == nil {
+		return fmt.Errorf("the Sign function must be set, unless both the CertificateRequest and Kubernetes CSR controllers are disabled")
+	}
+
+	if r.SignWorkerPool.Size < 0 || r.SignWorkerPool.QueueDepth < 0 {
+		return fmt.Errorf("the Size and QueueDepth of the SignWorkerPool must not be negative, got %d and %d", r.SignWorkerPool.Size, r.SignWorkerPool.QueueDepth)
+	}
+
+	sign := r.Sign
+	virtualIssuers := r.VirtualIssuers
+	if r.SignWorkerPool.Size > 0 {
+		pool := newSignWorkerPool(r.SignWorkerPool)
+		sign = pool.wrap(r.Sign)
+
+		virtualIssuers = make([]VirtualIssuer, len(r.VirtualIssuers))
+		for i, virtualIssuer := range r.VirtualIssuers {
+			virtualIssuer.Sign = pool.wrap(virtualIssuer.Sign)
+			virtualIssuers[i] = virtualIssuer
+		}
+	}
+
+	check := r.Check
+	if r.GlobalCheckRate > 0 && r.Check != nil {
+		check = newRateLimitedCheck(r.Check, rate.NewLimiter(r.GlobalCheckRate, 1))
+	}
+
 	var err error
 	cl := mgr.GetClient()
 	eventSource := kubeutil.NewEventStore()
@@ -93,7 +402,64 @@ func (r *CombinedController) SetupWithManager(ctx context.Context, mgr ctrl.Mana
 		r.Clock = clock.RealClock{}
 	}
 
-	for _, issuerType := range append(r.IssuerTypes, r.ClusterIssuerTypes...) {
+	apiReader := r.APIReader
+	if apiReader == nil {
+		apiReader = mgr.GetAPIReader()
+	}
+
+	allIssuerTypes := append(append([]v1alpha1.Issuer{}, r.IssuerTypes...), r.ClusterIssuerTypes...)
+
+	logger := r.Logger
+	if logger.GetSink() == nil {
+		logger = mgr.GetLogger()
+	}
+	logger.Info(
+		"Setting up the issuer-lib controllers.",
+		"component", r.ComponentInfo.Name, "version", r.ComponentInfo.Version,
+		"issuerLibVersion", issuerLibVersion(),
+	)
+
+	newEventRecorder := func(component string) record.EventRecorder {
+		return mgr.GetEventRecorderFor(r.ComponentInfo.eventComponentName(component))
+	}
+
+	var issuerTypeRecorders map[schema.GroupKind]record.EventRecorder
+	if r.EventRecorder == nil {
+		issuerTypeRecorders, err = newIssuerTypeEventRecorders(mgr.GetScheme(), allIssuerTypes, newEventRecorder)
+		if err != nil {
+			return fmt.Errorf("failed to create event recorders: %w", err)
+		}
+	}
+
+	// eventRecorderFor returns the configured EventRecorder or, if it is not set,
+	// an event recorder that uses the event recorder of the matched issuer type.
+	// If a ResourceLabeler is configured, its labels are added to the events.
+	eventRecorderFor := func(issuerTypeOf func(object runtime.Object) (schema.GroupKind, bool)) record.EventRecorder {
+		recorder := r.EventRecorder
+		if recorder == nil {
+			recorder = &issuerTypeEventRecorder{
+				recorders:    issuerTypeRecorders,
+				fallback:     newEventRecorder(r.FieldOwner),
+				issuerTypeOf: issuerTypeOf,
+			}
+		}
+
+		if r.ResourceLabeler != nil {
+			recorder = &labeledEventRecorder{
+				recorder: recorder,
+				labeler:  r.ResourceLabeler,
+			}
+		}
+
+		return recorder
+	}
+
+	issuerEventRecorder := eventRecorderFor(func(object runtime.Object) (schema.GroupKind, bool) {
+		gvk, err := apiutil.GVKForObject(object, mgr.GetScheme())
+		return gvk.GroupKind(), err == nil
+	})
+
+	for _, issuerType := range allIssuerTypes {
 		if err = (&IssuerReconciler{
 			ForObject: issuerType,
 
@@ -101,63 +467,202 @@ func (r *CombinedController) SetupWithManager(ctx context.Context, mgr ctrl.Mana
 			EventSource: eventSource,
 
 			Client:        cl,
-			Check:         r.Check,
+			Check:         check,
 			IgnoreIssuer:  r.IgnoreIssuer,
-			EventRecorder: r.EventRecorder,
+			EventRecorder: issuerEventRecorder,
 			Clock:         r.Clock,
 
+			IssuerConfigMapRefs: r.IssuerConfigMapRefs,
+			OnIssuerBootstrap:   r.OnIssuerBootstrap,
+			MaxIssuerConditions: r.MaxIssuerConditions,
+
+			IsPermanent: r.IsPermanent,
+
+			HTTPClientProvider: r.HTTPClientProvider,
+			APIReader:          apiReader,
+
+			Logger: r.Logger,
+
+			DisableForceApply: r.DisableForceApply,
+
+			CacheSyncTimeout:     r.CacheSyncTimeout,
+			MaxBackoff:           r.MaxBackoff,
+			AuditResyncInterval:  r.AuditResyncInterval,
+			PreSetupWithManager:  r.PreSetupWithManager,
 			PostSetupWithManager: r.PostSetupWithManager,
 		}).SetupWithManager(ctx, mgr); err != nil {
 			return fmt.Errorf("%T: %w", issuerType, err)
 		}
 	}
 
-	if r.DisableCertificateRequestController && r.DisableKubernetesCSRController {
-		return fmt.Errorf("both CertificateRequest and Kubernetes CSR controllers are disabled, must enable at least one")
+	managedIssuerGVKs := make([]schema.GroupVersionKind, 0, len(allIssuerTypes))
+	for _, issuerType := range allIssuerTypes {
+		// The GVK was set by the IssuerReconciler's SetupWithManager.
+		managedIssuerGVKs = append(managedIssuerGVKs, issuerType.GetObjectKind().GroupVersionKind())
+	}
+	r.managedIssuerGVKs = managedIssuerGVKs
+
+	if r.StatusEndpointPath != "" {
+		handler := NewIssuerStatusHandler(cl, mgr.GetScheme(), managedIssuerGVKs)
+		if err := mgr.AddMetricsExtraHandler(r.StatusEndpointPath, handler); err != nil {
+			return fmt.Errorf("failed to register the issuer status endpoint: %w", err)
+		}
 	}
 
+	// controllerNames contains the names of the CertificateRequest and
+	// Kubernetes CSR controllers, which are used to label their metrics.
+	var controllerNames []string
+
 	if !r.DisableCertificateRequestController {
-		if err = (&CertificateRequestReconciler{
+		crReconciler := &CertificateRequestReconciler{
 			IssuerTypes:        r.IssuerTypes,
 			ClusterIssuerTypes: r.ClusterIssuerTypes,
 
 			FieldOwner:       r.FieldOwner,
 			MaxRetryDuration: r.MaxRetryDuration,
+			MaxRequestAge:    r.MaxRequestAge,
+			SignTimeout:      r.SignTimeout,
+			MaxCSRSize:       r.MaxCSRSize,
 			EventSource:      eventSource,
 
-			Client:                   cl,
-			Sign:                     r.Sign,
-			IgnoreCertificateRequest: r.IgnoreCertificateRequest,
-			EventRecorder:            r.EventRecorder,
-			Clock:                    r.Clock,
-
-			SetCAOnCertificateRequest: r.SetCAOnCertificateRequest,
-
+			IssuerDeletedGracePeriod: r.IssuerDeletedGracePeriod,
+
+			PendingCertificateRequestResyncInterval: r.PendingCertificateRequestResyncInterval,
+			FormatPendingMessage:                    r.FormatPendingMessage,
+			ForceReissueAnnotation:                  r.ForceReissueAnnotation,
+
+			Client:                    cl,
+			Sign:                      sign,
+			AfterSign:                 r.AfterSign,
+			IgnoreCertificateRequest:  r.IgnoreCertificateRequest,
+			IgnoreIssuer:              r.IgnoreIssuer,
+			ApproveCertificateRequest: r.ApproveCertificateRequest,
+			GetNameConstraints:        r.GetNameConstraints,
+			QuotaCheck:                r.QuotaCheck,
+			DefaultUsages:             r.DefaultUsages,
+			Clock:                     r.Clock,
+
+			HTTPClientProvider: r.HTTPClientProvider,
+			APIReader:          apiReader,
+
+			SetCAOnCertificateRequest:   r.SetCAOnCertificateRequest,
+			PreserveExistingCertificate: r.PreserveExistingCertificate,
+
+			CheckChainCompleteness: r.CheckChainCompleteness,
+			RetryIncompleteChain:   r.RetryIncompleteChain,
+			RequireSCT:             r.RequireSCT,
+			MinimumSCTs:            r.MinimumSCTs,
+			RequireExactSANs:       r.RequireExactSANs,
+			DeduplicateSANs:        r.DeduplicateSANs,
+			RejectConflictingSANs:  r.RejectConflictingSANs,
+
+			TreatAllErrorsAsRetryable: r.TreatAllErrorsAsRetryable,
+			IsPermanent:               r.IsPermanent,
+			EscalateToIssuer:          r.EscalateToIssuer,
+
+			Logger: r.Logger,
+
+			DisableForceApply: r.DisableForceApply,
+
+			CacheSyncTimeout:     r.CacheSyncTimeout,
+			MaxBackoff:           r.MaxBackoff,
+			AuditResyncInterval:  r.AuditResyncInterval,
+			PreSetupWithManager:  r.PreSetupWithManager,
 			PostSetupWithManager: r.PostSetupWithManager,
-		}).SetupWithManager(ctx, mgr); err != nil {
+		}
+		crReconciler.EventRecorder = eventRecorderFor(crReconciler.issuerGroupKind)
+		if r.MaxIdenticalRequestEvents > 0 {
+			crReconciler.EventRecorder = newRateLimitedEventRecorder(
+				crReconciler.EventRecorder, crReconciler.issuerKey, r.Clock,
+				r.MaxIdenticalRequestEvents, r.IdenticalRequestEventsInterval,
+			)
+		}
+
+		if err = crReconciler.SetupWithManager(ctx, mgr); err != nil {
 			return fmt.Errorf("CertificateRequestReconciler: %w", err)
 		}
+
+		controllerNames = append(controllerNames, "certificaterequest")
 	}
 
-	if !r.DisableKubernetesCSRController {
-		if err = (&CertificateSigningRequestReconciler{
+	if !r.DisableKubernetesCSRController && len(r.ClusterIssuerTypes) == 0 && len(r.VirtualIssuers) == 0 {
+		logger.V(1).Info("Not setting up the Kubernetes CSR controller, because no ClusterIssuerTypes or VirtualIssuers are configured.")
+	} else if !r.DisableKubernetesCSRController {
+		csrReconciler := &CertificateSigningRequestReconciler{
 			IssuerTypes:        r.IssuerTypes,
 			ClusterIssuerTypes: r.ClusterIssuerTypes,
 
 			FieldOwner:       r.FieldOwner,
 			MaxRetryDuration: r.MaxRetryDuration,
+			MaxRequestAge:    r.MaxRequestAge,
+			SignTimeout:      r.SignTimeout,
+			MaxCSRSize:       r.MaxCSRSize,
 			EventSource:      eventSource,
 
+			PendingCertificateRequestResyncInterval: r.PendingCertificateRequestResyncInterval,
+
+			KubernetesSignerNames: r.KubernetesSignerNames,
+			VirtualIssuers:        virtualIssuers,
+
 			Client:                   cl,
-			Sign:                     r.Sign,
+			Sign:                     sign,
+			AfterSign:                r.AfterSign,
 			IgnoreCertificateRequest: r.IgnoreCertificateRequest,
-			EventRecorder:            r.EventRecorder,
+			IgnoreIssuer:             r.IgnoreIssuer,
+			GetNameConstraints:       r.GetNameConstraints,
+			QuotaCheck:               r.QuotaCheck,
 			Clock:                    r.Clock,
 
+			HTTPClientProvider: r.HTTPClientProvider,
+			APIReader:          apiReader,
+
+			CheckChainCompleteness: r.CheckChainCompleteness,
+			RetryIncompleteChain:   r.RetryIncompleteChain,
+			RequireSCT:             r.RequireSCT,
+			MinimumSCTs:            r.MinimumSCTs,
+			RequireExactSANs:       r.RequireExactSANs,
+			DeduplicateSANs:        r.DeduplicateSANs,
+			RejectConflictingSANs:  r.RejectConflictingSANs,
+
+			TreatAllErrorsAsRetryable: r.TreatAllErrorsAsRetryable,
+			IsPermanent:               r.IsPermanent,
+			EscalateToIssuer:          r.EscalateToIssuer,
+
+			Logger: r.Logger,
+
+			DisableForceApply: r.DisableForceApply,
+
+			CacheSyncTimeout:     r.CacheSyncTimeout,
+			MaxBackoff:           r.MaxBackoff,
+			AuditResyncInterval:  r.AuditResyncInterval,
+			PreSetupWithManager:  r.PreSetupWithManager,
 			PostSetupWithManager: r.PostSetupWithManager,
-		}).SetupWithManager(ctx, mgr); err != nil {
+		}
+		csrReconciler.EventRecorder = eventRecorderFor(csrReconciler.issuerGroupKind)
+		if r.MaxIdenticalRequestEvents > 0 {
+			csrReconciler.EventRecorder = newRateLimitedEventRecorder(
+				csrReconciler.EventRecorder, csrReconciler.issuerKey, r.Clock,
+				r.MaxIdenticalRequestEvents, r.IdenticalRequestEventsInterval,
+			)
+		}
+
+		if err = csrReconciler.SetupWithManager(ctx, mgr); err != nil {
 			return fmt.Errorf("CertificateRequestReconciler: %w", err)
 		}
+
+		controllerNames = append(controllerNames, "certificatesigningrequest")
+	}
+
+	if r.QueueDepthWarningThreshold > 0 && len(controllerNames) > 0 {
+		if err := mgr.Add(&queueDepthMonitor{
+			controllerNames: controllerNames,
+			threshold:       r.QueueDepthWarningThreshold,
+			gatherer:        metrics.Registry,
+			clock:           r.Clock,
+			logger:          logger.WithName("queue-depth-monitor"),
+		}); err != nil {
+			return fmt.Errorf("failed to add the queue depth monitor: %w", err)
+		}
 	}
 
 	return nil
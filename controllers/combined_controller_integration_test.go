@@ -19,6 +19,9 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -29,8 +32,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	v1alpha1 "github.com/cert-manager/issuer-lib/api/v1alpha1"
@@ -278,3 +283,574 @@ func TestCombinedControllerTemporaryFailedCertificateRequestRetrigger(t *testing
 		})
 	}
 }
+
+// TestCombinedControllerCATemporarilyUnavailable runs the
+// CombinedController against a real Kubernetes API server.
+func TestCombinedControllerCATemporarilyUnavailable(t *testing.T) { //nolint:tparallel
+	t.Parallel()
+
+	t.Log(
+		"Tests to show that a CertificateRequest stays Pending while the CA is temporarily unavailable",
+		"and that it becomes Ready once the CA recovers, without the CertificateRequest being failed",
+		"as long as the CA recovers within the MaxRetryDuration",
+	)
+
+	fieldOwner := "ca-temporarily-unavailable"
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	// caUnavailable is the fault injector for this test, when set to true the
+	// Sign function behaves as if the CA can not be reached.
+	caUnavailable := atomic.Bool{}
+	caUnavailable.Store(true)
+	ctx = setupControllersAPIServerAndClient(t, ctx, kubeClients,
+		func(mgr ctrl.Manager) controllerInterface {
+			return &CombinedController{
+				IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+				ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+				FieldOwner:         fieldOwner,
+				MaxRetryDuration:   time.Hour,
+				Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+					return nil
+				},
+				Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+					if caUnavailable.Load() {
+						return signer.PEMBundle{}, fmt.Errorf("[CA is unavailable]")
+					}
+					return signer.PEMBundle{
+						ChainPEM: []byte("cert"),
+					}, nil
+				},
+				EventRecorder: record.NewFakeRecorder(100),
+			}
+		},
+	)
+
+	t.Logf("Creating a namespace")
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	cr := cmgen.CertificateRequest(
+		"certificate-request-1",
+		cmgen.SetCertificateRequestNamespace(namespace),
+		cmgen.SetCertificateRequestCSR([]byte("doo")),
+		cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Name:  issuer.Name,
+			Kind:  issuer.Kind,
+			Group: api.SchemeGroupVersion.Group,
+		}),
+	)
+
+	t.Log("Creating the SimpleIssuer")
+	require.NoError(t, kubeClients.Client.Create(ctx, issuer))
+
+	checkComplete := kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Creating & approving the CertificateRequest")
+	createApprovedCR(t, ctx, kubeClients.Client, clock.RealClock{}, cr)
+	t.Log("Waiting for the CertificateRequest to be Pending because the CA is unavailable")
+	err := checkComplete(func(obj runtime.Object) error {
+		readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionFalse) ||
+			(readyCondition.Reason != cmapi.CertificateRequestReasonPending) ||
+			(readyCondition.Message != "CertificateRequest is not ready yet: [CA is unavailable]") {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+
+	checkComplete = kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Making the CA available again")
+	caUnavailable.Store(false)
+	// A Failed CertificateRequest is never reconciled again, so reaching the Ready
+	// state also proves that the CertificateRequest was not marked as Failed.
+	t.Log("Waiting for the CertificateRequest to become Ready")
+	err = checkComplete(func(obj runtime.Object) error {
+		readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionTrue) ||
+			(readyCondition.Reason != cmapi.CertificateRequestReasonIssued) ||
+			(readyCondition.Message != "issued") {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+}
+
+// TestCombinedControllerIssuerCreatedAfterCertificateRequest runs the
+// CombinedController against a real Kubernetes API server.
+func TestCombinedControllerIssuerCreatedAfterCertificateRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Log(
+		"Tests to show that a CertificateRequest that was created before its issuer existed",
+		"is signed once the issuer is created and has become Ready, without being re-triggered manually",
+	)
+
+	fieldOwner := "issuer-created-after-certificate-request"
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	ctx = setupControllersAPIServerAndClient(t, ctx, kubeClients,
+		func(mgr ctrl.Manager) controllerInterface {
+			return &CombinedController{
+				IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+				ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+				FieldOwner:         fieldOwner,
+				MaxRetryDuration:   time.Minute,
+				Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+					return nil
+				},
+				Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+					return signer.PEMBundle{
+						ChainPEM: []byte("cert"),
+					}, nil
+				},
+				EventRecorder: record.NewFakeRecorder(100),
+			}
+		},
+	)
+
+	t.Logf("Creating a namespace")
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	cr := cmgen.CertificateRequest(
+		"certificate-request-1",
+		cmgen.SetCertificateRequestNamespace(namespace),
+		cmgen.SetCertificateRequestCSR([]byte("doo")),
+		cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Name:  issuer.Name,
+			Kind:  issuer.Kind,
+			Group: api.SchemeGroupVersion.Group,
+		}),
+	)
+
+	checkComplete := kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Creating & approving the CertificateRequest")
+	createApprovedCR(t, ctx, kubeClients.Client, clock.RealClock{}, cr)
+	t.Log("Waiting for the CertificateRequest to be Pending because the issuer does not exist")
+	err := checkComplete(func(obj runtime.Object) error {
+		readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionFalse) ||
+			(readyCondition.Reason != cmapi.CertificateRequestReasonPending) ||
+			(readyCondition.Message != "SimpleIssuer.testing.cert-manager.io \"issuer-1\" not found. Waiting for it to be created.") {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+
+	checkComplete = kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Creating the SimpleIssuer")
+	require.NoError(t, kubeClients.Client.Create(ctx, issuer))
+	t.Log("Waiting for the CertificateRequest to become Ready")
+	err = checkComplete(func(obj runtime.Object) error {
+		readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionTrue) ||
+			(readyCondition.Reason != cmapi.CertificateRequestReasonIssued) ||
+			(readyCondition.Message != "issued") {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+}
+
+// TestCombinedControllerMaxRetryDurationExpiry runs the CombinedController
+// against a real Kubernetes API server.
+func TestCombinedControllerMaxRetryDurationExpiry(t *testing.T) {
+	t.Parallel()
+
+	t.Log(
+		"Tests to show that a CertificateRequest is failed permanently once the MaxRetryDuration has passed",
+		"the controller only uses the Clock for its timing logic, so a fake clock is used to expire the",
+		"MaxRetryDuration deterministically, without waiting for it to pass",
+	)
+
+	fieldOwner := "max-retry-duration-expiry"
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	// The creation timestamp of the CertificateRequest is set by the API server,
+	// so the fake clock starts at the current time.
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	ctx = setupControllersAPIServerAndClient(t, ctx, kubeClients,
+		func(mgr ctrl.Manager) controllerInterface {
+			return &CombinedController{
+				IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+				ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+				FieldOwner:         fieldOwner,
+				MaxRetryDuration:   time.Hour,
+				Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+					return nil
+				},
+				Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+					return signer.PEMBundle{}, fmt.Errorf("[CA is unavailable]")
+				},
+				EventRecorder: record.NewFakeRecorder(100),
+				Clock:         fakeClock,
+			}
+		},
+	)
+
+	t.Logf("Creating a namespace")
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	cr := cmgen.CertificateRequest(
+		"certificate-request-1",
+		cmgen.SetCertificateRequestNamespace(namespace),
+		cmgen.SetCertificateRequestCSR([]byte("doo")),
+		cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Name:  issuer.Name,
+			Kind:  issuer.Kind,
+			Group: api.SchemeGroupVersion.Group,
+		}),
+	)
+
+	t.Log("Creating the SimpleIssuer")
+	require.NoError(t, kubeClients.Client.Create(ctx, issuer))
+
+	checkComplete := kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Creating & approving the CertificateRequest")
+	createApprovedCR(t, ctx, kubeClients.Client, clock.RealClock{}, cr)
+	t.Log("Waiting for the CertificateRequest to be Pending because Sign failed")
+	err := checkComplete(func(obj runtime.Object) error {
+		readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionFalse) ||
+			(readyCondition.Reason != cmapi.CertificateRequestReasonPending) ||
+			(readyCondition.Message != "CertificateRequest is not ready yet: [CA is unavailable]") {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+
+	checkComplete = kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Advancing the fake clock past the MaxRetryDuration")
+	fakeClock.Step(2 * time.Hour)
+	// The failed Sign call requeued the CertificateRequest with backoff, the
+	// next reconcile observes that the MaxRetryDuration has passed.
+	t.Log("Waiting for the CertificateRequest to be Failed")
+	err = checkComplete(func(obj runtime.Object) error {
+		readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionFalse) ||
+			(readyCondition.Reason != cmapi.CertificateRequestReasonFailed) ||
+			(readyCondition.Message != "CertificateRequest has failed permanently: [CA is unavailable]") {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+}
+
+// TestCombinedControllerIssuerDeletedWhilePending runs the CombinedController
+// against a real Kubernetes API server.
+func TestCombinedControllerIssuerDeletedWhilePending(t *testing.T) {
+	t.Parallel()
+
+	t.Log(
+		"Tests to show that a CertificateRequest whose issuer is deleted while it is waiting to be signed",
+		"gets the IssuerNotFound condition and is failed permanently once the IssuerDeletedGracePeriod has passed",
+	)
+
+	fieldOwner := "issuer-deleted-while-pending"
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	ctx = setupControllersAPIServerAndClient(t, ctx, kubeClients,
+		func(mgr ctrl.Manager) controllerInterface {
+			return &CombinedController{
+				IssuerTypes:              []v1alpha1.Issuer{&api.SimpleIssuer{}},
+				ClusterIssuerTypes:       []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+				FieldOwner:               fieldOwner,
+				MaxRetryDuration:         time.Hour,
+				IssuerDeletedGracePeriod: 2 * time.Second,
+				Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+					return nil
+				},
+				Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+					return signer.PEMBundle{}, fmt.Errorf("[CA is unavailable]")
+				},
+				EventRecorder: record.NewFakeRecorder(100),
+				Clock:         clock.RealClock{},
+			}
+		},
+	)
+
+	t.Logf("Creating a namespace")
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	cr := cmgen.CertificateRequest(
+		"certificate-request-1",
+		cmgen.SetCertificateRequestNamespace(namespace),
+		cmgen.SetCertificateRequestCSR([]byte("doo")),
+		cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Name:  issuer.Name,
+			Kind:  issuer.Kind,
+			Group: api.SchemeGroupVersion.Group,
+		}),
+	)
+
+	t.Log("Creating the SimpleIssuer")
+	require.NoError(t, kubeClients.Client.Create(ctx, issuer))
+
+	checkComplete := kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Creating & approving the CertificateRequest")
+	createApprovedCR(t, ctx, kubeClients.Client, clock.RealClock{}, cr)
+	t.Log("Waiting for the CertificateRequest to be Pending because Sign failed")
+	err := checkComplete(func(obj runtime.Object) error {
+		readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionFalse) ||
+			(readyCondition.Reason != cmapi.CertificateRequestReasonPending) ||
+			(readyCondition.Message != "CertificateRequest is not ready yet: [CA is unavailable]") {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+
+	checkComplete = kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Deleting the SimpleIssuer")
+	require.NoError(t, kubeClients.Client.Delete(ctx, issuer))
+	t.Log("Waiting for the CertificateRequest to have the IssuerNotFound condition")
+	err = checkComplete(func(obj runtime.Object) error {
+		notFoundCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), v1alpha1.CertificateRequestConditionIssuerNotFound)
+
+		if (notFoundCondition == nil) ||
+			(notFoundCondition.Status != cmmeta.ConditionTrue) ||
+			(notFoundCondition.Reason != v1alpha1.CertificateRequestConditionReasonIssuerDeleted) {
+			return fmt.Errorf("incorrect issuer not found condition: %v", notFoundCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+
+	checkComplete = kubeClients.StartObjectWatch(t, ctx, cr)
+	t.Log("Waiting for the CertificateRequest to be Failed once the grace period has passed")
+	err = checkComplete(func(obj runtime.Object) error {
+		readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionFalse) ||
+			(readyCondition.Reason != cmapi.CertificateRequestReasonFailed) ||
+			(readyCondition.Message != "CertificateRequest has failed permanently: SimpleIssuer.testing.cert-manager.io \"issuer-1\" not found. The issuer was deleted and was not recreated within 2s.") {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+}
+
+// TestCombinedControllerIssuerOnly runs the CombinedController with both the
+// CertificateRequest and Kubernetes CSR controllers disabled against a real
+// Kubernetes API server.
+func TestCombinedControllerIssuerOnly(t *testing.T) {
+	t.Parallel()
+
+	t.Log(
+		"Tests to show that the CombinedController can run only the issuer controllers",
+		"without a Sign function, and that the issuers still become Ready",
+	)
+
+	fieldOwner := "issuer-only"
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	ctx = setupControllersAPIServerAndClient(t, ctx, kubeClients,
+		func(mgr ctrl.Manager) controllerInterface {
+			return &CombinedController{
+				IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+				ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+				FieldOwner:         fieldOwner,
+				MaxRetryDuration:   time.Minute,
+				Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+					return nil
+				},
+				DisableCertificateRequestController: true,
+				DisableKubernetesCSRController:      true,
+				EventRecorder:                       record.NewFakeRecorder(100),
+			}
+		},
+	)
+
+	t.Logf("Creating a namespace")
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	checkComplete := kubeClients.StartObjectWatch(t, ctx, issuer)
+	t.Log("Creating the SimpleIssuer")
+	require.NoError(t, kubeClients.Client.Create(ctx, issuer))
+	t.Log("Waiting for the SimpleIssuer to be Ready")
+	err := checkComplete(func(obj runtime.Object) error {
+		readyCondition := conditions.GetIssuerStatusCondition(obj.(*api.SimpleIssuer).Status.Conditions, cmapi.IssuerConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionTrue) ||
+			(readyCondition.Reason != v1alpha1.IssuerConditionReasonChecked) ||
+			(readyCondition.Message != "Succeeded checking the issuer") {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+}
+
+// issuerGetCounter is a http.RoundTripper that counts the GET requests for
+// individual SimpleIssuers (list and watch requests are not counted).
+type issuerGetCounter struct {
+	next  http.RoundTripper
+	count *atomic.Int32
+}
+
+func (c *issuerGetCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/simpleissuers/") {
+		c.count.Add(1)
+	}
+	return c.next.RoundTrip(req)
+}
+
+// TestCombinedControllerCachedIssuerReads runs the CombinedController against a
+// real Kubernetes API server.
+func TestCombinedControllerCachedIssuerReads(t *testing.T) {
+	t.Parallel()
+
+	t.Log(
+		"Tests to show that the CertificateRequest controller reads the issuer from the informer cache",
+		"i.e. that signing multiple CertificateRequests for the same issuer doesn't result in GET requests for the issuer",
+	)
+
+	fieldOwner := "cached-issuer-reads"
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	// The controller manager uses a copy of the rest config that counts the
+	// issuer GET requests.
+	issuerGets := &atomic.Int32{}
+	managerClients := *kubeClients
+	managerClients.Rest = rest.CopyConfig(kubeClients.Rest)
+	managerClients.Rest.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &issuerGetCounter{next: rt, count: issuerGets}
+	})
+
+	ctx = setupControllersAPIServerAndClient(t, ctx, &managerClients,
+		func(mgr ctrl.Manager) controllerInterface {
+			return &CombinedController{
+				IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+				ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+				FieldOwner:         fieldOwner,
+				MaxRetryDuration:   time.Minute,
+				Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+					return nil
+				},
+				Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+					return signer.PEMBundle{
+						ChainPEM: []byte("cert"),
+					}, nil
+				},
+				EventRecorder: record.NewFakeRecorder(100),
+			}
+		},
+	)
+
+	t.Logf("Creating a namespace")
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	t.Log("Creating the SimpleIssuer")
+	require.NoError(t, kubeClients.Client.Create(ctx, issuer))
+
+	for i := 0; i < 5; i++ {
+		cr := cmgen.CertificateRequest(
+			fmt.Sprintf("certificate-request-%d", i),
+			cmgen.SetCertificateRequestNamespace(namespace),
+			cmgen.SetCertificateRequestCSR([]byte("doo")),
+			cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+				Name:  issuer.Name,
+				Kind:  issuer.Kind,
+				Group: api.SchemeGroupVersion.Group,
+			}),
+		)
+
+		checkComplete := kubeClients.StartObjectWatch(t, ctx, cr)
+		t.Logf("Creating & approving the CertificateRequest %s", cr.Name)
+		createApprovedCR(t, ctx, kubeClients.Client, clock.RealClock{}, cr)
+		t.Logf("Waiting for the CertificateRequest %s to become Ready", cr.Name)
+		err := checkComplete(func(obj runtime.Object) error {
+			readyCondition := cmutil.GetCertificateRequestCondition(obj.(*cmapi.CertificateRequest), cmapi.CertificateRequestConditionReady)
+
+			if (readyCondition == nil) ||
+				(readyCondition.Status != cmmeta.ConditionTrue) ||
+				(readyCondition.Reason != cmapi.CertificateRequestReasonIssued) {
+				return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+			}
+
+			return nil
+		}, watch.Added, watch.Modified)
+		require.NoError(t, err)
+	}
+
+	require.Zero(t, issuerGets.Load(), "the issuer was read from the API server instead of the informer cache")
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	v1alpha1 "github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+)
+
+func TestCombinedControllerSignIsRequired(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name                                string
+		disableCertificateRequestController bool
+		disableKubernetesCSRController      bool
+		validateError                       *errormatch.Matcher
+	}
+
+	tests := []testCase{
+		{
+			name:          "all-controllers-enabled",
+			validateError: errormatch.ErrorContains("the Sign function must be set"),
+		},
+		{
+			name:                                "only-kubernetes-csr-controller-enabled",
+			disableCertificateRequestController: true,
+			validateError:                       errormatch.ErrorContains("the Sign function must be set"),
+		},
+		{
+			name:                           "only-certificate-request-controller-enabled",
+			disableKubernetesCSRController: true,
+			validateError:                  errormatch.ErrorContains("the Sign function must be set"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			controller := &CombinedController{
+				IssuerTypes:                         []v1alpha1.Issuer{&api.SimpleIssuer{}},
+				FieldOwner:                          "test-combined-controller",
+				Check:                               func(_ context.Context, _ v1alpha1.Issuer) error { return nil },
+				DisableCertificateRequestController: tc.disableCertificateRequestController,
+				DisableKubernetesCSRController:      tc.disableKubernetesCSRController,
+			}
+
+			// The configuration is validated before the manager is used.
+			err := controller.SetupWithManager(context.TODO(), nil)
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+		})
+	}
+}
+
+func TestCombinedControllerSignWorkerPoolMustNotBeNegative(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name           string
+		signWorkerPool SignWorkerPool
+	}
+
+	tests := []testCase{
+		{
+			name:           "negative-size",
+			signWorkerPool: SignWorkerPool{Size: -1, QueueDepth: 10},
+		},
+		{
+			name:           "negative-queue-depth",
+			signWorkerPool: SignWorkerPool{Size: 2, QueueDepth: -10},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			controller := &CombinedController{
+				IssuerTypes: []v1alpha1.Issuer{&api.SimpleIssuer{}},
+				FieldOwner:  "test-combined-controller",
+				Check:       func(_ context.Context, _ v1alpha1.Issuer) error { return nil },
+				Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+					return signer.PEMBundle{}, nil
+				},
+				SignWorkerPool: tc.signWorkerPool,
+			}
+
+			// The configuration is validated before the manager is used.
+			err := controller.SetupWithManager(context.TODO(), nil)
+			(*errormatch.ErrorContains("must not be negative"))(t, err)
+		})
+	}
+}
+
+func TestCombinedControllerCacheSyncTimeout(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+
+	// The manager is never started, so it doesn't connect to the API server.
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:1"}, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+	})
+	require.NoError(t, err)
+
+	cacheSyncTimeouts := map[string]time.Duration{}
+	combined := &CombinedController{
+		IssuerTypes:                         []v1alpha1.Issuer{&api.SimpleIssuer{}},
+		ClusterIssuerTypes:                  []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+		FieldOwner:                          "test-combined-controller-cache-sync-timeout",
+		Check:                               func(_ context.Context, _ v1alpha1.Issuer) error { return nil },
+		DisableCertificateRequestController: true,
+		DisableKubernetesCSRController:      true,
+		CacheSyncTimeout:                    7 * time.Minute,
+		PostSetupWithManager: func(_ context.Context, gvk schema.GroupVersionKind, _ ctrl.Manager, c controller.Controller) error {
+			// The controller.Options are stored on the internal controller
+			// implementation, which can't be imported.
+			cacheSyncTimeouts[gvk.Kind] = time.Duration(reflect.ValueOf(c).Elem().FieldByName("CacheSyncTimeout").Int())
+			return nil
+		},
+	}
+
+	require.NoError(t, combined.SetupWithManager(context.TODO(), mgr))
+	assert.Equal(t, map[string]time.Duration{
+		"SimpleIssuer":        7 * time.Minute,
+		"SimpleClusterIssuer": 7 * time.Minute,
+	}, cacheSyncTimeouts)
+}
+
+func TestCombinedControllerManagedIssuerGVKs(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+
+	// The manager is never started, so it doesn't connect to the API server.
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:1"}, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+	})
+	require.NoError(t, err)
+
+	combined := &CombinedController{
+		IssuerTypes:                         []v1alpha1.Issuer{&api.SimpleIssuer{}},
+		ClusterIssuerTypes:                  []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+		FieldOwner:                          "test-combined-controller-managed-issuer-gvks",
+		Check:                               func(_ context.Context, _ v1alpha1.Issuer) error { return nil },
+		DisableCertificateRequestController: true,
+		DisableKubernetesCSRController:      true,
+	}
+
+	assert.Nil(t, combined.ManagedIssuerGVKs())
+
+	require.NoError(t, combined.SetupWithManager(context.TODO(), mgr))
+	assert.Equal(t, []schema.GroupVersionKind{
+		api.SchemeGroupVersion.WithKind("SimpleIssuer"),
+		api.SchemeGroupVersion.WithKind("SimpleClusterIssuer"),
+	}, combined.ManagedIssuerGVKs())
+}
+
+func TestCombinedControllerWithoutClusterIssuerTypes(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name                string
+		clusterIssuerTypes  []v1alpha1.Issuer
+		expectedControllers []string
+	}
+
+	tests := []testCase{
+		{
+			name:               "with-cluster-issuer-types",
+			clusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+			expectedControllers: []string{
+				"SimpleIssuer",
+				"SimpleClusterIssuer",
+				"CertificateRequest",
+				"CertificateSigningRequest",
+			},
+		},
+		{
+			name: "without-cluster-issuer-types",
+			expectedControllers: []string{
+				"SimpleIssuer",
+				"CertificateRequest",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, api.AddToScheme(scheme))
+			require.NoError(t, setupCertificateRequestReconcilerScheme(scheme))
+			require.NoError(t, setupCertificateSigningRequestReconcilerScheme(scheme))
+
+			// The field indexes of the CertificateRequest and Kubernetes CSR
+			// controllers require a REST mapper, use a static one so that the
+			// manager doesn't connect to the API server.
+			restMapper := meta.NewDefaultRESTMapper(nil)
+			for gvk := range scheme.AllKnownTypes() {
+				scope := meta.RESTScopeNamespace
+				if gvk.Kind == "SimpleClusterIssuer" || gvk.Kind == "CertificateSigningRequest" {
+					scope = meta.RESTScopeRoot
+				}
+				restMapper.Add(gvk, scope)
+			}
+
+			// The manager is never started, so it doesn't connect to the API server.
+			mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:1"}, ctrl.Options{
+				Scheme:             scheme,
+				MetricsBindAddress: "0",
+				MapperProvider: func(_ *rest.Config, _ *http.Client) (meta.RESTMapper, error) {
+					return restMapper, nil
+				},
+			})
+			require.NoError(t, err)
+
+			var controllers []string
+			combined := &CombinedController{
+				IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+				ClusterIssuerTypes: tc.clusterIssuerTypes,
+				FieldOwner:         "test-combined-controller-" + tc.name,
+				Check:              func(_ context.Context, _ v1alpha1.Issuer) error { return nil },
+				Sign: func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+					return signer.PEMBundle{}, nil
+				},
+				PostSetupWithManager: func(_ context.Context, gvk schema.GroupVersionKind, _ ctrl.Manager, _ controller.Controller) error {
+					controllers = append(controllers, gvk.Kind)
+					return nil
+				},
+			}
+
+			require.NoError(t, combined.SetupWithManager(context.TODO(), mgr))
+			assert.Equal(t, tc.expectedControllers, controllers)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"runtime/debug"
+)
+
+// issuerLibModule is the path of the Go module of issuer-lib, it is used to
+// look up the issuer-lib version in the build info of the binary.
+const issuerLibModule = "github.com/cert-manager/issuer-lib"
+
+// ComponentInfo identifies the controller that is built using issuer-lib, eg.
+// {Name: "my-issuer", Version: "v1.2.3"}. It is used to correlate events and
+// logs with a specific build of the controller.
+type ComponentInfo struct {
+	Name    string
+	Version string
+}
+
+// IsZero returns true if neither the name nor the version is set.
+func (c ComponentInfo) IsZero() bool {
+	return c.Name == "" && c.Version == ""
+}
+
+// String returns the name and version in the "<name>@<version>" format. The
+// version is omitted if it is not set.
+func (c ComponentInfo) String() string {
+	if c.Version == "" {
+		return c.Name
+	}
+	return c.Name + "@" + c.Version
+}
+
+// eventComponentName returns the name of the component that is used as the
+// source of events, which includes the component info if it is set, eg.
+// "simpleissuer.testing.cert-manager.io (my-issuer@v1.2.3)".
+func (c ComponentInfo) eventComponentName(component string) string {
+	if c.IsZero() {
+		return component
+	}
+	return component + " (" + c.String() + ")"
+}
+
+// issuerLibVersion returns the version of issuer-lib that the binary was built
+// with, or "unknown" if the build info is not available.
+func issuerLibVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	module := &info.Main
+	if module.Path != issuerLibModule {
+		module = nil
+		for _, dep := range info.Deps {
+			if dep.Path == issuerLibModule {
+				module = dep
+				break
+			}
+		}
+	}
+	if module == nil {
+		return "unknown"
+	}
+
+	if module.Replace != nil {
+		module = module.Replace
+	}
+	if module.Version == "" {
+		// eg. when issuer-lib is replaced by a local directory
+		return "(devel)"
+	}
+	return module.Version
+}
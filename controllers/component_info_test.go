@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComponentInfoEventComponentName(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name          string
+		componentInfo ComponentInfo
+		expected      string
+	}
+
+	tests := []testCase{
+		{
+			name:     "not-set",
+			expected: "simpleissuer.testing.cert-manager.io",
+		},
+		{
+			name:          "name-only",
+			componentInfo: ComponentInfo{Name: "my-issuer"},
+			expected:      "simpleissuer.testing.cert-manager.io (my-issuer)",
+		},
+		{
+			name:          "name-and-version",
+			componentInfo: ComponentInfo{Name: "my-issuer", Version: "v1.2.3"},
+			expected:      "simpleissuer.testing.cert-manager.io (my-issuer@v1.2.3)",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.componentInfo.eventComponentName("simpleissuer.testing.cert-manager.io"))
+		})
+	}
+}
+
+func TestIssuerLibVersion(t *testing.T) {
+	t.Parallel()
+
+	// The tests are part of the issuer-lib module itself, so the version is
+	// known but is not a released version.
+	assert.NotEqual(t, "unknown", issuerLibVersion())
+	assert.NotEmpty(t, issuerLibVersion())
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// controllerOptions returns the controller options that are configured on a
+// reconciler: the log constructor for its Logger, the CacheSyncTimeout and the
+// MaxBackoff rate limiter. They are set on the builder before the
+// PreSetupWithManager hook is called, so that the hook can override them.
+func controllerOptions(
+	logger logr.Logger,
+	gvk schema.GroupVersionKind,
+	cacheSyncTimeout time.Duration,
+	maxBackoff time.Duration,
+) controller.Options {
+	options := controller.Options{
+		CacheSyncTimeout: cacheSyncTimeout,
+		RateLimiter:      newMaxBackoffRateLimiter(maxBackoff),
+	}
+	if logger.GetSink() != nil {
+		options.LogConstructor = newLogConstructor(logger, gvk)
+	}
+	return options
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	controllerpkg "sigs.k8s.io/controller-runtime/pkg/controller"
+
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+)
+
+func newTestManager(t *testing.T) ctrl.Manager {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+
+	// The manager is never started, so it doesn't connect to the API server.
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:1"}, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+	})
+	require.NoError(t, err)
+	return mgr
+}
+
+func TestControllerOptions(t *testing.T) {
+	t.Parallel()
+
+	gvk := api.SchemeGroupVersion.WithKind("SimpleIssuer")
+
+	t.Run("configured", func(t *testing.T) {
+		logger := funcr.New(func(_, _ string) {}, funcr.Options{})
+
+		options := controllerOptions(logger, gvk, 7*time.Minute, 5*time.Second)
+		assert.Equal(t, 7*time.Minute, options.CacheSyncTimeout)
+		assert.IsType(t, maxBackoffRateLimiter{}, options.RateLimiter)
+		assert.NotNil(t, options.LogConstructor)
+	})
+
+	t.Run("nothing-configured", func(t *testing.T) {
+		options := controllerOptions(logr.Logger{}, gvk, 0, 0)
+		assert.Equal(t, controllerpkg.Options{}, options)
+	})
+}
+
+// TestIssuerReconcilerSetupWithManagerOptions verifies that the Logger and
+// CacheSyncTimeout options of the reconciler are set on the built controller,
+// and that the options set by the PreSetupWithManager hook win. The rate
+// limiter is not exposed by the built controller, see TestControllerOptions.
+func TestIssuerReconcilerSetupWithManagerOptions(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name                     string
+		preSetupWithManager      func(context.Context, schema.GroupVersionKind, ctrl.Manager, *builder.Builder) error
+		expectedMaxConcurrent    int64
+		expectedCacheSyncTimeout time.Duration
+	}
+
+	tests := []testCase{
+		{
+			name:                     "reconciler-options",
+			expectedMaxConcurrent:    1,
+			expectedCacheSyncTimeout: 7 * time.Minute,
+		},
+		{
+			name: "hook-options-win",
+			preSetupWithManager: func(_ context.Context, _ schema.GroupVersionKind, _ ctrl.Manager, b *builder.Builder) error {
+				b.WithOptions(controllerpkg.Options{
+					MaxConcurrentReconciles: 3,
+					CacheSyncTimeout:        time.Minute,
+				})
+				return nil
+			},
+			expectedMaxConcurrent:    3,
+			expectedCacheSyncTimeout: time.Minute,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mgr := newTestManager(t)
+
+			var logs []string
+			logger := funcr.New(func(_, args string) {
+				logs = append(logs, args)
+			}, funcr.Options{})
+
+			var built controllerpkg.Controller
+			controller := IssuerReconciler{
+				ForObject:           &api.SimpleIssuer{},
+				FieldOwner:          "test-issuer-reconciler-setup-options",
+				Logger:              logger,
+				CacheSyncTimeout:    7 * time.Minute,
+				MaxBackoff:          5 * time.Second,
+				PreSetupWithManager: tc.preSetupWithManager,
+				PostSetupWithManager: func(_ context.Context, _ schema.GroupVersionKind, _ ctrl.Manager, c controllerpkg.Controller) error {
+					built = c
+					return nil
+				},
+			}
+
+			require.NoError(t, controller.SetupWithManager(context.TODO(), mgr))
+			require.NotNil(t, built)
+
+			// The fields of the controller implementation are not part of the
+			// Controller interface.
+			fields := reflect.ValueOf(built).Elem()
+			assert.Equal(t, tc.expectedMaxConcurrent, fields.FieldByName("MaxConcurrentReconciles").Int())
+			assert.Equal(t, int64(tc.expectedCacheSyncTimeout), fields.FieldByName("CacheSyncTimeout").Int())
+
+			if tc.preSetupWithManager == nil {
+				logConstructor := fields.FieldByName("LogConstructor").Interface().(func(*ctrl.Request) logr.Logger)
+				logConstructor(nil).Info("test")
+				require.Len(t, logs, 1)
+				assert.Contains(t, logs[0], `"controller"="simpleissuer"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// labeledEventRecorder is an event recorder that adds the labels returned by
+// the labeler for the involved object to each event. The client-go event
+// recorder can't set labels on events, so they are added as annotations of the
+// event. Annotations that are passed explicitly take precedence.
+type labeledEventRecorder struct {
+	recorder record.EventRecorder
+	labeler  func(obj client.Object) map[string]string
+}
+
+var _ record.EventRecorder = &labeledEventRecorder{}
+
+func (r *labeledEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, "%s", message)
+}
+
+func (r *labeledEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, messageFmt, args...)
+}
+
+func (r *labeledEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if obj, ok := object.(client.Object); ok {
+		if labels := r.labeler(obj); len(labels) > 0 {
+			merged := make(map[string]string, len(labels)+len(annotations))
+			for key, value := range labels {
+				merged[key] = value
+			}
+			for key, value := range annotations {
+				merged[key] = value
+			}
+			annotations = merged
+		}
+	}
+
+	r.recorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/testutil"
+)
+
+func TestLabeledEventRecorder(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, setupCertificateRequestReconcilerScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+
+	broadcaster := record.NewBroadcaster()
+	defer broadcaster.Shutdown()
+
+	events := make(chan *corev1.Event, 10)
+	broadcaster.StartEventWatcher(func(event *corev1.Event) {
+		events <- event
+	})
+
+	// The tenant is derived from the issuer: from its label for an issuer and
+	// from the issuerRef for a CertificateRequest.
+	recorder := &labeledEventRecorder{
+		recorder: broadcaster.NewRecorder(scheme, corev1.EventSource{Component: "test"}),
+		labeler: func(obj client.Object) map[string]string {
+			switch obj := obj.(type) {
+			case *api.SimpleIssuer:
+				return map[string]string{"example.com/tenant": obj.Labels["example.com/tenant"]}
+			case *cmapi.CertificateRequest:
+				return map[string]string{"example.com/tenant": obj.Spec.IssuerRef.Name}
+			}
+			return nil
+		},
+	}
+
+	type testCase struct {
+		name                string
+		record              func(object runtime.Object)
+		object              runtime.Object
+		expectedAnnotations map[string]string
+	}
+
+	tests := []testCase{
+		{
+			name: "issuer",
+			record: func(object runtime.Object) {
+				recorder.Event(object, corev1.EventTypeNormal, "Test", "issuer")
+			},
+			object: testutil.SimpleIssuer("issuer-1",
+				testutil.SetSimpleIssuerNamespace("ns1"),
+				func(si *api.SimpleIssuer) {
+					si.Labels = map[string]string{"example.com/tenant": "team-a"}
+				},
+			),
+			expectedAnnotations: map[string]string{"example.com/tenant": "team-a"},
+		},
+		{
+			name: "certificate-request",
+			record: func(object runtime.Object) {
+				recorder.Eventf(object, corev1.EventTypeWarning, "Test", "%s", "certificate-request")
+			},
+			object: cmgen.CertificateRequest("cr1",
+				cmgen.SetCertificateRequestNamespace("ns1"),
+				cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "team-b",
+					Kind:  "SimpleIssuer",
+					Group: api.SchemeGroupVersion.Group,
+				}),
+			),
+			expectedAnnotations: map[string]string{"example.com/tenant": "team-b"},
+		},
+		{
+			name: "explicit-annotations-take-precedence",
+			record: func(object runtime.Object) {
+				recorder.AnnotatedEventf(object, map[string]string{
+					"example.com/tenant": "explicit",
+					"example.com/other":  "value",
+				}, corev1.EventTypeNormal, "Test", "explicit-annotations-take-precedence")
+			},
+			object: cmgen.CertificateRequest("cr1",
+				cmgen.SetCertificateRequestNamespace("ns1"),
+				cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name: "team-b",
+				}),
+			),
+			expectedAnnotations: map[string]string{
+				"example.com/tenant": "explicit",
+				"example.com/other":  "value",
+			},
+		},
+		{
+			name: "no-labels",
+			record: func(object runtime.Object) {
+				recorder.Event(object, corev1.EventTypeNormal, "Test", "no-labels")
+			},
+			object: testutil.SimpleClusterIssuer("cluster-issuer-1"),
+		},
+	}
+
+	// The events are recorded sequentially, because they are all received
+	// through the same broadcaster.
+	for _, tc := range tests {
+		tc.record(tc.object)
+
+		select {
+		case event := <-events:
+			assert.Equal(t, tc.name, event.Message)
+			assert.Equal(t, tc.expectedAnnotations, event.Annotations, tc.name)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for the %q event", tc.name)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+)
+
+// DefaultIdenticalRequestEventsInterval is the default interval in which at most
+// MaxIdenticalRequestEvents identical events are recorded for the requests of
+// an issuer.
+const DefaultIdenticalRequestEventsInterval = time.Minute
+
+// rateLimitedEventRecorder is an event recorder that records at most max
+// identical events (same type, reason and message) per interval for all the
+// requests that reference the same issuer. Additional identical events are
+// dropped. The kube event recorder only aggregates events per object, so
+// without this limit an unavailable CA results in an event for every request.
+// Events for objects whose issuer can't be determined are always recorded.
+type rateLimitedEventRecorder struct {
+	recorder record.EventRecorder
+	issuerOf func(object runtime.Object) (string, bool)
+	clock    clock.PassiveClock
+	max      int
+	interval time.Duration
+
+	mu        sync.Mutex
+	windows   map[rateLimitedEventKey]*rateLimitedEventWindow
+	lastPrune time.Time
+}
+
+type rateLimitedEventKey struct {
+	issuer    string
+	eventtype string
+	reason    string
+	message   string
+}
+
+type rateLimitedEventWindow struct {
+	start time.Time
+	count int
+}
+
+var _ record.EventRecorder = &rateLimitedEventRecorder{}
+
+func newRateLimitedEventRecorder(
+	recorder record.EventRecorder,
+	issuerOf func(object runtime.Object) (string, bool),
+	clock clock.PassiveClock,
+	max int,
+	interval time.Duration,
+) *rateLimitedEventRecorder {
+	if interval <= 0 {
+		interval = DefaultIdenticalRequestEventsInterval
+	}
+
+	return &rateLimitedEventRecorder{
+		recorder: recorder,
+		issuerOf: issuerOf,
+		clock:    clock,
+		max:      max,
+		interval: interval,
+		windows:  map[rateLimitedEventKey]*rateLimitedEventWindow{},
+	}
+}
+
+// allow returns true if the event should be recorded.
+func (r *rateLimitedEventRecorder) allow(object runtime.Object, eventtype, reason, message string) bool {
+	issuer, ok := r.issuerOf(object)
+	if !ok {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	r.pruneExpiredWindows(now)
+
+	key := rateLimitedEventKey{issuer, eventtype, reason, message}
+	window, ok := r.windows[key]
+	if !ok || now.Sub(window.start) >= r.interval {
+		window = &rateLimitedEventWindow{start: now}
+		r.windows[key] = window
+	}
+
+	if window.count >= r.max {
+		return false
+	}
+
+	window.count++
+	return true
+}
+
+// pruneExpiredWindows removes the windows that have expired, so that the
+// memory usage does not grow with the number of distinct events. This is done
+// at most once per interval.
+func (r *rateLimitedEventRecorder) pruneExpiredWindows(now time.Time) {
+	if now.Sub(r.lastPrune) < r.interval {
+		return
+	}
+	r.lastPrune = now
+
+	for key, window := range r.windows {
+		if now.Sub(window.start) >= r.interval {
+			delete(r.windows, key)
+		}
+	}
+}
+
+func (r *rateLimitedEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if r.allow(object, eventtype, reason, message) {
+		r.recorder.Event(object, eventtype, reason, message)
+	}
+}
+
+func (r *rateLimitedEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if r.allow(object, eventtype, reason, message) {
+		r.recorder.Event(object, eventtype, reason, message)
+	}
+}
+
+func (r *rateLimitedEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if r.allow(object, eventtype, reason, message) {
+		r.recorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/testutil"
+)
+
+func TestRateLimitedEventRecorder(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, setupCertificateRequestReconcilerScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+
+	controller := CertificateRequestReconciler{
+		IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+		ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+	}
+	require.NoError(t, controller.setIssuersGroupVersionKind(scheme))
+
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	fakeRecorder := record.NewFakeRecorder(1000)
+	recorder := newRateLimitedEventRecorder(fakeRecorder, controller.issuerKey, fakeClock, 3, time.Minute)
+
+	newCR := func(name string, issuerName string) runtime.Object {
+		return cmgen.CertificateRequest(name,
+			cmgen.SetCertificateRequestNamespace("ns1"),
+			cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+				Name:  issuerName,
+				Kind:  "SimpleIssuer",
+				Group: api.SchemeGroupVersion.Group,
+			}),
+		)
+	}
+
+	failAll := func(issuerName string, message string) {
+		for i := 0; i < 100; i++ {
+			recorder.Eventf(newCR(fmt.Sprintf("cr-%d", i), issuerName), corev1.EventTypeWarning, "RetryableError", "Failed to sign CertificateRequest, will retry: %s", message)
+		}
+	}
+
+	// The CA is down and 100 requests of the same issuer fail with the same
+	// error: only the first 3 events are recorded.
+	failAll("issuer-1", "[CA unavailable]")
+	assert.Len(t, chanToSlice(fakeRecorder.Events), 3)
+
+	// The limit applies per issuer and per message.
+	failAll("issuer-2", "[CA unavailable]")
+	failAll("issuer-1", "[CA timeout]")
+	assert.Len(t, chanToSlice(fakeRecorder.Events), 6)
+
+	// Events for objects without a matching issuer are not limited.
+	for i := 0; i < 10; i++ {
+		recorder.Event(testutil.SimpleIssuer("issuer-1", testutil.SetSimpleIssuerNamespace("ns1")), corev1.EventTypeWarning, "RetryableError", "[CA unavailable]")
+	}
+	assert.Len(t, chanToSlice(fakeRecorder.Events), 10)
+
+	// Once the interval has passed, the events are recorded again.
+	fakeClock.Step(time.Minute)
+	failAll("issuer-1", "[CA unavailable]")
+	assert.Equal(t, []string{
+		"Warning RetryableError Failed to sign CertificateRequest, will retry: [CA unavailable]",
+		"Warning RetryableError Failed to sign CertificateRequest, will retry: [CA unavailable]",
+		"Warning RetryableError Failed to sign CertificateRequest, will retry: [CA unavailable]",
+	}, chanToSlice(fakeRecorder.Events))
+}
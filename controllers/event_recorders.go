@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	v1alpha1 "github.com/cert-manager/issuer-lib/api/v1alpha1"
+)
+
+// eventComponentName returns the component name that is used as the source of
+// the events related to an issuer type, eg. "simpleissuer.testing.cert-manager.io".
+func eventComponentName(gvk schema.GroupVersionKind) string {
+	return strings.ToLower(gvk.Kind) + "." + gvk.Group
+}
+
+// newIssuerTypeEventRecorders creates an event recorder for each issuer type,
+// using the component name derived from the issuer kind.
+func newIssuerTypeEventRecorders(
+	scheme *runtime.Scheme,
+	issuerTypes []v1alpha1.Issuer,
+	newRecorder func(component string) record.EventRecorder,
+) (map[schema.GroupKind]record.EventRecorder, error) {
+	recorders := make(map[schema.GroupKind]record.EventRecorder, len(issuerTypes))
+	for _, issuerType := range issuerTypes {
+		gvk, err := apiutil.GVKForObject(issuerType, scheme)
+		if err != nil {
+			return nil, err
+		}
+
+		recorders[gvk.GroupKind()] = newRecorder(eventComponentName(gvk))
+	}
+
+	return recorders, nil
+}
+
+// issuerTypeEventRecorder is an event recorder that records each event using
+// the event recorder of the issuer type that the object belongs to. This way,
+// the events of the CertificateRequest and Kubernetes CSR controllers are
+// attributed to the issuer type that is responsible for the request.
+// If the issuer type can't be determined, the fallback recorder is used.
+type issuerTypeEventRecorder struct {
+	recorders    map[schema.GroupKind]record.EventRecorder
+	fallback     record.EventRecorder
+	issuerTypeOf func(object runtime.Object) (schema.GroupKind, bool)
+}
+
+var _ record.EventRecorder = &issuerTypeEventRecorder{}
+
+func (r *issuerTypeEventRecorder) recorderFor(object runtime.Object) record.EventRecorder {
+	if groupKind, ok := r.issuerTypeOf(object); ok {
+		if recorder, ok := r.recorders[groupKind]; ok {
+			return recorder
+		}
+	}
+
+	return r.fallback
+}
+
+func (r *issuerTypeEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.recorderFor(object).Event(object, eventtype, reason, message)
+}
+
+func (r *issuerTypeEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.recorderFor(object).Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+func (r *issuerTypeEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.recorderFor(object).AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+}
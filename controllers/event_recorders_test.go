@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/testutil"
+)
+
+func TestIssuerTypeEventRecorder(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, setupCertificateRequestReconcilerScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+
+	controller := CertificateRequestReconciler{
+		IssuerTypes:        []v1alpha1.Issuer{&api.SimpleIssuer{}},
+		ClusterIssuerTypes: []v1alpha1.Issuer{&api.SimpleClusterIssuer{}},
+	}
+	require.NoError(t, controller.setIssuersGroupVersionKind(scheme))
+
+	broadcaster := record.NewBroadcaster()
+	defer broadcaster.Shutdown()
+
+	events := make(chan *corev1.Event, 10)
+	broadcaster.StartEventWatcher(func(event *corev1.Event) {
+		events <- event
+	})
+
+	newRecorder := func(component string) record.EventRecorder {
+		return broadcaster.NewRecorder(scheme, corev1.EventSource{Component: component})
+	}
+
+	recorders, err := newIssuerTypeEventRecorders(scheme, controller.allIssuerTypes(), newRecorder)
+	require.NoError(t, err)
+
+	recorder := &issuerTypeEventRecorder{
+		recorders:    recorders,
+		fallback:     newRecorder("fallback"),
+		issuerTypeOf: controller.issuerGroupKind,
+	}
+
+	type testCase struct {
+		name              string
+		object            runtime.Object
+		expectedComponent string
+	}
+
+	tests := []testCase{
+		{
+			name: "issuer",
+			object: cmgen.CertificateRequest("cr1",
+				cmgen.SetCertificateRequestNamespace("ns1"),
+				cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Kind:  "SimpleIssuer",
+					Group: api.SchemeGroupVersion.Group,
+				}),
+			),
+			expectedComponent: "simpleissuer.testing.cert-manager.io",
+		},
+		{
+			name: "cluster-issuer",
+			object: cmgen.CertificateRequest("cr1",
+				cmgen.SetCertificateRequestNamespace("ns1"),
+				cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "cluster-issuer-1",
+					Kind:  "SimpleClusterIssuer",
+					Group: api.SchemeGroupVersion.Group,
+				}),
+			),
+			expectedComponent: "simpleclusterissuer.testing.cert-manager.io",
+		},
+		{
+			name: "foreign-issuer",
+			object: cmgen.CertificateRequest("cr1",
+				cmgen.SetCertificateRequestNamespace("ns1"),
+				cmgen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Kind:  "Issuer",
+					Group: "cert-manager.io",
+				}),
+			),
+			expectedComponent: "fallback",
+		},
+		{
+			name:              "not-a-certificate-request",
+			object:            testutil.SimpleIssuer("issuer-1", testutil.SetSimpleIssuerNamespace("ns1")),
+			expectedComponent: "fallback",
+		},
+	}
+
+	// The events are recorded sequentially, because they are all received
+	// through the same broadcaster.
+	for _, tc := range tests {
+		recorder.Event(tc.object, corev1.EventTypeNormal, "Test", tc.name)
+
+		select {
+		case event := <-events:
+			assert.Equal(t, tc.name, event.Message)
+			assert.Equal(t, tc.expectedComponent, event.Source.Component, tc.name)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for the %q event", tc.name)
+		}
+	}
+}
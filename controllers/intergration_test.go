@@ -47,6 +47,21 @@ func createNS(t *testing.T, ctx context.Context, kc client.Client, nsName string
 	require.NoError(t, kc.Create(ctx, &ns))
 }
 
+func installCRDs(t *testing.T, kubeClients *testresource.OwnedKubeClients) {
+	t.Helper()
+
+	t.Log("Installing cert-manager CRDs")
+	_, err := kubeClients.InstallCRDs(envtest.CRDInstallOptions{
+		Scheme: kubeClients.Scheme,
+		Paths: []string{
+			os.Getenv("SIMPLE_CRDS"),
+			os.Getenv("CERT_MANAGER_CRDS"),
+		},
+		ErrorIfPathMissing: true,
+	})
+	require.NoError(t, err)
+}
+
 type controllerInterface interface {
 	SetupWithManager(ctx context.Context, mgr ctrl.Manager) error
 }
@@ -66,16 +81,7 @@ func setupControllersAPIServerAndClient(t *testing.T, parentCtx context.Context,
 	ctrl.SetLogger(logger)
 	klog.SetLogger(logger)
 
-	t.Log("Installing cert-manager CRDs")
-	_, err := kubeClients.InstallCRDs(envtest.CRDInstallOptions{
-		Scheme: kubeClients.Scheme,
-		Paths: []string{
-			os.Getenv("SIMPLE_CRDS"),
-			os.Getenv("CERT_MANAGER_CRDS"),
-		},
-		ErrorIfPathMissing: true,
-	})
-	require.NoError(t, err)
+	installCRDs(t, kubeClients)
 
 	scheme := runtime.NewScheme()
 	require.NoError(t, setupCertificateRequestReconcilerScheme(scheme))
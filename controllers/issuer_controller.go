@@ -20,13 +20,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/clock"
@@ -48,8 +54,11 @@ import (
 
 const (
 	eventIssuerChecked        = "Checked"
+	eventIssuerRecovered      = "Recovered"
 	eventIssuerRetryableError = "RetryableError"
 	eventIssuerPermanentError = "PermanentError"
+	eventIssuerBootstrapped   = "Bootstrapped"
+	eventIssuerBootstrapError = "BootstrapError"
 )
 
 // IssuerReconciler reconciles a SimpleIssuer object
@@ -67,12 +76,100 @@ type IssuerReconciler struct {
 	// reconciling an issuer resource.
 	signer.IgnoreIssuer
 
+	// IssuerConfigMapRefs is an optional function that returns the ConfigMaps
+	// that an issuer takes its configuration from (eg. a CA bundle). The issuer
+	// is checked again when one of these ConfigMaps is created, updated or
+	// deleted, so that its readiness stays in sync with the configuration.
+	// Issuers that have failed permanently are not checked again until their
+	// spec changes.
+	// IMPORTANT: the controller needs "list" and "watch" permissions for
+	// ConfigMaps, because the ConfigMaps are cached by the manager.
+	IssuerConfigMapRefs func(issuerObject v1alpha1.Issuer) []types.NamespacedName
+
+	// OnIssuerBootstrap is an optional function that is called once per issuer,
+	// before the issuer is checked for the first time. It can be used for
+	// one-time setup, eg. to register a webhook with the CA or to provision a
+	// tenant. If it returns an error, the issuer is not Ready and the function
+	// is retried with backoff; Check is only called after it has succeeded.
+	// Once it succeeds, the IssuerBootstrappedAnnotation is set on the issuer,
+	// and the issuer is checked in the reconcile that is triggered by this
+	// update. The function can be called more than once if setting the annotation
+	// fails, so it should be idempotent.
+	// IMPORTANT: the controller needs "patch" permissions for the issuer
+	// resources to set the annotation.
+	OnIssuerBootstrap func(ctx context.Context, issuerObject v1alpha1.Issuer) error
+
+	// MaxIssuerConditions is the maximum number of conditions that the
+	// controller sets on the issuer status, including the Ready condition.
+	// If Check reports more conditions than fit, the conditions with the
+	// oldest last transition time are removed. The Ready condition is never
+	// removed. If zero, the number of conditions is not limited.
+	MaxIssuerConditions int
+
+	// HTTPClientProvider is an optional function that returns a pre-configured
+	// HTTP client (eg. with proxy, TLS trust and timeout settings) that is
+	// passed to the Check function using the context. The client can be retrieved using
+	// signer.HTTPClientFromContext.
+	HTTPClientProvider func() *http.Client
+
+	// APIReader is an optional reader that is passed to the Check
+	// functions using the context, so they can read Kubernetes objects using the
+	// credentials of the controller. It can be retrieved using
+	// signer.APIReaderFromContext. The CombinedController sets it to the
+	// (uncached) API reader of the manager.
+	APIReader client.Reader
+
 	// EventRecorder is used for creating Kubernetes events on resources.
 	EventRecorder record.EventRecorder
 
 	// Clock is used to mock condition transition times in tests.
 	Clock clock.PassiveClock
 
+	// Logger is an optional logger that is used instead of the manager's logger.
+	// This can be used to inject a pre-configured logger, eg. to filter the
+	// verbosity levels used by the controller (see README.md).
+	Logger logr.Logger
+
+	// IsPermanent is an optional function that classifies additional Check
+	// errors as permanent, in addition to the errors that wrap a
+	// signer.PermanentError.
+	IsPermanent func(error) bool
+
+	// DisableForceApply is used to apply status patches without forcing the
+	// ownership of fields that are managed by another field manager. When this
+	// option is set, such conflicts are returned as errors instead of being
+	// overwritten. Forcing is enabled by default.
+	DisableForceApply bool
+
+	// CacheSyncTimeout is the maximum duration that the controller waits for
+	// its informer caches to sync when it starts. This can be increased on
+	// large clusters where the initial sync takes longer. If zero, the manager's
+	// controller options are used, which default to 2 minutes.
+	CacheSyncTimeout time.Duration
+
+	// MaxBackoff caps the exponential backoff of the controller's rate limiter,
+	// so that eg. a CA that becomes available again is detected within a
+	// bounded time. If zero, the backoff of the default rate limiter is used,
+	// which grows up to 1000 seconds.
+	MaxBackoff time.Duration
+
+	// AuditResyncInterval is the interval at which all the issuers are
+	// reconciled again, independent of watch events and of the informer
+	// resync, eg. to prove that the issuers are periodically re-evaluated for
+	// compliance purposes. A log line is written for every cycle. If zero,
+	// this is disabled.
+	AuditResyncInterval time.Duration
+
+	// PreSetupWithManager is an optional function that is called before the
+	// controller is built. It can be used to customize the controller builder,
+	// eg. to set a custom rate limiter using WithOptions or to add event filters.
+	// The Logger, CacheSyncTimeout and MaxBackoff options of the reconciler
+	// are set on the builder before the hook is called. WithOptions replaces
+	// all the options, so a hook that calls WithOptions has to set these
+	// options again if it wants to keep them.
+	PreSetupWithManager func(context.Context, schema.GroupVersionKind, ctrl.Manager, *builder.Builder) error
+	// PostSetupWithManager is an optional function that is called after the
+	// controller is built, eg. to add extra watches or health checks.
 	PostSetupWithManager func(context.Context, schema.GroupVersionKind, ctrl.Manager, controller.Controller) error
 }
 
@@ -95,9 +192,13 @@ func (r *IssuerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 		if err := r.Client.Status().Patch(ctx, cr, patch, &client.SubResourcePatchOptions{
 			PatchOptions: client.PatchOptions{
 				FieldManager: r.FieldOwner,
-				Force:        ptr.To(true),
+				Force:        ptr.To(!r.DisableForceApply),
 			},
 		}); err != nil {
+			if isStatusSubresourceNotFound(err) {
+				err = fmt.Errorf("failed to patch the status of the issuer, the status subresource is probably not enabled on the %s CRD (add \"subresources: {status: {}}\" to the CRD version): %w", r.ForObject.GetObjectKind().GroupVersionKind().GroupKind(), err)
+				return ctrl.Result{}, utilerrors.NewAggregate([]error{err, returnedError})
+			}
 			if !apierrors.IsNotFound(err) {
 				return ctrl.Result{}, utilerrors.NewAggregate([]error{err, returnedError})
 			}
@@ -109,6 +210,20 @@ func (r *IssuerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 	return result, returnedError
 }
 
+// isStatusSubresourceNotFound returns true if err is the NotFound error that
+// the API server returns for a status patch if the status subresource is not
+// enabled on the CRD. Unlike the NotFound error for an object that was
+// deleted, this error does not contain the name of the object.
+func isStatusSubresourceNotFound(err error) bool {
+	var statusErr apierrors.APIStatus
+	if !apierrors.IsNotFound(err) || !errors.As(err, &statusErr) {
+		return false
+	}
+
+	details := statusErr.Status().Details
+	return details == nil || details.Name == ""
+}
+
 // reconcileStatusPatch is responsible for reconciling the issuer. It will return the
 // result and reconcileError to be returned by the Reconcile function. It also returns
 // an issuerStatusPatch that the Reconcile function will apply to the issuer's status.
@@ -122,6 +237,13 @@ func (r *IssuerReconciler) reconcileStatusPatch(
 	ctx context.Context,
 	req ctrl.Request,
 ) (result ctrl.Result, issuerStatusPatch *v1alpha1.IssuerStatus, reconcileError error) {
+	if r.HTTPClientProvider != nil {
+		ctx = signer.ContextWithHTTPClient(ctx, r.HTTPClientProvider())
+	}
+	if r.APIReader != nil {
+		ctx = signer.ContextWithAPIReader(ctx, r.APIReader)
+	}
+
 	// Get the ClusterIssuer
 	issuer := r.ForObject.DeepCopyObject().(v1alpha1.Issuer)
 	forObjectGvk := r.ForObject.GetObjectKind().GroupVersionKind()
@@ -160,7 +282,13 @@ func (r *IssuerReconciler) reconcileStatusPatch(
 
 	// We now have a Issuer that belongs to us so we are responsible
 	// for updating its Status.
-	issuerStatusPatch = &v1alpha1.IssuerStatus{}
+	issuerStatusPatch = &v1alpha1.IssuerStatus{
+		// Keep the time of the last successful check and the error of the
+		// last failed check, the fields would be removed if they were omitted
+		// from the patch.
+		LastCheckTime:  issuer.GetStatus().LastCheckTime,
+		LastCheckError: issuer.GetStatus().LastCheckError,
+	}
 
 	setCondition := func(
 		conditionType cmapi.IssuerConditionType,
@@ -178,10 +306,18 @@ func (r *IssuerReconciler) reconcileStatusPatch(
 		return condition.Message
 	}
 
+	// The conditions that were reported by the last Check are omitted from
+	// the patch (and thus removed) if they are not set again. keepCheckConditions
+	// is used on the paths that don't call Check, to keep them unchanged.
+	keepCheckConditions := func() {
+		issuerStatusPatch.Conditions = append(issuerStatusPatch.Conditions, checkConditions(issuer.GetStatus().Conditions)...)
+	}
+
 	// Add a Ready condition if one does not already exist. Set initial Status
 	// to Unknown.
 	if readyCondition == nil {
 		logger.V(1).Info("Initializing Ready condition")
+		keepCheckConditions()
 		setCondition(
 			cmapi.IssuerConditionReady,
 			cmmeta.ConditionUnknown,
@@ -194,16 +330,89 @@ func (r *IssuerReconciler) reconcileStatusPatch(
 		return result, issuerStatusPatch, nil // apply patch, done
 	}
 
+	if r.OnIssuerBootstrap != nil && issuer.GetAnnotations()[v1alpha1.IssuerBootstrappedAnnotation] == "" {
+		if err := r.OnIssuerBootstrap(log.IntoContext(ctx, logger), issuer); err != nil {
+			logger.V(1).Error(err, "Issuer bootstrap error.")
+			keepCheckConditions()
+			message := setCondition(
+				cmapi.IssuerConditionReady,
+				cmmeta.ConditionFalse,
+				v1alpha1.IssuerConditionReasonPending,
+				fmt.Sprintf("Issuer is not ready yet, failed to bootstrap the issuer: %s", err),
+			)
+			r.EventRecorder.Event(issuer, corev1.EventTypeWarning, eventIssuerBootstrapError, message)
+			return result, issuerStatusPatch, err // apply patch, requeue with backoff
+		}
+
+		if _, err := applySignerAnnotations(ctx, r.Client, issuer, map[string]string{
+			v1alpha1.IssuerBootstrappedAnnotation: r.Clock.Now().UTC().Format(time.RFC3339),
+		}); err != nil {
+			return result, nil, fmt.Errorf("failed to record that the issuer was bootstrapped: %v", err) // requeue with backoff
+		}
+
+		logger.V(1).Info("Successfully bootstrapped the issuer.")
+		r.EventRecorder.Event(issuer, corev1.EventTypeNormal, eventIssuerBootstrapped, "Succeeded bootstrapping the issuer")
+
+		// The annotation update triggers a new reconcile (see IssuerPredicate),
+		// which checks the issuer. Keep the current conditions until then.
+		keepCheckConditions()
+		setCondition(
+			cmapi.IssuerConditionReady,
+			readyCondition.Status,
+			readyCondition.Reason,
+			readyCondition.Message,
+		)
+		return result, issuerStatusPatch, nil // apply patch, done
+	}
+
 	var err error
+	var requestedRequeueAfter func() time.Duration
 	if (readyCondition.Status == cmmeta.ConditionTrue) && (reportedError != nil) {
 		// We received an error from a Certificaterequest while our current status is Ready,
 		// update the ready state of the issuer to reflect the error.
+		keepCheckConditions()
 		err = reportedError
 	} else {
-		err = r.Check(log.IntoContext(ctx, logger), issuer)
+		checkCtx, reportedConditions := signer.ContextWithCheckConditions(log.IntoContext(ctx, logger))
+		checkCtx, requestedRequeueAfter = signer.ContextWithCheckRequeue(checkCtx)
+		err = r.Check(checkCtx, issuer)
+		var gatingConditions []signer.CheckCondition
+		for _, condition := range reportedConditions() {
+			if condition.Type == cmapi.IssuerConditionReady {
+				logger.V(1).Info("Ignoring a Ready condition reported by Check.")
+				continue
+			}
+
+			setCondition(condition.Type, condition.Status, condition.Reason, condition.Message)
+			gatingConditions = append(gatingConditions, condition)
+		}
+		if err == nil {
+			// Ready is the AND of the result of Check and the gating conditions.
+			err = gatingConditionsError(gatingConditions)
+		}
+		if r.MaxIssuerConditions > 0 {
+			// Keep room for the Ready condition, which is set below.
+			var pruned []cmapi.IssuerConditionType
+			issuerStatusPatch.Conditions, pruned = pruneIssuerConditions(issuerStatusPatch.Conditions, r.MaxIssuerConditions-1)
+			if len(pruned) > 0 {
+				logger.V(1).Info("Removed conditions reported by Check, because MaxIssuerConditions was exceeded.", "removed", pruned)
+			}
+		}
+		if err != nil {
+			// Store the full error, it is only cleared once the check
+			// succeeds.
+			issuerStatusPatch.LastCheckError = &v1alpha1.IssuerCheckError{
+				Message: err.Error(),
+				Time:    metav1.NewTime(r.Clock.Now()),
+			}
+		} else {
+			issuerStatusPatch.LastCheckError = nil
+		}
 	}
 	if err == nil {
 		logger.V(1).Info("Successfully finished the reconciliation.")
+		now := metav1.NewTime(r.Clock.Now())
+		issuerStatusPatch.LastCheckTime = &now
 		message := setCondition(
 			cmapi.IssuerConditionReady,
 			cmmeta.ConditionTrue,
@@ -211,12 +420,24 @@ func (r *IssuerReconciler) reconcileStatusPatch(
 			"Succeeded checking the issuer",
 		)
 		r.EventRecorder.Event(issuer, corev1.EventTypeNormal, eventIssuerChecked, message)
+		if readyCondition.Status == cmmeta.ConditionFalse {
+			// Only emit this event on the transition from not-Ready to Ready,
+			// so that alerts on the issuer can be resolved automatically.
+			r.EventRecorder.Eventf(issuer, corev1.EventTypeNormal, eventIssuerRecovered, "Issuer has recovered from %s and is Ready again", readyCondition.Reason)
+		}
+
+		if requestedRequeueAfter != nil {
+			if requeueAfter := requestedRequeueAfter(); requeueAfter > 0 {
+				logger.V(1).Info("Check requested the issuer to be checked again.", "requeueAfter", requeueAfter)
+				result.RequeueAfter = requeueAfter
+				return result, issuerStatusPatch, nil // apply patch, requeue after requested duration
+			}
+		}
 
 		return result, issuerStatusPatch, nil // apply patch, done
 	}
 
-	isPermanentError := errors.As(err, &signer.PermanentError{})
-	if isPermanentError {
+	if isPermanentError(err, r.IsPermanent) {
 		// fail permanently
 		logger.V(1).Error(err, "Permanent Issuer error. Marking as failed.")
 		message := setCondition(
@@ -241,11 +462,66 @@ func (r *IssuerReconciler) reconcileStatusPatch(
 	}
 }
 
+// checkConditions returns the conditions of an issuer that were reported by
+// Check, ie. all conditions except the Ready condition.
+func checkConditions(issuerConditions []cmapi.IssuerCondition) []cmapi.IssuerCondition {
+	var reported []cmapi.IssuerCondition
+	for _, condition := range issuerConditions {
+		if condition.Type == cmapi.IssuerConditionReady {
+			continue
+		}
+		reported = append(reported, condition)
+	}
+	return reported
+}
+
+// gatingConditionsError returns an error that lists the gating conditions
+// that don't have the True status, or nil if all of them are True. If a
+// condition type was reported more than once, only the last one is used.
+func gatingConditionsError(reported []signer.CheckCondition) error {
+	var types []cmapi.IssuerConditionType
+	last := map[cmapi.IssuerConditionType]signer.CheckCondition{}
+	for _, condition := range reported {
+		if _, ok := last[condition.Type]; !ok {
+			types = append(types, condition.Type)
+		}
+		last[condition.Type] = condition
+	}
+
+	var notReady []string
+	for _, conditionType := range types {
+		condition := last[conditionType]
+		if !condition.Gating || condition.Status == cmmeta.ConditionTrue {
+			continue
+		}
+
+		notReady = append(notReady, fmt.Sprintf("%s is %s (%s: %s)", condition.Type, condition.Status, condition.Reason, condition.Message))
+	}
+
+	if len(notReady) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("gating conditions are not True: %s", strings.Join(notReady, ", "))
+}
+
 // SetupWithManager sets up the controller with the Manager.
+// The EventSource is optional, if it is not set (eg. when the IssuerReconciler
+// is used without the CertificateRequest controllers), an empty event source is
+// created.
 func (r *IssuerReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if err := ValidateIssuerType(mgr.GetScheme(), r.ForObject); err != nil {
+		return err
+	}
+
 	if err := kubeutil.SetGroupVersionKind(mgr.GetScheme(), r.ForObject); err != nil {
 		return err
 	}
+
+	if r.EventSource == nil {
+		r.EventSource = kubeutil.NewEventStore()
+	}
+
 	forObjectGvk := r.ForObject.GetObjectKind().GroupVersionKind()
 
 	build := ctrl.NewControllerManagedBy(mgr).
@@ -265,6 +541,79 @@ func (r *IssuerReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manage
 			nil,
 		)
 
+	// We watch the ConfigMaps that the issuers reference. When a ConfigMap
+	// receives a watch event, we reconcile all the issuers that reference it.
+	if r.IssuerConfigMapRefs != nil {
+		// See the CertificateRequestReconciler for the defaulting of the timeout.
+		timeout := r.CacheSyncTimeout
+		if timeout == 0 {
+			timeout = mgr.GetControllerOptions().CacheSyncTimeout
+		}
+		if timeout == 0 {
+			timeout = 2 * time.Minute
+		}
+		cacheSyncCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		resourceHandler, err := kubeutil.NewLinkedResourceHandler(
+			cacheSyncCtx,
+			mgr.GetLogger(),
+			mgr.GetScheme(),
+			mgr.GetCache(),
+			r.ForObject,
+			func(rawObj client.Object) []string {
+				issuer, ok := rawObj.(v1alpha1.Issuer)
+				if !ok {
+					return nil
+				}
+
+				refs := r.IssuerConfigMapRefs(issuer)
+				ids := make([]string, 0, len(refs))
+				for _, ref := range refs {
+					ids = append(ids, fmt.Sprintf("%s/%s", ref.Namespace, ref.Name))
+				}
+				return ids
+			},
+			nil,
+		)
+		if err != nil {
+			return err
+		}
+
+		build = build.Watches(
+			&corev1.ConfigMap{},
+			resourceHandler,
+			builder.WithPredicates(
+				predicate.ResourceVersionChangedPredicate{},
+			),
+		)
+	}
+
+	if r.AuditResyncInterval > 0 {
+		logger := mgr.GetLogger()
+		if r.Logger.GetSink() != nil {
+			logger = r.Logger
+		}
+
+		auditBuild, err := setupAuditResync(mgr, build, logger, strings.ToLower(forObjectGvk.Kind), r.AuditResyncInterval, r.auditResyncObjects)
+		if err != nil {
+			return err
+		}
+		build = auditBuild
+	}
+
+	// The options are set before the PreSetupWithManager hook, so that the
+	// hook can override them.
+	build = build.WithOptions(controllerOptions(r.Logger, forObjectGvk, r.CacheSyncTimeout, r.MaxBackoff))
+
+	if r.PreSetupWithManager != nil {
+		err := r.PreSetupWithManager(ctx, forObjectGvk, mgr, build)
+		r.PreSetupWithManager = nil // free setup function
+		if err != nil {
+			return err
+		}
+	}
+
 	if controller, err := build.Build(r); err != nil {
 		return err
 	} else if r.PostSetupWithManager != nil {
@@ -274,3 +623,62 @@ func (r *IssuerReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manage
 	}
 	return nil
 }
+
+// pruneIssuerConditions removes the oldest conditions (by last transition
+// time), so that at most maxConditions conditions remain. Conditions with the
+// same last transition time are removed in the reverse order of their types.
+// The Ready condition is never removed. The order of the remaining conditions
+// is preserved. The types of the removed conditions are returned.
+func pruneIssuerConditions(
+	issuerConditions []cmapi.IssuerCondition,
+	maxConditions int,
+) ([]cmapi.IssuerCondition, []cmapi.IssuerConditionType) {
+	if len(issuerConditions) <= maxConditions {
+		return issuerConditions, nil
+	}
+
+	// Sort the indices of the conditions that can be removed from the oldest
+	// condition to the newest condition.
+	order := make([]int, 0, len(issuerConditions))
+	for i, condition := range issuerConditions {
+		if condition.Type == cmapi.IssuerConditionReady {
+			continue
+		}
+		order = append(order, i)
+	}
+	transitionTime := func(condition cmapi.IssuerCondition) time.Time {
+		if condition.LastTransitionTime == nil {
+			return time.Time{}
+		}
+		return condition.LastTransitionTime.Time
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		conditionA, conditionB := issuerConditions[order[a]], issuerConditions[order[b]]
+		timeA, timeB := transitionTime(conditionA), transitionTime(conditionB)
+		if !timeA.Equal(timeB) {
+			return timeA.Before(timeB)
+		}
+		return conditionA.Type > conditionB.Type
+	})
+
+	removeCount := len(issuerConditions) - maxConditions
+	if removeCount > len(order) {
+		removeCount = len(order)
+	}
+	removed := make(map[int]bool, removeCount)
+	for _, i := range order[:removeCount] {
+		removed[i] = true
+	}
+
+	kept := make([]cmapi.IssuerCondition, 0, len(issuerConditions)-removeCount)
+	var pruned []cmapi.IssuerConditionType
+	for i, condition := range issuerConditions {
+		if removed[i] {
+			pruned = append(pruned, condition.Type)
+			continue
+		}
+		kept = append(kept, condition)
+	}
+
+	return kept, pruned
+}
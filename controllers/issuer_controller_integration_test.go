@@ -0,0 +1,334 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/conditions"
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+	"github.com/cert-manager/issuer-lib/internal/kubeutil"
+	"github.com/cert-manager/issuer-lib/internal/ssaclient"
+	"github.com/cert-manager/issuer-lib/internal/tests/testcontext"
+	"github.com/cert-manager/issuer-lib/internal/tests/testresource"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/testutil"
+)
+
+// TestIssuerControllerIntegrationDisableForceApply runs the
+// IssuerReconciler against a real Kubernetes API server.
+func TestIssuerControllerIntegrationDisableForceApply(t *testing.T) {
+	t.Parallel()
+
+	t.Log(
+		"Tests to show that the IssuerReconciler overwrites status fields owned by another field manager by default",
+		"and that it returns a conflict error instead if DisableForceApply is set",
+	)
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	installCRDs(t, kubeClients)
+
+	type testCase struct {
+		name              string
+		disableForceApply bool
+		expectConflict    bool
+	}
+
+	tests := []testCase{
+		{
+			name:              "force-apply",
+			disableForceApply: false,
+			expectConflict:    false,
+		},
+		{
+			name:              "disable-force-apply",
+			disableForceApply: true,
+			expectConflict:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Logf("Creating a namespace")
+			namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+			defer cleanup()
+
+			issuer := testutil.SimpleIssuer(
+				"issuer-1",
+				testutil.SetSimpleIssuerNamespace(namespace),
+			)
+			require.NoError(t, kubeClients.Client.Create(ctx, issuer))
+
+			t.Log("Setting the Ready condition using another field manager")
+			issuerStatus := &v1alpha1.IssuerStatus{}
+			conditions.SetIssuerStatusCondition(
+				clock.RealClock{},
+				issuerStatus.Conditions,
+				&issuerStatus.Conditions,
+				issuer.GetGeneration(),
+				cmapi.IssuerConditionReady,
+				cmmeta.ConditionTrue,
+				v1alpha1.IssuerConditionReasonChecked,
+				"Set by another field manager",
+			)
+			issuerObj, patch, err := ssaclient.GenerateIssuerStatusPatch(issuer, issuer.Name, issuer.Namespace, issuerStatus)
+			require.NoError(t, err)
+			require.NoError(t, kubeClients.Client.Status().Patch(ctx, issuerObj, patch, &client.SubResourcePatchOptions{
+				PatchOptions: client.PatchOptions{
+					FieldManager: "other-field-manager",
+					Force:        ptr.To(true),
+				},
+			}))
+
+			forObject := &api.SimpleIssuer{}
+			require.NoError(t, kubeutil.SetGroupVersionKind(kubeClients.Scheme, forObject))
+
+			reconciler := &IssuerReconciler{
+				ForObject:   forObject,
+				FieldOwner:  "disable-force-apply",
+				EventSource: kubeutil.NewEventStore(),
+				Client:      kubeClients.Client,
+				Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+					return nil
+				},
+				EventRecorder:     record.NewFakeRecorder(100),
+				Clock:             clock.RealClock{},
+				DisableForceApply: tc.disableForceApply,
+			}
+
+			_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(issuer)})
+
+			var current api.SimpleIssuer
+			require.NoError(t, kubeClients.Client.Get(ctx, client.ObjectKeyFromObject(issuer), &current))
+			readyCondition := conditions.GetIssuerStatusCondition(current.Status.Conditions, cmapi.IssuerConditionReady)
+			require.NotNil(t, readyCondition)
+
+			if tc.expectConflict {
+				require.ErrorContains(t, err, "conflict with \"other-field-manager\"")
+				require.Equal(t, "Set by another field manager", readyCondition.Message)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "Succeeded checking the issuer", readyCondition.Message)
+			}
+		})
+	}
+}
+
+// TestIssuerControllerIntegrationIgnoreIssuer runs the IssuerReconciler
+// against a real Kubernetes API server.
+func TestIssuerControllerIntegrationIgnoreIssuer(t *testing.T) {
+	t.Parallel()
+
+	t.Log(
+		"Tests to show that the IssuerReconciler does not call Check and does not modify the status of an issuer",
+		"while IgnoreIssuer returns true, and that it reconciles the issuer normally once IgnoreIssuer returns false",
+	)
+
+	fieldOwner := "ignore-issuer"
+
+	ignoreIssuer := atomic.Bool{}
+	ignoreIssuer.Store(true)
+	checkCalls := atomic.Int32{}
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	ctx = setupControllersAPIServerAndClient(t, ctx, kubeClients,
+		func(mgr ctrl.Manager) controllerInterface {
+			return &IssuerReconciler{
+				ForObject:  &api.SimpleIssuer{},
+				FieldOwner: fieldOwner,
+				Client:     mgr.GetClient(),
+				Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+					checkCalls.Add(1)
+					return nil
+				},
+				IgnoreIssuer: func(_ context.Context, _ v1alpha1.Issuer) (bool, error) {
+					return ignoreIssuer.Load(), nil
+				},
+				EventRecorder: record.NewFakeRecorder(100),
+				Clock:         clock.RealClock{},
+			}
+		},
+	)
+
+	t.Logf("Creating a namespace")
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	t.Log("Creating the SimpleIssuer, while IgnoreIssuer returns true")
+	require.NoError(t, kubeClients.Client.Create(ctx, issuer))
+
+	t.Log("Checking that Check is not called and that the status is not modified")
+	require.Never(t, func() bool {
+		var current api.SimpleIssuer
+		require.NoError(t, kubeClients.Client.Get(ctx, client.ObjectKeyFromObject(issuer), &current))
+		return checkCalls.Load() > 0 || len(current.Status.Conditions) > 0
+	}, 2*time.Second, 100*time.Millisecond)
+
+	checkComplete := kubeClients.StartObjectWatch(t, ctx, issuer)
+
+	t.Log("Making IgnoreIssuer return false and triggering a reconcile by setting the Ready condition using another field manager")
+	ignoreIssuer.Store(false)
+	setIssuerReadyCondition(t, ctx, kubeClients.Client, clock.RealClock{}, "other-field-manager", issuer, cmmeta.ConditionUnknown, "Test", "Set by another field manager")
+
+	t.Log("Waiting for the SimpleIssuer to be Ready")
+	err := checkComplete(func(obj runtime.Object) error {
+		readyCondition := conditions.GetIssuerStatusCondition(obj.(*api.SimpleIssuer).Status.Conditions, cmapi.IssuerConditionReady)
+
+		if (readyCondition == nil) ||
+			(readyCondition.Status != cmmeta.ConditionTrue) ||
+			(readyCondition.Reason != v1alpha1.IssuerConditionReasonChecked) {
+			return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+	require.Positive(t, checkCalls.Load())
+}
+
+// TestIssuerControllerIntegrationConfigMapRefs runs the IssuerReconciler
+// against a real Kubernetes API server.
+func TestIssuerControllerIntegrationConfigMapRefs(t *testing.T) {
+	t.Parallel()
+
+	t.Log(
+		"Tests to show that the IssuerReconciler checks the issuer again when",
+		"one of the ConfigMaps returned by IssuerConfigMapRefs is updated",
+	)
+
+	fieldOwner := "issuer-configmap-refs"
+	configMapName := "issuer-config"
+	configConditionType := cmapi.IssuerConditionType("ConfigLoaded")
+
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.UnitTest)
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	ctx = setupControllersAPIServerAndClient(t, ctx, kubeClients,
+		func(mgr ctrl.Manager) controllerInterface {
+			cl := mgr.GetClient()
+
+			return &IssuerReconciler{
+				ForObject:  &api.SimpleIssuer{},
+				FieldOwner: fieldOwner,
+				Client:     cl,
+				Check: func(ctx context.Context, issuer v1alpha1.Issuer) error {
+					var configMap corev1.ConfigMap
+					if err := cl.Get(ctx, types.NamespacedName{
+						Namespace: issuer.GetNamespace(),
+						Name:      configMapName,
+					}, &configMap); err != nil {
+						return err
+					}
+
+					signer.ReportCheckConditions(ctx, signer.CheckCondition{
+						Type:    configConditionType,
+						Status:  cmmeta.ConditionTrue,
+						Reason:  "Loaded",
+						Message: configMap.Data["version"],
+					})
+					return nil
+				},
+				IssuerConfigMapRefs: func(issuer v1alpha1.Issuer) []types.NamespacedName {
+					return []types.NamespacedName{
+						{Namespace: issuer.GetNamespace(), Name: configMapName},
+					}
+				},
+				EventRecorder: record.NewFakeRecorder(100),
+				Clock:         clock.RealClock{},
+			}
+		},
+	)
+
+	t.Logf("Creating a namespace")
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName,
+			Namespace: namespace,
+		},
+		Data: map[string]string{"version": "1"},
+	}
+	require.NoError(t, kubeClients.Client.Create(ctx, configMap))
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	waitForConfigVersion := func(version string) {
+		checkComplete := kubeClients.StartObjectWatch(t, ctx, issuer)
+		err := checkComplete(func(obj runtime.Object) error {
+			issuerConditions := obj.(*api.SimpleIssuer).Status.Conditions
+
+			readyCondition := conditions.GetIssuerStatusCondition(issuerConditions, cmapi.IssuerConditionReady)
+			if (readyCondition == nil) ||
+				(readyCondition.Status != cmmeta.ConditionTrue) ||
+				(readyCondition.Reason != v1alpha1.IssuerConditionReasonChecked) {
+				return fmt.Errorf("incorrect ready condition: %v", readyCondition)
+			}
+
+			configCondition := conditions.GetIssuerStatusCondition(issuerConditions, configConditionType)
+			if (configCondition == nil) ||
+				(configCondition.Message != version) {
+				return fmt.Errorf("incorrect config condition: %v", configCondition)
+			}
+
+			return nil
+		}, watch.Added, watch.Modified)
+		require.NoError(t, err)
+	}
+
+	t.Log("Creating the SimpleIssuer and waiting for it to be checked with the initial ConfigMap")
+	require.NoError(t, kubeClients.Client.Create(ctx, issuer))
+	waitForConfigVersion("1")
+
+	t.Log("Updating the ConfigMap and waiting for the SimpleIssuer to be checked again")
+	configMap.Data["version"] = "2"
+	require.NoError(t, kubeClients.Client.Update(ctx, configMap))
+	waitForConfigVersion("2")
+}
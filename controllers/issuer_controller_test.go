@@ -28,20 +28,28 @@ import (
 	logrtesting "github.com/go-logr/logr/testing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	clocktesting "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	controllerpkg "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/conditions"
 	"github.com/cert-manager/issuer-lib/controllers/signer"
+	"github.com/cert-manager/issuer-lib/internal/kubeutil"
 	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
 	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
 	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/testutil"
@@ -75,6 +83,9 @@ func TestSimpleIssuerReconcilerReconcile(t *testing.T) {
 		expectedResult      reconcile.Result
 		expectedStatusPatch *v1alpha1.IssuerStatus
 		expectedEvents      []string
+		maxConditions       int
+		ignoreIssuer        signer.IgnoreIssuer
+		onIssuerBootstrap   func(ctx context.Context, issuerObject v1alpha1.Issuer) error
 	}
 
 	randTime := randomTime()
@@ -107,6 +118,60 @@ func TestSimpleIssuerReconcilerReconcile(t *testing.T) {
 			expectedStatusPatch: nil,
 		},
 
+		// Ignore the issuer if it is owned by a different controller.
+		{
+			name:         "ignore-issuer-owned-by-other-controller",
+			check:        staticChecker(nil),
+			ignoreIssuer: signer.IssuerOwnedBy("example.com/controller", "blue"),
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					func(si *api.SimpleIssuer) {
+						si.Labels = map[string]string{"example.com/controller": "green"}
+					},
+				),
+			},
+			expectedStatusPatch: nil,
+		},
+
+		// Reconcile the issuer if it is owned by this controller.
+		{
+			name:         "reconcile-issuer-owned-by-controller",
+			check:        staticChecker(nil),
+			ignoreIssuer: signer.IssuerOwnedBy("example.com/controller", "blue"),
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionTrue,
+						v1alpha1.IssuerConditionReasonChecked,
+						"Succeeded checking the issuer",
+					),
+					func(si *api.SimpleIssuer) {
+						si.Labels = map[string]string{"example.com/controller": "blue"}
+					},
+				),
+			},
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.IssuerConditionReasonChecked,
+						Message:            "Succeeded checking the issuer",
+						ObservedGeneration: 80,
+						LastTransitionTime: &fakeTimeObj1,
+					},
+				},
+				LastCheckTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Normal Checked Succeeded checking the issuer",
+			},
+		},
+
 		// Update status, even if already at Ready for observed generation
 		{
 			name:  "trigger-when-ready",
@@ -134,6 +199,276 @@ func TestSimpleIssuerReconcilerReconcile(t *testing.T) {
 						LastTransitionTime: &fakeTimeObj1, // since the status is not updated, the LastTransitionTime is not updated either
 					},
 				},
+				LastCheckTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Normal Checked Succeeded checking the issuer",
+			},
+		},
+
+		// Set the additional conditions reported by Check, both if it succeeds and
+		// if it fails. A reported Ready condition is ignored.
+		{
+			name: "check-reports-conditions",
+			check: func(ctx context.Context, _ v1alpha1.Issuer) error {
+				signer.ReportCheckConditions(ctx,
+					signer.CheckCondition{Type: "EndpointReachable", Status: cmmeta.ConditionTrue, Reason: "Reachable", Message: "The CA endpoint is reachable"},
+					signer.CheckCondition{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionFalse, Reason: "Ignored", Message: "ignored"},
+				)
+				signer.ReportCheckConditions(ctx,
+					signer.CheckCondition{Type: "AuthValid", Status: cmmeta.ConditionFalse, Reason: "Expired", Message: "The credentials have expired"},
+				)
+				return fmt.Errorf("[specific error]")
+			},
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionTrue,
+						v1alpha1.IssuerConditionReasonChecked,
+						"Succeeded checking the issuer",
+					),
+				),
+			},
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               "EndpointReachable",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "Reachable",
+						Message:            "The CA endpoint is reachable",
+						LastTransitionTime: &fakeTimeObj2,
+						ObservedGeneration: 80,
+					},
+					{
+						Type:               "AuthValid",
+						Status:             cmmeta.ConditionFalse,
+						Reason:             "Expired",
+						Message:            "The credentials have expired",
+						LastTransitionTime: &fakeTimeObj2,
+						ObservedGeneration: 80,
+					},
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.IssuerConditionReasonPending,
+						Message:            "Issuer is not ready yet: [specific error]",
+						LastTransitionTime: &fakeTimeObj2,
+						ObservedGeneration: 80,
+					},
+				},
+				LastCheckError: &v1alpha1.IssuerCheckError{
+					Message: "[specific error]",
+					Time:    fakeTimeObj2,
+				},
+			},
+			validateError: errormatch.ErrorContains("[specific error]"),
+			expectedEvents: []string{
+				"Warning RetryableError Issuer is not ready yet: [specific error]",
+			},
+		},
+
+		{
+			name: "check-requests-requeue",
+			check: func(ctx context.Context, _ v1alpha1.Issuer) error {
+				signer.RequeueCheckAfter(ctx, 2*time.Hour)
+				signer.RequeueCheckAfter(ctx, 30*time.Minute)
+				return nil
+			},
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionTrue,
+						v1alpha1.IssuerConditionReasonChecked,
+						"Succeeded checking the issuer",
+					),
+				),
+			},
+			expectedResult: reconcile.Result{RequeueAfter: 30 * time.Minute},
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.IssuerConditionReasonChecked,
+						Message:            "Succeeded checking the issuer",
+						LastTransitionTime: &fakeTimeObj1,
+						ObservedGeneration: 80,
+					},
+				},
+				LastCheckTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Normal Checked Succeeded checking the issuer",
+			},
+		},
+
+		{
+			name: "check-requests-requeue-error",
+			check: func(ctx context.Context, _ v1alpha1.Issuer) error {
+				signer.RequeueCheckAfter(ctx, 30*time.Minute)
+				return fmt.Errorf("[specific error]")
+			},
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionTrue,
+						v1alpha1.IssuerConditionReasonChecked,
+						"Succeeded checking the issuer",
+					),
+				),
+			},
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.IssuerConditionReasonPending,
+						Message:            "Issuer is not ready yet: [specific error]",
+						LastTransitionTime: &fakeTimeObj2,
+						ObservedGeneration: 80,
+					},
+				},
+				LastCheckError: &v1alpha1.IssuerCheckError{
+					Message: "[specific error]",
+					Time:    fakeTimeObj2,
+				},
+			},
+			validateError: errormatch.ErrorContains("[specific error]"),
+			expectedEvents: []string{
+				"Warning RetryableError Issuer is not ready yet: [specific error]",
+			},
+		},
+
+		// Ready is the AND of the gating conditions: if one of the dependencies
+		// is not True, the issuer is not Ready even though Check succeeded.
+		{
+			name: "check-reports-gating-condition-not-true",
+			check: func(ctx context.Context, _ v1alpha1.Issuer) error {
+				signer.ReportCheckConditions(ctx,
+					signer.CheckCondition{Type: "CAReachable", Status: cmmeta.ConditionTrue, Reason: "Reachable", Message: "The CA is reachable", Gating: true},
+					signer.CheckCondition{Type: "LicenseValid", Status: cmmeta.ConditionFalse, Reason: "Expired", Message: "The license has expired", Gating: true},
+					signer.CheckCondition{Type: "QuotaLow", Status: cmmeta.ConditionFalse, Reason: "Plenty", Message: "Not gating"},
+				)
+				return nil
+			},
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionTrue,
+						v1alpha1.IssuerConditionReasonChecked,
+						"Succeeded checking the issuer",
+					),
+				),
+			},
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               "CAReachable",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "Reachable",
+						Message:            "The CA is reachable",
+						LastTransitionTime: &fakeTimeObj2,
+						ObservedGeneration: 80,
+					},
+					{
+						Type:               "LicenseValid",
+						Status:             cmmeta.ConditionFalse,
+						Reason:             "Expired",
+						Message:            "The license has expired",
+						LastTransitionTime: &fakeTimeObj2,
+						ObservedGeneration: 80,
+					},
+					{
+						Type:               "QuotaLow",
+						Status:             cmmeta.ConditionFalse,
+						Reason:             "Plenty",
+						Message:            "Not gating",
+						LastTransitionTime: &fakeTimeObj2,
+						ObservedGeneration: 80,
+					},
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.IssuerConditionReasonPending,
+						Message:            "Issuer is not ready yet: gating conditions are not True: LicenseValid is False (Expired: The license has expired)",
+						LastTransitionTime: &fakeTimeObj2,
+						ObservedGeneration: 80,
+					},
+				},
+				LastCheckError: &v1alpha1.IssuerCheckError{
+					Message: "gating conditions are not True: LicenseValid is False (Expired: The license has expired)",
+					Time:    fakeTimeObj2,
+				},
+			},
+			validateError: errormatch.ErrorContains("gating conditions are not True"),
+			expectedEvents: []string{
+				"Warning RetryableError Issuer is not ready yet: gating conditions are not True: LicenseValid is False (Expired: The license has expired)",
+			},
+		},
+
+		// Remove the oldest conditions if Check reports more conditions than
+		// MaxIssuerConditions allows, but keep the Ready condition.
+		{
+			name: "check-reports-conditions-pruned-oldest",
+			check: func(ctx context.Context, _ v1alpha1.Issuer) error {
+				signer.ReportCheckConditions(ctx,
+					signer.CheckCondition{Type: "EndpointReachable", Status: cmmeta.ConditionTrue, Reason: "Reachable", Message: "The CA endpoint is reachable"},
+					signer.CheckCondition{Type: "AuthValid", Status: cmmeta.ConditionFalse, Reason: "Expired", Message: "The credentials have expired"},
+				)
+				return nil
+			},
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionTrue,
+						v1alpha1.IssuerConditionReasonChecked,
+						"Succeeded checking the issuer",
+					),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						"EndpointReachable",
+						cmmeta.ConditionTrue,
+						"Reachable",
+						"The CA endpoint is reachable",
+					),
+				),
+			},
+			maxConditions: 2,
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               "AuthValid",
+						Status:             cmmeta.ConditionFalse,
+						Reason:             "Expired",
+						Message:            "The credentials have expired",
+						LastTransitionTime: &fakeTimeObj2,
+						ObservedGeneration: 80,
+					},
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.IssuerConditionReasonChecked,
+						Message:            "Succeeded checking the issuer",
+						LastTransitionTime: &fakeTimeObj1,
+						ObservedGeneration: 80,
+					},
+				},
+				LastCheckTime: &fakeTimeObj2,
 			},
 			expectedEvents: []string{
 				"Normal Checked Succeeded checking the issuer",
@@ -156,36 +491,179 @@ func TestSimpleIssuerReconcilerReconcile(t *testing.T) {
 					),
 				),
 			},
-			expectedStatusPatch: nil,
+			expectedStatusPatch: nil,
+		},
+
+		// Ignore reported error if not ready
+		{
+			name:  "failed-ignore-reported-error",
+			check: staticChecker(nil),
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionFalse,
+						v1alpha1.IssuerConditionReasonFailed,
+						"[error message]",
+					),
+				),
+			},
+			eventSourceError:    fmt.Errorf("[specific error]"),
+			expectedStatusPatch: nil,
+		},
+
+		// Set error if the CertificateRequest controller reported error
+		{
+			name:  "ready-reported-error",
+			check: staticChecker(nil),
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionTrue,
+						v1alpha1.IssuerConditionReasonChecked,
+						"Succeeded checking the issuer",
+					),
+				),
+			},
+			eventSourceError: fmt.Errorf("[specific error]"),
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.IssuerConditionReasonPending,
+						Message:            "Issuer is not ready yet: [specific error]",
+						ObservedGeneration: 80,
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			validateError: errormatch.ErrorContains("[specific error]"),
+			expectedEvents: []string{
+				"Warning RetryableError Issuer is not ready yet: [specific error]",
+			},
+		},
+
+		// Keep the LastCheckError if the CertificateRequest controller
+		// reported an error, the issuer is not checked on that path.
+		{
+			name:  "ready-reported-error-keeps-last-check-error",
+			check: staticChecker(nil),
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionTrue,
+						v1alpha1.IssuerConditionReasonChecked,
+						"Succeeded checking the issuer",
+					),
+					func(si *api.SimpleIssuer) {
+						si.Status.LastCheckError = &v1alpha1.IssuerCheckError{
+							Message: "[check error]",
+							Time:    fakeTimeObj1,
+						}
+					},
+				),
+			},
+			eventSourceError: fmt.Errorf("[specific error]"),
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.IssuerConditionReasonPending,
+						Message:            "Issuer is not ready yet: [specific error]",
+						ObservedGeneration: 80,
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				LastCheckError: &v1alpha1.IssuerCheckError{
+					Message: "[check error]",
+					Time:    fakeTimeObj1,
+				},
+			},
+			validateError: errormatch.ErrorContains("[specific error]"),
+			expectedEvents: []string{
+				"Warning RetryableError Issuer is not ready yet: [specific error]",
+			},
+		},
+
+		// Keep the conditions reported by the last Check if the
+		// CertificateRequest controller reported an error.
+		{
+			name:  "ready-reported-error-keeps-check-conditions",
+			check: staticChecker(nil),
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						"EndpointReachable",
+						cmmeta.ConditionTrue,
+						"Reachable",
+						"The CA endpoint is reachable",
+					),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionTrue,
+						v1alpha1.IssuerConditionReasonChecked,
+						"Succeeded checking the issuer",
+					),
+				),
+			},
+			eventSourceError: fmt.Errorf("[specific error]"),
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               "EndpointReachable",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "Reachable",
+						Message:            "The CA endpoint is reachable",
+						ObservedGeneration: 80,
+						LastTransitionTime: &fakeTimeObj1,
+					},
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.IssuerConditionReasonPending,
+						Message:            "Issuer is not ready yet: [specific error]",
+						ObservedGeneration: 80,
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+			},
+			validateError: errormatch.ErrorContains("[specific error]"),
+			expectedEvents: []string{
+				"Warning RetryableError Issuer is not ready yet: [specific error]",
+			},
 		},
 
-		// Ignore reported error if not ready
+		// Keep the conditions reported by the last Check if the issuer
+		// could not be bootstrapped.
 		{
-			name:  "failed-ignore-reported-error",
+			name:  "bootstrap-error-keeps-check-conditions",
 			check: staticChecker(nil),
+			onIssuerBootstrap: func(_ context.Context, _ v1alpha1.Issuer) error {
+				return fmt.Errorf("[bootstrap error]")
+			},
 			objects: []client.Object{
 				testutil.SimpleIssuerFrom(issuer1,
 					testutil.SetSimpleIssuerGeneration(80),
 					testutil.SetSimpleIssuerStatusCondition(
 						fakeClock1,
-						cmapi.IssuerConditionReady,
-						cmmeta.ConditionFalse,
-						v1alpha1.IssuerConditionReasonFailed,
-						"[error message]",
+						"EndpointReachable",
+						cmmeta.ConditionTrue,
+						"Reachable",
+						"The CA endpoint is reachable",
 					),
-				),
-			},
-			eventSourceError:    fmt.Errorf("[specific error]"),
-			expectedStatusPatch: nil,
-		},
-
-		// Set error if the CertificateRequest controller reported error
-		{
-			name:  "ready-reported-error",
-			check: staticChecker(nil),
-			objects: []client.Object{
-				testutil.SimpleIssuerFrom(issuer1,
-					testutil.SetSimpleIssuerGeneration(80),
 					testutil.SetSimpleIssuerStatusCondition(
 						fakeClock1,
 						cmapi.IssuerConditionReady,
@@ -195,22 +673,29 @@ func TestSimpleIssuerReconcilerReconcile(t *testing.T) {
 					),
 				),
 			},
-			eventSourceError: fmt.Errorf("[specific error]"),
 			expectedStatusPatch: &v1alpha1.IssuerStatus{
 				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               "EndpointReachable",
+						Status:             cmmeta.ConditionTrue,
+						Reason:             "Reachable",
+						Message:            "The CA endpoint is reachable",
+						ObservedGeneration: 80,
+						LastTransitionTime: &fakeTimeObj1,
+					},
 					{
 						Type:               cmapi.IssuerConditionReady,
 						Status:             cmmeta.ConditionFalse,
 						Reason:             v1alpha1.IssuerConditionReasonPending,
-						Message:            "Issuer is not ready yet: [specific error]",
+						Message:            "Issuer is not ready yet, failed to bootstrap the issuer: [bootstrap error]",
 						ObservedGeneration: 80,
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
 			},
-			validateError: errormatch.ErrorContains("[specific error]"),
+			validateError: errormatch.ErrorContains("[bootstrap error]"),
 			expectedEvents: []string{
-				"Warning RetryableError Issuer is not ready yet: [specific error]",
+				"Warning BootstrapError Issuer is not ready yet, failed to bootstrap the issuer: [bootstrap error]",
 			},
 		},
 
@@ -242,6 +727,7 @@ func TestSimpleIssuerReconcilerReconcile(t *testing.T) {
 						ObservedGeneration: 81,
 					},
 				},
+				LastCheckTime: &fakeTimeObj2,
 			},
 			expectedEvents: []string{
 				"Normal Checked Succeeded checking the issuer",
@@ -292,6 +778,10 @@ func TestSimpleIssuerReconcilerReconcile(t *testing.T) {
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
+				LastCheckError: &v1alpha1.IssuerCheckError{
+					Message: "[specific error]",
+					Time:    fakeTimeObj2,
+				},
 			},
 			validateError: errormatch.ErrorContains("[specific error]"),
 			expectedEvents: []string{
@@ -324,6 +814,10 @@ func TestSimpleIssuerReconcilerReconcile(t *testing.T) {
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
+				LastCheckError: &v1alpha1.IssuerCheckError{
+					Message: "[specific error]",
+					Time:    fakeTimeObj2,
+				},
 			},
 			validateError: errormatch.ErrorContains("terminal error: [specific error]"),
 			expectedEvents: []string{
@@ -359,12 +853,132 @@ func TestSimpleIssuerReconcilerReconcile(t *testing.T) {
 						LastTransitionTime: &fakeTimeObj2,
 					},
 				},
+				LastCheckTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Normal Checked Succeeded checking the issuer",
+			},
+		},
+
+		// Clear the LastCheckError once the check function succeeds
+		{
+			name:  "success-clears-last-check-error",
+			check: staticChecker(nil),
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionFalse,
+						v1alpha1.IssuerConditionReasonPending,
+						"Issuer is not ready yet: [specific error]",
+					),
+					func(si *api.SimpleIssuer) {
+						si.Status.LastCheckError = &v1alpha1.IssuerCheckError{
+							Message: "[specific error]",
+							Time:    fakeTimeObj1,
+						}
+					},
+				),
+			},
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.IssuerConditionReasonChecked,
+						Message:            "Succeeded checking the issuer",
+						LastTransitionTime: &fakeTimeObj2,
+					},
+				},
+				LastCheckTime: &fakeTimeObj2,
+			},
+			expectedEvents: []string{
+				"Normal Checked Succeeded checking the issuer",
+				"Normal Recovered Issuer has recovered from Pending and is Ready again",
+			},
+		},
+
+		// Advance the LastCheckTime when the check function succeeds.
+		{
+			name:  "success-advances-last-check-time",
+			check: staticChecker(nil),
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionTrue,
+						v1alpha1.IssuerConditionReasonChecked,
+						"Succeeded checking the issuer",
+					),
+					func(si *api.SimpleIssuer) {
+						si.Status.LastCheckTime = &fakeTimeObj1
+					},
+				),
+			},
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionTrue,
+						Reason:             v1alpha1.IssuerConditionReasonChecked,
+						Message:            "Succeeded checking the issuer",
+						LastTransitionTime: &fakeTimeObj1,
+						ObservedGeneration: 80,
+					},
+				},
+				LastCheckTime: &fakeTimeObj2,
 			},
 			expectedEvents: []string{
 				"Normal Checked Succeeded checking the issuer",
 			},
 		},
 
+		// Keep the LastCheckTime of the last successful check when the check
+		// function fails.
+		{
+			name:  "error-keeps-last-check-time",
+			check: staticChecker(fmt.Errorf("[specific error]")),
+			objects: []client.Object{
+				testutil.SimpleIssuerFrom(issuer1,
+					testutil.SetSimpleIssuerGeneration(80),
+					testutil.SetSimpleIssuerStatusCondition(
+						fakeClock1,
+						cmapi.IssuerConditionReady,
+						cmmeta.ConditionTrue,
+						v1alpha1.IssuerConditionReasonChecked,
+						"Succeeded checking the issuer",
+					),
+					func(si *api.SimpleIssuer) {
+						si.Status.LastCheckTime = &fakeTimeObj1
+					},
+				),
+			},
+			expectedStatusPatch: &v1alpha1.IssuerStatus{
+				Conditions: []cmapi.IssuerCondition{
+					{
+						Type:               cmapi.IssuerConditionReady,
+						Status:             cmmeta.ConditionFalse,
+						Reason:             v1alpha1.IssuerConditionReasonPending,
+						Message:            "Issuer is not ready yet: [specific error]",
+						LastTransitionTime: &fakeTimeObj2,
+						ObservedGeneration: 80,
+					},
+				},
+				LastCheckError: &v1alpha1.IssuerCheckError{
+					Message: "[specific error]",
+					Time:    fakeTimeObj2,
+				},
+				LastCheckTime: &fakeTimeObj1,
+			},
+			validateError: errormatch.ErrorContains("[specific error]"),
+			expectedEvents: []string{
+				"Warning RetryableError Issuer is not ready yet: [specific error]",
+			},
+		},
+
 		// Set the Ready condition to Ready if the check function returned a permanent error on a previous version
 		{
 			name:  "success-recover",
@@ -393,9 +1007,11 @@ func TestSimpleIssuerReconcilerReconcile(t *testing.T) {
 						ObservedGeneration: 81,
 					},
 				},
+				LastCheckTime: &fakeTimeObj2,
 			},
 			expectedEvents: []string{
 				"Normal Checked Succeeded checking the issuer",
+				"Normal Recovered Issuer has recovered from Initializing and is Ready again",
 			},
 		},
 	}
@@ -436,6 +1052,11 @@ func TestSimpleIssuerReconcilerReconcile(t *testing.T) {
 				Check:         tc.check,
 				EventRecorder: fakeRecorder,
 				Clock:         fakeClock2,
+
+				IgnoreIssuer:      tc.ignoreIssuer,
+				OnIssuerBootstrap: tc.onIssuerBootstrap,
+
+				MaxIssuerConditions: tc.maxConditions,
 			}
 
 			res, issuerStatusPatch, reconcileErr := controller.reconcileStatusPatch(logger, context.TODO(), req)
@@ -468,3 +1089,377 @@ func (fakeEventSource) ReportError(gvk schema.GroupVersionKind, namespacedName t
 func (fes fakeEventSource) HasReportedError(gvk schema.GroupVersionKind, namespacedName types.NamespacedName) error {
 	return fes.err
 }
+
+func TestIssuerReconcilerSetupWithManagerHooks(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+
+	// The manager is never started, so it doesn't connect to the API server.
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:1"}, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+	})
+	require.NoError(t, err)
+
+	var calls []string
+	controller := IssuerReconciler{
+		ForObject:  &api.SimpleIssuer{},
+		FieldOwner: "test-issuer-reconciler-setup-hooks",
+		PreSetupWithManager: func(_ context.Context, gvk schema.GroupVersionKind, _ ctrl.Manager, b *builder.Builder) error {
+			calls = append(calls, "pre:"+gvk.Kind)
+			b.WithOptions(controllerpkg.Options{MaxConcurrentReconciles: 3})
+			return nil
+		},
+		PostSetupWithManager: func(_ context.Context, gvk schema.GroupVersionKind, _ ctrl.Manager, _ controllerpkg.Controller) error {
+			calls = append(calls, "post:"+gvk.Kind)
+			return nil
+		},
+	}
+
+	require.NoError(t, controller.SetupWithManager(context.TODO(), mgr))
+	assert.Equal(t, []string{"pre:SimpleIssuer", "post:SimpleIssuer"}, calls)
+	assert.Nil(t, controller.PreSetupWithManager)
+	assert.Nil(t, controller.PostSetupWithManager)
+
+	t.Run("pre-setup-error", func(t *testing.T) {
+		controller := IssuerReconciler{
+			ForObject:  &api.SimpleClusterIssuer{},
+			FieldOwner: "test-issuer-reconciler-setup-hooks",
+			PreSetupWithManager: func(_ context.Context, _ schema.GroupVersionKind, _ ctrl.Manager, _ *builder.Builder) error {
+				return fmt.Errorf("[pre-setup error]")
+			},
+		}
+
+		err := controller.SetupWithManager(context.TODO(), mgr)
+		(*errormatch.ErrorContains("[pre-setup error]"))(t, err)
+	})
+}
+
+// TestIssuerReconcilerKeepsLastTransitionTime verifies that reconciling an
+// issuer whose Ready condition does not change, does not update the
+// LastTransitionTime of that condition.
+func TestIssuerReconcilerKeepsLastTransitionTime(t *testing.T) {
+	t.Parallel()
+
+	randTime := randomTime()
+
+	fakeTime1 := randTime.Truncate(time.Second)
+	fakeTimeObj1 := metav1.NewTime(fakeTime1)
+	fakeClock1 := clocktesting.NewFakeClock(fakeTime1)
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace("ns1"),
+		testutil.SetSimpleIssuerGeneration(80),
+		testutil.SetSimpleIssuerStatusCondition(
+			fakeClock1,
+			cmapi.IssuerConditionReady,
+			cmmeta.ConditionTrue,
+			v1alpha1.IssuerConditionReasonChecked,
+			"Succeeded checking the issuer",
+		),
+	)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(issuer).
+		Build()
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(issuer)}
+	logger := logrtesting.NewTestLoggerWithOptions(t, logrtesting.Options{LogTimestamp: true, Verbosity: 10})
+	fakeClock := clocktesting.NewFakeClock(fakeTime1)
+
+	controller := IssuerReconciler{
+		ForObject:   &api.SimpleIssuer{},
+		FieldOwner:  "test-issuer-reconciler-keeps-last-transition-time",
+		EventSource: fakeEventSource{},
+		Client:      fakeClient,
+		Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+			return nil
+		},
+		EventRecorder: record.NewFakeRecorder(100),
+		Clock:         fakeClock,
+	}
+
+	for i := 0; i < 3; i++ {
+		fakeClock.Step(time.Hour)
+
+		_, issuerStatusPatch, err := controller.reconcileStatusPatch(logger, context.TODO(), req)
+		require.NoError(t, err)
+		require.NotNil(t, issuerStatusPatch)
+
+		readyCondition := conditions.GetIssuerStatusCondition(issuerStatusPatch.Conditions, cmapi.IssuerConditionReady)
+		require.NotNil(t, readyCondition)
+		assert.Equal(t, cmmeta.ConditionTrue, readyCondition.Status)
+		assert.Equal(t, &fakeTimeObj1, readyCondition.LastTransitionTime, "reconcile %d updated the LastTransitionTime", i)
+
+		// Store the patched status, like the status patch would.
+		var current api.SimpleIssuer
+		require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, &current))
+		current.Status = *issuerStatusPatch
+		require.NoError(t, fakeClient.Update(context.TODO(), &current))
+	}
+}
+
+// TestIssuerReconcilerPrunesOldestConditions verifies that a new condition
+// reported by Check replaces the oldest condition once MaxIssuerConditions is
+// reached, and that the Ready condition is never removed.
+func TestIssuerReconcilerPrunesOldestConditions(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := clocktesting.NewFakeClock(randomTime().Truncate(time.Second))
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace("ns1"),
+		testutil.SetSimpleIssuerGeneration(80),
+		testutil.SetSimpleIssuerStatusCondition(
+			fakeClock,
+			cmapi.IssuerConditionReady,
+			cmmeta.ConditionUnknown,
+			v1alpha1.IssuerConditionReasonInitializing,
+			"test has started reconciling this Issuer",
+		),
+	)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(issuer).
+		Build()
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(issuer)}
+	logger := logrtesting.NewTestLoggerWithOptions(t, logrtesting.Options{LogTimestamp: true, Verbosity: 10})
+
+	var reportedConditions []signer.CheckCondition
+	controller := IssuerReconciler{
+		ForObject:   &api.SimpleIssuer{},
+		FieldOwner:  "test-issuer-reconciler-pruned-conditions",
+		EventSource: fakeEventSource{},
+		Client:      fakeClient,
+		Check: func(ctx context.Context, _ v1alpha1.Issuer) error {
+			signer.ReportCheckConditions(ctx, reportedConditions...)
+			return nil
+		},
+		EventRecorder:       record.NewFakeRecorder(100),
+		Clock:               fakeClock,
+		MaxIssuerConditions: 3,
+	}
+
+	reconcileConditionTypes := func(conditions ...signer.CheckCondition) []cmapi.IssuerConditionType {
+		fakeClock.Step(time.Hour)
+		reportedConditions = conditions
+
+		_, issuerStatusPatch, err := controller.reconcileStatusPatch(logger, context.TODO(), req)
+		require.NoError(t, err)
+		require.NotNil(t, issuerStatusPatch)
+
+		// Store the patched status, like the status patch would.
+		var current api.SimpleIssuer
+		require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, &current))
+		current.Status = *issuerStatusPatch
+		require.NoError(t, fakeClient.Update(context.TODO(), &current))
+
+		conditionTypes := make([]cmapi.IssuerConditionType, 0, len(issuerStatusPatch.Conditions))
+		for _, condition := range issuerStatusPatch.Conditions {
+			conditionTypes = append(conditionTypes, condition.Type)
+		}
+		return conditionTypes
+	}
+
+	endpointReachable := signer.CheckCondition{Type: "EndpointReachable", Status: cmmeta.ConditionTrue, Reason: "Reachable", Message: "The CA endpoint is reachable"}
+	authValid := signer.CheckCondition{Type: "AuthValid", Status: cmmeta.ConditionTrue, Reason: "Valid", Message: "The credentials are valid"}
+	licenseValid := signer.CheckCondition{Type: "LicenseValid", Status: cmmeta.ConditionTrue, Reason: "Valid", Message: "The license is valid"}
+
+	assert.Equal(t,
+		[]cmapi.IssuerConditionType{"EndpointReachable", cmapi.IssuerConditionReady},
+		reconcileConditionTypes(endpointReachable),
+	)
+	assert.Equal(t,
+		[]cmapi.IssuerConditionType{"EndpointReachable", "AuthValid", cmapi.IssuerConditionReady},
+		reconcileConditionTypes(endpointReachable, authValid),
+	)
+	// The new LicenseValid condition replaces the oldest condition, the Ready
+	// condition is kept although it is as old as the removed condition.
+	assert.Equal(t,
+		[]cmapi.IssuerConditionType{"AuthValid", "LicenseValid", cmapi.IssuerConditionReady},
+		reconcileConditionTypes(endpointReachable, authValid, licenseValid),
+	)
+}
+
+// TestIssuerReconcilerBootstrap verifies that the OnIssuerBootstrap function
+// is retried until it succeeds, that the issuer is not Ready and not checked
+// before that, and that it is not called again once it has succeeded.
+func TestIssuerReconcilerBootstrap(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := clocktesting.NewFakeClock(randomTime().Truncate(time.Second))
+
+	issuer := testutil.SimpleIssuer(
+		"issuer-1",
+		testutil.SetSimpleIssuerNamespace("ns1"),
+		testutil.SetSimpleIssuerGeneration(80),
+		testutil.SetSimpleIssuerStatusCondition(
+			fakeClock,
+			cmapi.IssuerConditionReady,
+			cmmeta.ConditionUnknown,
+			v1alpha1.IssuerConditionReasonInitializing,
+			"test has started reconciling this Issuer",
+		),
+	)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(issuer).
+		Build()
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(issuer)}
+	logger := logrtesting.NewTestLoggerWithOptions(t, logrtesting.Options{LogTimestamp: true, Verbosity: 10})
+
+	bootstrapCalls := 0
+	checkCalls := 0
+	controller := IssuerReconciler{
+		ForObject:   &api.SimpleIssuer{},
+		FieldOwner:  "test-issuer-reconciler-bootstrap",
+		EventSource: fakeEventSource{},
+		Client:      fakeClient,
+		OnIssuerBootstrap: func(_ context.Context, _ v1alpha1.Issuer) error {
+			bootstrapCalls++
+			if bootstrapCalls == 1 {
+				return fmt.Errorf("[bootstrap error]")
+			}
+			return nil
+		},
+		Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+			checkCalls++
+			return nil
+		},
+		EventRecorder: record.NewFakeRecorder(100),
+		Clock:         fakeClock,
+	}
+
+	reconcileIssuer := func() (*cmapi.IssuerCondition, error) {
+		fakeClock.Step(time.Hour)
+
+		_, issuerStatusPatch, err := controller.reconcileStatusPatch(logger, context.TODO(), req)
+		require.NotNil(t, issuerStatusPatch)
+
+		// Store the patched status, like the status patch would.
+		var current api.SimpleIssuer
+		require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, &current))
+		current.Status = *issuerStatusPatch
+		require.NoError(t, fakeClient.Update(context.TODO(), &current))
+
+		return conditions.GetIssuerStatusCondition(issuerStatusPatch.Conditions, cmapi.IssuerConditionReady), err
+	}
+
+	// The first bootstrap attempt fails: the issuer is not Ready and is not
+	// checked.
+	readyCondition, err := reconcileIssuer()
+	(*errormatch.ErrorContains("[bootstrap error]"))(t, err)
+	require.NotNil(t, readyCondition)
+	assert.Equal(t, cmmeta.ConditionFalse, readyCondition.Status)
+	assert.Equal(t, "Issuer is not ready yet, failed to bootstrap the issuer: [bootstrap error]", readyCondition.Message)
+	assert.Equal(t, 1, bootstrapCalls)
+	assert.Equal(t, 0, checkCalls)
+
+	// The retried bootstrap succeeds, the issuer is not checked until the
+	// reconcile that is triggered by the bootstrapped annotation.
+	readyCondition, err = reconcileIssuer()
+	require.NoError(t, err)
+	require.NotNil(t, readyCondition)
+	assert.Equal(t, cmmeta.ConditionFalse, readyCondition.Status)
+	assert.Equal(t, 2, bootstrapCalls)
+	assert.Equal(t, 0, checkCalls)
+
+	var current api.SimpleIssuer
+	require.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, &current))
+	assert.Equal(t, fakeClock.Now().UTC().Format(time.RFC3339), current.Annotations[v1alpha1.IssuerBootstrappedAnnotation])
+
+	// Later reconciles only check the issuer.
+	for i := 1; i <= 2; i++ {
+		readyCondition, err = reconcileIssuer()
+		require.NoError(t, err)
+		require.NotNil(t, readyCondition)
+		assert.Equal(t, cmmeta.ConditionTrue, readyCondition.Status)
+		assert.Equal(t, 2, bootstrapCalls)
+		assert.Equal(t, i, checkCalls)
+	}
+}
+
+func TestIssuerReconcilerStatusSubresourceNotEnabled(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name          string
+		patchError    error
+		validateError *errormatch.Matcher
+	}
+
+	tests := []testCase{
+		// The API server returns a NotFound error without details if the
+		// status subresource is not enabled on the CRD.
+		{
+			name: "status-subresource-not-enabled",
+			patchError: &apierrors.StatusError{ErrStatus: metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    404,
+				Reason:  metav1.StatusReasonNotFound,
+				Message: "the server could not find the requested resource",
+				Details: &metav1.StatusDetails{},
+			}},
+			validateError: errormatch.ErrorContains("the status subresource is probably not enabled on the SimpleIssuer.testing.cert-manager.io CRD"),
+		},
+		// The issuer was deleted while it was being reconciled.
+		{
+			name:       "issuer-deleted",
+			patchError: apierrors.NewNotFound(schema.GroupResource{Group: api.SchemeGroupVersion.Group, Resource: "simpleissuers"}, "issuer-1"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			issuer := testutil.SimpleIssuer("issuer-1", testutil.SetSimpleIssuerNamespace("ns1"))
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, api.AddToScheme(scheme))
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(issuer).
+				WithInterceptorFuncs(interceptor.Funcs{
+					SubResourcePatch: func(_ context.Context, _ client.Client, _ string, _ client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+						return tc.patchError
+					},
+				}).
+				Build()
+
+			forObject := &api.SimpleIssuer{}
+			require.NoError(t, kubeutil.SetGroupVersionKind(scheme, forObject))
+
+			controller := IssuerReconciler{
+				ForObject:   forObject,
+				FieldOwner:  "test-status-subresource-not-enabled",
+				EventSource: fakeEventSource{},
+				Client:      fakeClient,
+				Check: func(_ context.Context, _ v1alpha1.Issuer) error {
+					return nil
+				},
+				EventRecorder: record.NewFakeRecorder(100),
+				Clock:         clocktesting.NewFakeClock(randomTime()),
+			}
+
+			_, err := controller.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(issuer)})
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// escalatedIssuerError returns the error that has to be reported to the issuer
+// for the Sign error err, and false if err must only be handled on the request.
+// By default, only errors that wrap a signer.IssuerError are escalated. If
+// the optional escalate function is set, it decides which errors are escalated
+// instead. For an escalated error that wraps a signer.IssuerError, the wrapped
+// error is reported.
+func escalatedIssuerError(err error, escalate func(error) bool) (error, bool) {
+	issuerError := new(signer.IssuerError)
+	isIssuerError := errors.As(err, issuerError)
+
+	if escalate == nil {
+		if isIssuerError {
+			return issuerError.Err, true
+		}
+		return nil, false
+	}
+
+	if !escalate(err) {
+		return nil, false
+	}
+	if isIssuerError {
+		return issuerError.Err, true
+	}
+	return err, true
+}
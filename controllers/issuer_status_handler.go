@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/conditions"
+)
+
+// IssuerStatusResponse is the JSON document that is served by the handler
+// returned by NewIssuerStatusHandler.
+type IssuerStatusResponse struct {
+	// Issuers contains the readiness of all the managed issuers, sorted by
+	// group, kind, namespace and name.
+	Issuers []IssuerStatusEntry `json:"issuers"`
+}
+
+// IssuerStatusEntry is the readiness of a single issuer, as reported by its
+// Ready condition.
+type IssuerStatusEntry struct {
+	Group     string `json:"group"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// Ready is true if the Ready condition of the issuer is True and up to
+	// date with the generation of the issuer.
+	Ready bool `json:"ready"`
+
+	// Reason and Message are copied from the Ready condition, they are empty
+	// if the issuer has no Ready condition yet.
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last transition time of the Ready condition.
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// NewIssuerStatusHandler returns an http.Handler that serves the readiness of
+// all issuers of the given GroupVersionKinds as an IssuerStatusResponse JSON
+// document. This allows external monitoring to check the issuers without
+// access to the Kubernetes API. The issuers are read using the provided
+// reader, which is typically the cached client of the manager.
+// The GVKs of the issuers that are managed by a CombinedController are
+// returned by its ManagedIssuerGVKs method.
+func NewIssuerStatusHandler(reader client.Reader, scheme *runtime.Scheme, issuerGVKs []schema.GroupVersionKind) http.Handler {
+	return &issuerStatusHandler{
+		reader:     reader,
+		scheme:     scheme,
+		issuerGVKs: append([]schema.GroupVersionKind(nil), issuerGVKs...),
+	}
+}
+
+type issuerStatusHandler struct {
+	reader     client.Reader
+	scheme     *runtime.Scheme
+	issuerGVKs []schema.GroupVersionKind
+}
+
+func (h *issuerStatusHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	entries, err := h.listIssuerStatuses(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(IssuerStatusResponse{Issuers: entries})
+}
+
+func (h *issuerStatusHandler) listIssuerStatuses(req *http.Request) ([]IssuerStatusEntry, error) {
+	entries := []IssuerStatusEntry{}
+
+	for _, gvk := range h.issuerGVKs {
+		listObj, err := h.scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create list for %s: %w", gvk.Kind, err)
+		}
+		list, ok := listObj.(client.ObjectList)
+		if !ok {
+			return nil, fmt.Errorf("%T is not a list type", listObj)
+		}
+
+		if err := h.reader.List(req.Context(), list); err != nil {
+			return nil, fmt.Errorf("failed to list %s issuers: %w", gvk.Kind, err)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s issuers: %w", gvk.Kind, err)
+		}
+
+		for _, item := range items {
+			issuer, ok := item.(v1alpha1.Issuer)
+			if !ok {
+				return nil, fmt.Errorf("%T does not implement the Issuer interface", item)
+			}
+
+			entry := IssuerStatusEntry{
+				Group:     gvk.Group,
+				Kind:      gvk.Kind,
+				Namespace: issuer.GetNamespace(),
+				Name:      issuer.GetName(),
+			}
+
+			if status := issuer.GetStatus(); status != nil {
+				readyCondition := conditions.GetIssuerStatusCondition(status.Conditions, cmapi.IssuerConditionReady)
+				if readyCondition != nil {
+					entry.Ready = readyCondition.Status == cmmeta.ConditionTrue &&
+						readyCondition.ObservedGeneration >= issuer.GetGeneration()
+					entry.Reason = readyCondition.Reason
+					entry.Message = readyCondition.Message
+					entry.LastTransitionTime = readyCondition.LastTransitionTime
+				}
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	return entries, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/testutil"
+)
+
+func TestIssuerStatusHandler(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := clocktesting.NewFakeClock(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			testutil.SimpleIssuer("issuer-ready",
+				testutil.SetSimpleIssuerNamespace("ns1"),
+				testutil.SetSimpleIssuerStatusCondition(
+					fakeClock,
+					cmapi.IssuerConditionReady,
+					cmmeta.ConditionTrue,
+					"Checked",
+					"Succeeded checking the issuer",
+				),
+			),
+			testutil.SimpleIssuer("issuer-not-checked",
+				testutil.SetSimpleIssuerNamespace("ns1"),
+			),
+			testutil.SimpleClusterIssuer("cluster-issuer-failed",
+				testutil.SetSimpleClusterIssuerStatusCondition(
+					fakeClock,
+					cmapi.IssuerConditionReady,
+					cmmeta.ConditionFalse,
+					"Pending",
+					"Issuer is not ready yet: the CA is down",
+				),
+			),
+		).
+		Build()
+
+	handler := NewIssuerStatusHandler(fakeClient, scheme, []schema.GroupVersionKind{
+		api.SchemeGroupVersion.WithKind("SimpleIssuer"),
+		api.SchemeGroupVersion.WithKind("SimpleClusterIssuer"),
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"issuers": [
+			{
+				"group": "testing.cert-manager.io",
+				"kind": "SimpleClusterIssuer",
+				"name": "cluster-issuer-failed",
+				"ready": false,
+				"reason": "Pending",
+				"message": "Issuer is not ready yet: the CA is down",
+				"lastTransitionTime": "2023-01-02T03:04:05Z"
+			},
+			{
+				"group": "testing.cert-manager.io",
+				"kind": "SimpleIssuer",
+				"namespace": "ns1",
+				"name": "issuer-not-checked",
+				"ready": false
+			},
+			{
+				"group": "testing.cert-manager.io",
+				"kind": "SimpleIssuer",
+				"namespace": "ns1",
+				"name": "issuer-ready",
+				"ready": true,
+				"reason": "Checked",
+				"message": "Succeeded checking the issuer",
+				"lastTransitionTime": "2023-01-02T03:04:05Z"
+			}
+		]
+	}`, string(body))
+
+	postResp, err := http.Post(server.URL, "application/json", nil)
+	require.NoError(t, err)
+	defer postResp.Body.Close()
+
+	assert.Equal(t, http.StatusMethodNotAllowed, postResp.StatusCode)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/internal/kubeutil"
+)
+
+// GVKForIssuer returns the GroupVersionKind that is registered in the scheme
+// for the issuer type. This is the GVK that issuer-lib uses for the issuer, it
+// can be used to construct issuerRefs and test fixtures.
+func GVKForIssuer(scheme *runtime.Scheme, issuer v1alpha1.Issuer) (schema.GroupVersionKind, error) {
+	return kubeutil.GVKForIssuer(scheme, issuer)
+}
+
+// ValidateIssuerType checks that an issuer type can be used by the controllers,
+// so that a malformed type results in a clear error at startup instead of a
+// confusing error at runtime. It checks that the type is registered in the
+// scheme, that GetStatus returns a pointer to the status of the issuer (and
+// not a copy) and that GetIssuerTypeIdentifier returns a non-empty value.
+// It is called by SetupWithManager for all the issuer types.
+func ValidateIssuerType(scheme *runtime.Scheme, issuer v1alpha1.Issuer) error {
+	if issuer == nil || reflect.ValueOf(issuer).IsNil() {
+		return fmt.Errorf("invalid issuer type: the issuer type must be a non-nil pointer, eg. &MyIssuer{}")
+	}
+
+	if _, err := apiutil.GVKForObject(issuer, scheme); err != nil {
+		return fmt.Errorf("invalid issuer type %T: the type is not registered in the scheme, add it using its AddToScheme function: %w", issuer, err)
+	}
+
+	// Use a copy, so that the issuer type itself is not modified.
+	issuerCopy, ok := issuer.DeepCopyObject().(v1alpha1.Issuer)
+	if !ok {
+		return fmt.Errorf("invalid issuer type %T: DeepCopyObject must return a %T", issuer, issuer)
+	}
+
+	status := issuerCopy.GetStatus()
+	if status == nil {
+		return fmt.Errorf("invalid issuer type %T: GetStatus must return a pointer to the status of the issuer, but it returned nil", issuer)
+	}
+
+	status.Conditions = append(status.Conditions, cmapi.IssuerCondition{Type: "IssuerLibValidation"})
+	if statusAgain := issuerCopy.GetStatus(); statusAgain == nil || len(statusAgain.Conditions) != len(status.Conditions) {
+		return fmt.Errorf("invalid issuer type %T: GetStatus must return a pointer to the status of the issuer, but it returned a copy", issuer)
+	}
+
+	if issuer.GetIssuerTypeIdentifier() == "" {
+		return fmt.Errorf("invalid issuer type %T: GetIssuerTypeIdentifier must return a non-empty value, eg. \"myissuers.example.com\"", issuer)
+	}
+
+	return nil
+}
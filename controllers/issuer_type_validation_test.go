@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+)
+
+// malformedIssuer is an issuer type with the mistakes that are commonly made
+// when implementing the v1alpha1.Issuer interface.
+type malformedIssuer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status v1alpha1.IssuerStatus `json:"status,omitempty"`
+
+	nilStatus  bool
+	copyStatus bool
+	identifier string
+}
+
+func (m *malformedIssuer) GetStatus() *v1alpha1.IssuerStatus {
+	if m.nilStatus {
+		return nil
+	}
+	if m.copyStatus {
+		status := m.Status
+		return &status
+	}
+	return &m.Status
+}
+
+func (m *malformedIssuer) GetIssuerTypeIdentifier() string {
+	return m.identifier
+}
+
+func (m *malformedIssuer) DeepCopyObject() runtime.Object {
+	out := *m
+	m.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	m.Status.DeepCopyInto(&out.Status)
+	return &out
+}
+
+func TestGVKForIssuer(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+
+	gvk, err := GVKForIssuer(scheme, &api.SimpleClusterIssuer{})
+	require.NoError(t, err)
+	assert.Equal(t, api.SchemeGroupVersion.WithKind("SimpleClusterIssuer"), gvk)
+
+	_, err = GVKForIssuer(runtime.NewScheme(), &api.SimpleIssuer{})
+	assert.ErrorContains(t, err, "no kind is registered for the type")
+}
+
+func TestValidateIssuerType(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+	scheme.AddKnownTypes(api.SchemeGroupVersion, &malformedIssuer{})
+
+	type testCase struct {
+		name          string
+		scheme        *runtime.Scheme
+		issuer        v1alpha1.Issuer
+		validateError *errormatch.Matcher
+	}
+
+	tests := []testCase{
+		{
+			name:   "valid-issuer",
+			scheme: scheme,
+			issuer: &api.SimpleIssuer{},
+		},
+		{
+			name:   "valid-cluster-issuer",
+			scheme: scheme,
+			issuer: &api.SimpleClusterIssuer{},
+		},
+		{
+			name:   "valid-malformed-issuer-without-mistakes",
+			scheme: scheme,
+			issuer: &malformedIssuer{identifier: "malformedissuers.testing.cert-manager.io"},
+		},
+		{
+			name:          "nil-issuer",
+			scheme:        scheme,
+			issuer:        nil,
+			validateError: errormatch.ErrorContains("invalid issuer type: the issuer type must be a non-nil pointer"),
+		},
+		{
+			name:          "typed-nil-issuer",
+			scheme:        scheme,
+			issuer:        (*api.SimpleIssuer)(nil),
+			validateError: errormatch.ErrorContains("invalid issuer type: the issuer type must be a non-nil pointer"),
+		},
+		{
+			name:          "not-registered",
+			scheme:        runtime.NewScheme(),
+			issuer:        &api.SimpleIssuer{},
+			validateError: errormatch.ErrorContains("invalid issuer type *api.SimpleIssuer: the type is not registered in the scheme"),
+		},
+		{
+			name:          "nil-status",
+			scheme:        scheme,
+			issuer:        &malformedIssuer{nilStatus: true, identifier: "malformedissuers.testing.cert-manager.io"},
+			validateError: errormatch.ErrorContains("invalid issuer type *controllers.malformedIssuer: GetStatus must return a pointer to the status of the issuer, but it returned nil"),
+		},
+		{
+			name:          "status-copy",
+			scheme:        scheme,
+			issuer:        &malformedIssuer{copyStatus: true, identifier: "malformedissuers.testing.cert-manager.io"},
+			validateError: errormatch.ErrorContains("invalid issuer type *controllers.malformedIssuer: GetStatus must return a pointer to the status of the issuer, but it returned a copy"),
+		},
+		{
+			name:          "empty-identifier",
+			scheme:        scheme,
+			issuer:        &malformedIssuer{},
+			validateError: errormatch.ErrorContains("invalid issuer type *controllers.malformedIssuer: GetIssuerTypeIdentifier must return a non-empty value"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateIssuerType(tc.scheme, tc.issuer)
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// validateKubernetesSignerConstraints validates that the CertificateSigningRequest
+// meets the constraints that Kubernetes mandates for its well-known signerNames,
+// see https://kubernetes.io/docs/reference/access-authn-authz/certificate-signing-requests/#kubernetes-signers.
+// The following constraints are enforced:
+//
+//   - kubernetes.io/kube-apiserver-client: the usages must include "client auth"
+//     and may only include "digital signature", "key encipherment" and "client auth".
+//   - kubernetes.io/kube-apiserver-client-kubelet: the subject must have the
+//     organization "system:nodes" and a "system:node:<node-name>" common name, no
+//     subjectAltNames are allowed and the usages must be exactly "digital signature",
+//     "client auth" and optionally "key encipherment".
+//   - kubernetes.io/kubelet-serving: the subject must have the organization
+//     "system:nodes" and a "system:node:<node-name>" common name, at least one DNS
+//     or IP subjectAltName is required, no email or URI subjectAltNames are allowed
+//     and the usages must be exactly "digital signature", "server auth" and optionally
+//     "key encipherment".
+//
+// For any other signerName, no constraints are enforced.
+// All violations are returned together as a signer.ValidationError.
+func validateKubernetesSignerConstraints(csr *certificatesv1.CertificateSigningRequest) error {
+	var violations []error
+
+	switch csr.Spec.SignerName {
+	case certificatesv1.KubeAPIServerClientSignerName:
+		if !hasUsage(csr.Spec.Usages, certificatesv1.UsageClientAuth) {
+			violations = append(violations, fmt.Errorf("%s: usages must include %q", csr.Spec.SignerName, certificatesv1.UsageClientAuth))
+		}
+		violations = append(violations, validateAllowedUsages(
+			csr.Spec.SignerName,
+			csr.Spec.Usages,
+			certificatesv1.UsageDigitalSignature,
+			certificatesv1.UsageKeyEncipherment,
+			certificatesv1.UsageClientAuth,
+		))
+
+	case certificatesv1.KubeAPIServerClientKubeletSignerName:
+		request, err := parseKubernetesCSR(csr)
+		if err != nil {
+			return signer.ValidationError{Violations: []string{err.Error()}}
+		}
+		violations = append(violations, validateNodeSubject(csr.Spec.SignerName, request)...)
+		if len(request.DNSNames) > 0 || len(request.IPAddresses) > 0 || len(request.EmailAddresses) > 0 || len(request.URIs) > 0 {
+			violations = append(violations, fmt.Errorf("%s: subjectAltNames are not allowed", csr.Spec.SignerName))
+		}
+		violations = append(violations, validateExactUsages(csr.Spec.SignerName, csr.Spec.Usages, certificatesv1.UsageClientAuth)...)
+
+	case certificatesv1.KubeletServingSignerName:
+		request, err := parseKubernetesCSR(csr)
+		if err != nil {
+			return signer.ValidationError{Violations: []string{err.Error()}}
+		}
+		violations = append(violations, validateNodeSubject(csr.Spec.SignerName, request)...)
+		if len(request.DNSNames) == 0 && len(request.IPAddresses) == 0 {
+			violations = append(violations, fmt.Errorf("%s: at least one DNS or IP subjectAltName is required", csr.Spec.SignerName))
+		}
+		if len(request.EmailAddresses) > 0 || len(request.URIs) > 0 {
+			violations = append(violations, fmt.Errorf("%s: email and URI subjectAltNames are not allowed", csr.Spec.SignerName))
+		}
+		violations = append(violations, validateExactUsages(csr.Spec.SignerName, csr.Spec.Usages, certificatesv1.UsageServerAuth)...)
+	}
+
+	var messages []string
+	for _, violation := range violations {
+		if violation != nil {
+			messages = append(messages, violation.Error())
+		}
+	}
+	if len(messages) > 0 {
+		return signer.ValidationError{Violations: messages}
+	}
+
+	return nil
+}
+
+func parseKubernetesCSR(csr *certificatesv1.CertificateSigningRequest) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("%s: failed to decode PEM encoded certificate request", csr.Spec.SignerName)
+	}
+
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to parse certificate request: %w", csr.Spec.SignerName, err)
+	}
+
+	return request, nil
+}
+
+func validateNodeSubject(signerName string, request *x509.CertificateRequest) []error {
+	var violations []error
+
+	if !reflect.DeepEqual(request.Subject.Organization, []string{"system:nodes"}) {
+		violations = append(violations, fmt.Errorf("%s: subject organization must be exactly [\"system:nodes\"], got %q", signerName, request.Subject.Organization))
+	}
+
+	if nodeName := strings.TrimPrefix(request.Subject.CommonName, "system:node:"); nodeName == request.Subject.CommonName || nodeName == "" {
+		violations = append(violations, fmt.Errorf("%s: subject common name must have the format \"system:node:<node-name>\", got %q", signerName, request.Subject.CommonName))
+	}
+
+	return violations
+}
+
+// validateExactUsages validates that the usages are exactly "digital signature",
+// the provided extended usage and optionally "key encipherment".
+func validateExactUsages(signerName string, usages []certificatesv1.KeyUsage, extendedUsage certificatesv1.KeyUsage) []error {
+	var violations []error
+
+	if !hasUsage(usages, certificatesv1.UsageDigitalSignature) || !hasUsage(usages, extendedUsage) {
+		violations = append(violations, fmt.Errorf("%s: usages must include %q and %q", signerName, certificatesv1.UsageDigitalSignature, extendedUsage))
+	}
+
+	return append(violations, validateAllowedUsages(
+		signerName,
+		usages,
+		certificatesv1.UsageDigitalSignature,
+		certificatesv1.UsageKeyEncipherment,
+		extendedUsage,
+	))
+}
+
+func validateAllowedUsages(signerName string, usages []certificatesv1.KeyUsage, allowed ...certificatesv1.KeyUsage) error {
+	var disallowed []string
+	for _, usage := range usages {
+		if !hasUsage(allowed, usage) {
+			disallowed = append(disallowed, string(usage))
+		}
+	}
+
+	if len(disallowed) > 0 {
+		sort.Strings(disallowed)
+		return fmt.Errorf("%s: usages %q are not allowed", signerName, disallowed)
+	}
+
+	return nil
+}
+
+func hasUsage(usages []certificatesv1.KeyUsage, usage certificatesv1.KeyUsage) bool {
+	for _, u := range usages {
+		if u == usage {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/x509"
+	"testing"
+
+	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+)
+
+func setCSROrganization(organization ...string) cmgen.CSRModifier {
+	return func(cr *x509.CertificateRequest) error {
+		cr.Subject.Organization = organization
+		return nil
+	}
+}
+
+func TestValidateKubernetesSignerConstraints(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name          string
+		signerName    string
+		csrMods       []cmgen.CSRModifier
+		usages        []certificatesv1.KeyUsage
+		validateError *errormatch.Matcher
+	}
+
+	nodeSubject := []cmgen.CSRModifier{
+		setCSROrganization("system:nodes"),
+		cmgen.SetCSRCommonName("system:node:node-1"),
+	}
+
+	withNodeSubject := func(mods ...cmgen.CSRModifier) []cmgen.CSRModifier {
+		return append(append([]cmgen.CSRModifier{}, nodeSubject...), mods...)
+	}
+
+	servingUsages := []certificatesv1.KeyUsage{
+		certificatesv1.UsageDigitalSignature,
+		certificatesv1.UsageKeyEncipherment,
+		certificatesv1.UsageServerAuth,
+	}
+
+	clientUsages := []certificatesv1.KeyUsage{
+		certificatesv1.UsageDigitalSignature,
+		certificatesv1.UsageKeyEncipherment,
+		certificatesv1.UsageClientAuth,
+	}
+
+	tests := []testCase{
+		{
+			name:       "other-signer-name-is-not-validated",
+			signerName: "simpleclusterissuers.issuer.cert-manager.io/name",
+			usages:     []certificatesv1.KeyUsage{certificatesv1.UsageCodeSigning},
+		},
+
+		// kubernetes.io/kubelet-serving
+		{
+			name:       "kubelet-serving-valid",
+			signerName: certificatesv1.KubeletServingSignerName,
+			csrMods:    withNodeSubject(cmgen.SetCSRDNSNames("node-1.example.com"), cmgen.SetCSRIPAddressesFromStrings("10.0.0.1")),
+			usages:     servingUsages,
+		},
+		{
+			name:       "kubelet-serving-valid-without-key-encipherment",
+			signerName: certificatesv1.KubeletServingSignerName,
+			csrMods:    withNodeSubject(cmgen.SetCSRIPAddressesFromStrings("10.0.0.1")),
+			usages:     []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageServerAuth},
+		},
+		{
+			name:          "kubelet-serving-wrong-organization",
+			signerName:    certificatesv1.KubeletServingSignerName,
+			csrMods:       []cmgen.CSRModifier{setCSROrganization("other"), cmgen.SetCSRCommonName("system:node:node-1"), cmgen.SetCSRDNSNames("node-1.example.com")},
+			usages:        servingUsages,
+			validateError: errormatch.ErrorContains("kubernetes.io/kubelet-serving: subject organization must be exactly [\"system:nodes\"], got [\"other\"]"),
+		},
+		{
+			name:          "kubelet-serving-wrong-common-name",
+			signerName:    certificatesv1.KubeletServingSignerName,
+			csrMods:       []cmgen.CSRModifier{setCSROrganization("system:nodes"), cmgen.SetCSRCommonName("node-1"), cmgen.SetCSRDNSNames("node-1.example.com")},
+			usages:        servingUsages,
+			validateError: errormatch.ErrorContains("kubernetes.io/kubelet-serving: subject common name must have the format \"system:node:<node-name>\", got \"node-1\""),
+		},
+		{
+			name:          "kubelet-serving-empty-node-name",
+			signerName:    certificatesv1.KubeletServingSignerName,
+			csrMods:       []cmgen.CSRModifier{setCSROrganization("system:nodes"), cmgen.SetCSRCommonName("system:node:"), cmgen.SetCSRDNSNames("node-1.example.com")},
+			usages:        servingUsages,
+			validateError: errormatch.ErrorContains("kubernetes.io/kubelet-serving: subject common name must have the format \"system:node:<node-name>\", got \"system:node:\""),
+		},
+		{
+			name:          "kubelet-serving-no-sans",
+			signerName:    certificatesv1.KubeletServingSignerName,
+			csrMods:       withNodeSubject(),
+			usages:        servingUsages,
+			validateError: errormatch.ErrorContains("kubernetes.io/kubelet-serving: at least one DNS or IP subjectAltName is required"),
+		},
+		{
+			name:          "kubelet-serving-email-san",
+			signerName:    certificatesv1.KubeletServingSignerName,
+			csrMods:       withNodeSubject(cmgen.SetCSRDNSNames("node-1.example.com"), cmgen.SetCSREmails([]string{"node-1@example.com"})),
+			usages:        servingUsages,
+			validateError: errormatch.ErrorContains("kubernetes.io/kubelet-serving: email and URI subjectAltNames are not allowed"),
+		},
+		{
+			name:          "kubelet-serving-uri-san",
+			signerName:    certificatesv1.KubeletServingSignerName,
+			csrMods:       withNodeSubject(cmgen.SetCSRDNSNames("node-1.example.com"), cmgen.SetCSRURIsFromStrings("spiffe://example.com/node-1")),
+			usages:        servingUsages,
+			validateError: errormatch.ErrorContains("kubernetes.io/kubelet-serving: email and URI subjectAltNames are not allowed"),
+		},
+		{
+			name:          "kubelet-serving-missing-server-auth",
+			signerName:    certificatesv1.KubeletServingSignerName,
+			csrMods:       withNodeSubject(cmgen.SetCSRDNSNames("node-1.example.com")),
+			usages:        []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment},
+			validateError: errormatch.ErrorContains("kubernetes.io/kubelet-serving: usages must include \"digital signature\" and \"server auth\""),
+		},
+		{
+			name:          "kubelet-serving-extra-usages",
+			signerName:    certificatesv1.KubeletServingSignerName,
+			csrMods:       withNodeSubject(cmgen.SetCSRDNSNames("node-1.example.com")),
+			usages:        append(append([]certificatesv1.KeyUsage{}, servingUsages...), certificatesv1.UsageClientAuth, certificatesv1.UsageCertSign),
+			validateError: errormatch.ErrorContains("kubernetes.io/kubelet-serving: usages [\"cert sign\" \"client auth\"] are not allowed"),
+		},
+		{
+			name:          "kubelet-serving-invalid-request",
+			signerName:    certificatesv1.KubeletServingSignerName,
+			usages:        servingUsages,
+			validateError: errormatch.ErrorContains("kubernetes.io/kubelet-serving: failed to decode PEM encoded certificate request"),
+		},
+
+		// kubernetes.io/kube-apiserver-client-kubelet
+		{
+			name:       "kube-apiserver-client-kubelet-valid",
+			signerName: certificatesv1.KubeAPIServerClientKubeletSignerName,
+			csrMods:    withNodeSubject(),
+			usages:     clientUsages,
+		},
+		{
+			name:          "kube-apiserver-client-kubelet-sans",
+			signerName:    certificatesv1.KubeAPIServerClientKubeletSignerName,
+			csrMods:       withNodeSubject(cmgen.SetCSRDNSNames("node-1.example.com")),
+			usages:        clientUsages,
+			validateError: errormatch.ErrorContains("kubernetes.io/kube-apiserver-client-kubelet: subjectAltNames are not allowed"),
+		},
+		{
+			name:          "kube-apiserver-client-kubelet-server-auth",
+			signerName:    certificatesv1.KubeAPIServerClientKubeletSignerName,
+			csrMods:       withNodeSubject(),
+			usages:        append(append([]certificatesv1.KeyUsage{}, clientUsages...), certificatesv1.UsageServerAuth),
+			validateError: errormatch.ErrorContains("kubernetes.io/kube-apiserver-client-kubelet: usages [\"server auth\"] are not allowed"),
+		},
+
+		// kubernetes.io/kube-apiserver-client
+		{
+			name:       "kube-apiserver-client-valid",
+			signerName: certificatesv1.KubeAPIServerClientSignerName,
+			csrMods:    []cmgen.CSRModifier{cmgen.SetCSRCommonName("user-1")},
+			usages:     []certificatesv1.KeyUsage{certificatesv1.UsageClientAuth},
+		},
+		{
+			name:          "kube-apiserver-client-missing-client-auth",
+			signerName:    certificatesv1.KubeAPIServerClientSignerName,
+			csrMods:       []cmgen.CSRModifier{cmgen.SetCSRCommonName("user-1")},
+			usages:        []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature},
+			validateError: errormatch.ErrorContains("kubernetes.io/kube-apiserver-client: usages must include \"client auth\""),
+		},
+		{
+			name:          "kube-apiserver-client-server-auth",
+			signerName:    certificatesv1.KubeAPIServerClientSignerName,
+			csrMods:       []cmgen.CSRModifier{cmgen.SetCSRCommonName("user-1")},
+			usages:        []certificatesv1.KeyUsage{certificatesv1.UsageClientAuth, certificatesv1.UsageServerAuth},
+			validateError: errormatch.ErrorContains("kubernetes.io/kube-apiserver-client: usages [\"server auth\"] are not allowed"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var request []byte
+			if tc.csrMods != nil {
+				var err error
+				request, _, err = cmgen.CSR(x509.ECDSA, tc.csrMods...)
+				require.NoError(t, err)
+			}
+
+			csr := cmgen.CertificateSigningRequest(
+				"csr1",
+				cmgen.SetCertificateSigningRequestSignerName(tc.signerName),
+				cmgen.SetCertificateSigningRequestRequest(request),
+				cmgen.SetCertificateSigningRequestUsages(tc.usages),
+			)
+
+			err := validateKubernetesSignerConstraints(csr)
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// The controllers use the following log verbosity conventions:
+//   - Errors are always logged, regardless of the verbosity.
+//   - V(1) is used to log the outcome of a reconcile, eg. "Issuer not found. Ignoring."
+//   - V(2) is used for debug information that is logged on every reconcile, eg.
+//     "Starting reconcile loop".
+
+// newLogConstructor returns a log constructor that mirrors the default log
+// constructor of controller-runtime, but that uses the provided logger instead
+// of the manager's logger.
+func newLogConstructor(logger logr.Logger, gvk schema.GroupVersionKind) func(*reconcile.Request) logr.Logger {
+	logger = logger.WithValues(
+		"controller", strings.ToLower(gvk.Kind),
+		"controllerGroup", gvk.Group,
+		"controllerKind", gvk.Kind,
+	)
+
+	return func(req *reconcile.Request) logr.Logger {
+		logger := logger
+		if req != nil {
+			logger = logger.WithValues(
+				gvk.Kind, klog.KRef(req.Namespace, req.Name),
+				"namespace", req.Namespace, "name", req.Name,
+			)
+		}
+		return logger
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestNewLogConstructor(t *testing.T) {
+	t.Parallel()
+
+	var lines []string
+	logger := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{Verbosity: 1})
+
+	logConstructor := newLogConstructor(logger, schema.GroupVersionKind{
+		Group:   "testing.cert-manager.io",
+		Version: "api",
+		Kind:    "SimpleIssuer",
+	})
+
+	logConstructor(nil).Info("no request")
+	logConstructor(&reconcile.Request{
+		NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "issuer-1"},
+	}).V(1).Info("with request")
+	logConstructor(nil).V(2).Info("filtered")
+
+	assert.Equal(t, []string{
+		`"level"=0 "msg"="no request" "controller"="simpleissuer" "controllerGroup"="testing.cert-manager.io" "controllerKind"="SimpleIssuer"`,
+		`"level"=1 "msg"="with request" "controller"="simpleissuer" "controllerGroup"="testing.cert-manager.io" "controllerKind"="SimpleIssuer" "SimpleIssuer"={"name":"issuer-1","namespace":"ns1"} "namespace"="ns1" "name"="issuer-1"`,
+	}, lines)
+}
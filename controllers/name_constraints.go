@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	v1alpha1 "github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// checkNameConstraints validates the subjectAltNames of the request against
+// the name constraints of the issuer. A PermanentError is returned if the
+// request contains a subjectAltName that is not allowed.
+func checkNameConstraints(
+	ctx context.Context,
+	getNameConstraints signer.GetNameConstraints,
+	cr signer.CertificateRequestObject,
+	issuerObject v1alpha1.Issuer,
+) error {
+	if getNameConstraints == nil {
+		return nil
+	}
+
+	nameConstraints, err := getNameConstraints(ctx, issuerObject)
+	if err != nil {
+		return fmt.Errorf("failed to get name constraints: %w", err)
+	}
+	if nameConstraints == nil {
+		return nil
+	}
+
+	template, _, _, err := cr.GetRequest()
+	if err != nil {
+		return signer.PermanentError{Err: fmt.Errorf("failed to parse request: %w", err)}
+	}
+
+	return nameConstraints.Validate(template)
+}
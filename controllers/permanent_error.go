@@ -0,0 +1,32 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// isPermanentError returns true if err wraps a signer.PermanentError or if the
+// optional isPermanent function classifies it as permanent.
+func isPermanentError(err error, isPermanent func(error) bool) bool {
+	if errors.As(err, &signer.PermanentError{}) {
+		return true
+	}
+	return isPermanent != nil && isPermanent(err)
+}
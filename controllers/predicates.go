@@ -21,7 +21,9 @@ import (
 
 	cmutil "github.com/cert-manager/cert-manager/pkg/api/util"
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/cert-manager/cert-manager/pkg/controller/certificatesigningrequests/util"
 	certificatesv1 "k8s.io/api/certificates/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
@@ -75,6 +77,38 @@ func (CertificateRequestPredicate) Update(e event.UpdateEvent) bool {
 	return !reflect.DeepEqual(e.ObjectNew.GetAnnotations(), e.ObjectOld.GetAnnotations())
 }
 
+// This predicate is used to filter out CertificateRequest events for
+// CertificateRequests that have not been approved or denied yet.
+//
+// The reconciler ignores these CertificateRequests, so there is no need to
+// reconcile them. Once an approval controller adds the Approved or Denied
+// condition, the resulting update event passes this predicate.
+type CertificateRequestApprovedPredicate struct {
+	predicate.Funcs
+}
+
+func (CertificateRequestApprovedPredicate) Create(e event.CreateEvent) bool {
+	return certificateRequestIsApprovedOrDenied(e.Object)
+}
+
+func (CertificateRequestApprovedPredicate) Update(e event.UpdateEvent) bool {
+	return certificateRequestIsApprovedOrDenied(e.ObjectNew)
+}
+
+func (CertificateRequestApprovedPredicate) Generic(e event.GenericEvent) bool {
+	return certificateRequestIsApprovedOrDenied(e.Object)
+}
+
+func certificateRequestIsApprovedOrDenied(obj client.Object) bool {
+	cr, ok := obj.(*cmapi.CertificateRequest)
+	if !ok {
+		// the object is missing or invalid, just reconcile to be safe
+		return true
+	}
+
+	return cmutil.CertificateRequestIsApproved(cr) || cmutil.CertificateRequestIsDenied(cr)
+}
+
 // This predicate is used to indicate when a CertificateSigningRequest event should
 // trigger a reconciliation of itself.
 //
@@ -116,6 +150,37 @@ func (CertificateSigningRequestPredicate) Update(e event.UpdateEvent) bool {
 	return !reflect.DeepEqual(e.ObjectNew.GetAnnotations(), e.ObjectOld.GetAnnotations())
 }
 
+// This predicate is used to filter out CertificateSigningRequest events for
+// CertificateSigningRequests that have not been approved or denied yet.
+//
+// Kubernetes CSRs use their own approval conditions, which are set using
+// the approval subresource.
+type CertificateSigningRequestApprovedPredicate struct {
+	predicate.Funcs
+}
+
+func (CertificateSigningRequestApprovedPredicate) Create(e event.CreateEvent) bool {
+	return certificateSigningRequestIsApprovedOrDenied(e.Object)
+}
+
+func (CertificateSigningRequestApprovedPredicate) Update(e event.UpdateEvent) bool {
+	return certificateSigningRequestIsApprovedOrDenied(e.ObjectNew)
+}
+
+func (CertificateSigningRequestApprovedPredicate) Generic(e event.GenericEvent) bool {
+	return certificateSigningRequestIsApprovedOrDenied(e.Object)
+}
+
+func certificateSigningRequestIsApprovedOrDenied(obj client.Object) bool {
+	csr, ok := obj.(*certificatesv1.CertificateSigningRequest)
+	if !ok {
+		// the object is missing or invalid, just reconcile to be safe
+		return true
+	}
+
+	return util.CertificateSigningRequestIsApproved(csr) || util.CertificateSigningRequestIsDenied(csr)
+}
+
 // Predicate for Issuer events that should trigger the CertificateRequest reconciler
 //
 // In these cases we want to trigger:
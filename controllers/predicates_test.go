@@ -380,6 +380,186 @@ func TestCertificateSigningRequestPredicate(t *testing.T) {
 	}
 }
 
+func TestCertificateRequestApprovedPredicate(t *testing.T) {
+	predicate := controllers.CertificateRequestApprovedPredicate{}
+
+	cr1 := cmgen.CertificateRequest("cr1")
+	approvedCr1 := cmgen.CertificateRequestFrom(cr1,
+		cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionApproved,
+			Status: cmmeta.ConditionTrue,
+		}),
+	)
+	deniedCr1 := cmgen.CertificateRequestFrom(cr1,
+		cmgen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionDenied,
+			Status: cmmeta.ConditionTrue,
+		}),
+	)
+
+	type testcase struct {
+		name            string
+		event           interface{}
+		shouldReconcile bool
+	}
+
+	testcases := []testcase{
+		{
+			name:            "create-wrong-type",
+			shouldReconcile: true,
+			event:           event.CreateEvent{Object: &corev1.ConfigMap{}},
+		},
+		{
+			name:            "create-unapproved",
+			shouldReconcile: false,
+			event:           event.CreateEvent{Object: cr1},
+		},
+		{
+			name:            "create-approved",
+			shouldReconcile: true,
+			event:           event.CreateEvent{Object: approvedCr1},
+		},
+		{
+			name:            "create-denied",
+			shouldReconcile: true,
+			event:           event.CreateEvent{Object: deniedCr1},
+		},
+		{
+			name:            "update-nil",
+			shouldReconcile: true,
+			event:           event.UpdateEvent{ObjectOld: cr1, ObjectNew: nil},
+		},
+		{
+			name:            "update-unapproved",
+			shouldReconcile: false,
+			event: event.UpdateEvent{
+				ObjectOld: cr1,
+				ObjectNew: cmgen.CertificateRequestFrom(cr1,
+					cmgen.AddCertificateRequestAnnotations(map[string]string{
+						"test-annotation1": "value1",
+					}),
+				),
+			},
+		},
+		{
+			name:            "update-approved",
+			shouldReconcile: true,
+			event:           event.UpdateEvent{ObjectOld: cr1, ObjectNew: approvedCr1},
+		},
+		{
+			name:            "generic-unapproved",
+			shouldReconcile: false,
+			event:           event.GenericEvent{Object: cr1},
+		},
+		{
+			name:            "generic-denied",
+			shouldReconcile: true,
+			event:           event.GenericEvent{Object: deniedCr1},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var result bool
+			switch e := tc.event.(type) {
+			case event.CreateEvent:
+				result = predicate.Create(e)
+			case event.UpdateEvent:
+				result = predicate.Update(e)
+			case event.GenericEvent:
+				result = predicate.Generic(e)
+			}
+			require.Equal(t, tc.shouldReconcile, result)
+		})
+	}
+}
+
+func TestCertificateSigningRequestApprovedPredicate(t *testing.T) {
+	predicate := controllers.CertificateSigningRequestApprovedPredicate{}
+
+	csr1 := cmgen.CertificateSigningRequest("csr1")
+	approvedCsr1 := cmgen.CertificateSigningRequestFrom(csr1,
+		cmgen.SetCertificateSigningRequestStatusCondition(certificatesv1.CertificateSigningRequestCondition{
+			Type:   certificatesv1.CertificateApproved,
+			Status: corev1.ConditionTrue,
+		}),
+	)
+	deniedCsr1 := cmgen.CertificateSigningRequestFrom(csr1,
+		cmgen.SetCertificateSigningRequestStatusCondition(certificatesv1.CertificateSigningRequestCondition{
+			Type:   certificatesv1.CertificateDenied,
+			Status: corev1.ConditionTrue,
+		}),
+	)
+
+	type testcase struct {
+		name            string
+		event           interface{}
+		shouldReconcile bool
+	}
+
+	testcases := []testcase{
+		{
+			name:            "create-wrong-type",
+			shouldReconcile: true,
+			event:           event.CreateEvent{Object: &corev1.ConfigMap{}},
+		},
+		{
+			name:            "create-unapproved",
+			shouldReconcile: false,
+			event:           event.CreateEvent{Object: csr1},
+		},
+		{
+			name:            "create-approved",
+			shouldReconcile: true,
+			event:           event.CreateEvent{Object: approvedCsr1},
+		},
+		{
+			name:            "create-denied",
+			shouldReconcile: true,
+			event:           event.CreateEvent{Object: deniedCsr1},
+		},
+		{
+			name:            "update-unapproved",
+			shouldReconcile: false,
+			event: event.UpdateEvent{
+				ObjectOld: csr1,
+				ObjectNew: cmgen.CertificateSigningRequestFrom(csr1,
+					cmgen.AddCertificateSigningRequestAnnotations(map[string]string{
+						"test-annotation1": "value1",
+					}),
+				),
+			},
+		},
+		{
+			name:            "update-approved",
+			shouldReconcile: true,
+			event:           event.UpdateEvent{ObjectOld: csr1, ObjectNew: approvedCsr1},
+		},
+		{
+			name:            "generic-unapproved",
+			shouldReconcile: false,
+			event:           event.GenericEvent{Object: csr1},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var result bool
+			switch e := tc.event.(type) {
+			case event.CreateEvent:
+				result = predicate.Create(e)
+			case event.UpdateEvent:
+				result = predicate.Update(e)
+			case event.GenericEvent:
+				result = predicate.Generic(e)
+			}
+			require.Equal(t, tc.shouldReconcile, result)
+		})
+	}
+}
+
 type testissuer struct {
 	Status *v1alpha1.IssuerStatus
 	metav1.Object
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// workqueueDepthMetric is the name of the gauge that controller-runtime
+	// uses to expose the depth of the workqueue of each controller.
+	workqueueDepthMetric = "workqueue_depth"
+
+	// queueDepthCheckInterval is the interval at which the workqueue depth is checked.
+	queueDepthCheckInterval = 10 * time.Second
+
+	// queueDepthWarningPeriod is the period for which the workqueue depth has to
+	// exceed the threshold before a warning is logged. While the threshold stays
+	// exceeded, at most one warning is logged per period.
+	queueDepthWarningPeriod = time.Minute
+)
+
+// queueDepthMonitor periodically checks the depth of the workqueues of the
+// controllers (as exposed by the controller-runtime metrics) and logs a
+// warning if the depth exceeds the threshold for a sustained period.
+type queueDepthMonitor struct {
+	controllerNames []string
+	threshold       int
+
+	gatherer prometheus.Gatherer
+	clock    clock.PassiveClock
+	logger   logr.Logger
+
+	// exceededSince contains the time since which the threshold has been
+	// exceeded (or the last warning was logged) for each controller.
+	exceededSince map[string]time.Time
+}
+
+var _ manager.Runnable = &queueDepthMonitor{}
+var _ manager.LeaderElectionRunnable = &queueDepthMonitor{}
+
+func (m *queueDepthMonitor) Start(ctx context.Context) error {
+	ticker := time.NewTicker(queueDepthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// NeedLeaderElection returns true, because the controllers only process
+// their workqueues when they are the leader.
+func (m *queueDepthMonitor) NeedLeaderElection() bool {
+	return true
+}
+
+// check logs a warning for each controller whose workqueue depth has exceeded
+// the threshold for at least the queueDepthWarningPeriod. It returns the names
+// of these controllers.
+func (m *queueDepthMonitor) check() []string {
+	depths, err := m.queueDepths()
+	if err != nil {
+		m.logger.Error(err, "Failed to gather the workqueue depth metrics.")
+		return nil
+	}
+
+	if m.exceededSince == nil {
+		m.exceededSince = map[string]time.Time{}
+	}
+
+	now := m.clock.Now()
+	var warned []string
+	for _, name := range m.controllerNames {
+		depth := depths[name]
+		if depth <= float64(m.threshold) {
+			delete(m.exceededSince, name)
+			continue
+		}
+
+		since, ok := m.exceededSince[name]
+		if !ok {
+			m.exceededSince[name] = now
+			continue
+		}
+
+		if now.Sub(since) < queueDepthWarningPeriod {
+			continue
+		}
+
+		m.logger.Info(
+			"Warning: the workqueue depth has exceeded the threshold, requests are being processed slower than they are created.",
+			"controller", name, "depth", depth, "threshold", m.threshold, "since", since,
+		)
+		m.exceededSince[name] = now // throttle the warnings
+		warned = append(warned, name)
+	}
+
+	return warned
+}
+
+// queueDepths returns the current workqueue depth for each controller name.
+func (m *queueDepthMonitor) queueDepths() (map[string]float64, error) {
+	families, err := m.gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	depths := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != workqueueDepthMetric {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "name" {
+					depths[label.GetValue()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+
+	return depths, nil
+}
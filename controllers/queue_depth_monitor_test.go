@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestQueueDepthMonitor(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: workqueueDepthMetric,
+	}, []string{"name"})
+	registry.MustRegister(depth)
+
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	monitor := &queueDepthMonitor{
+		controllerNames: []string{"certificaterequest", "certificatesigningrequest"},
+		threshold:       10,
+		gatherer:        registry,
+		clock:           fakeClock,
+		logger:          logr.Discard(),
+	}
+
+	type step struct {
+		name           string
+		advance        time.Duration
+		crDepth        float64
+		csrDepth       float64
+		expectedWarned []string
+	}
+
+	steps := []step{
+		{
+			name:     "below-threshold",
+			crDepth:  10,
+			csrDepth: 5,
+		},
+		{
+			name:     "threshold-exceeded",
+			advance:  queueDepthCheckInterval,
+			crDepth:  11,
+			csrDepth: 5,
+		},
+		{
+			name:     "threshold-exceeded-not-sustained",
+			advance:  queueDepthWarningPeriod - time.Second,
+			crDepth:  20,
+			csrDepth: 5,
+		},
+		{
+			name:           "threshold-exceeded-sustained",
+			advance:        time.Second,
+			crDepth:        20,
+			csrDepth:       5,
+			expectedWarned: []string{"certificaterequest"},
+		},
+		{
+			name:     "warning-throttled",
+			advance:  queueDepthCheckInterval,
+			crDepth:  20,
+			csrDepth: 5,
+		},
+		{
+			name:           "warning-after-throttle-period",
+			advance:        queueDepthWarningPeriod,
+			crDepth:        20,
+			csrDepth:       5,
+			expectedWarned: []string{"certificaterequest"},
+		},
+		{
+			name:     "depth-recovered",
+			advance:  queueDepthCheckInterval,
+			crDepth:  0,
+			csrDepth: 5,
+		},
+		{
+			name:     "threshold-exceeded-again",
+			advance:  queueDepthWarningPeriod,
+			crDepth:  20,
+			csrDepth: 5,
+		},
+	}
+
+	// The steps are run sequentially, because they share the monitor state.
+	for _, s := range steps {
+		fakeClock.Step(s.advance)
+		depth.WithLabelValues("certificaterequest").Set(s.crDepth)
+		depth.WithLabelValues("certificatesigningrequest").Set(s.csrDepth)
+
+		assert.Equal(t, s.expectedWarned, monitor.check(), s.name)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// checkQuota calls the quotaCheck function, if it is set. If the quota is
+// exceeded, a SetCertificateRequestConditionError is returned that sets the
+// QuotaExceeded condition. The wrapped error is a PendingError, so that the
+// request is retried until the quota check succeeds.
+func checkQuota(
+	ctx context.Context,
+	quotaCheck signer.QuotaCheck,
+	cr signer.CertificateRequestObject,
+	issuerObject v1alpha1.Issuer,
+) error {
+	if quotaCheck == nil {
+		return nil
+	}
+
+	err := quotaCheck(ctx, cr, issuerObject)
+	if err == nil {
+		return nil
+	}
+
+	// Keep the RequeueAfter duration if the function returned a PendingError.
+	pendingError := signer.PendingError{}
+	_ = errors.As(err, &pendingError)
+
+	return signer.SetCertificateRequestConditionError{
+		Err: signer.PendingError{
+			Err:          fmt.Errorf("quota exceeded: %w", err),
+			RequeueAfter: pendingError.RequeueAfter,
+		},
+		ConditionType: v1alpha1.CertificateRequestConditionQuotaExceeded,
+		Status:        cmmeta.ConditionTrue,
+		Reason:        v1alpha1.CertificateRequestConditionReasonQuotaExceeded,
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+)
+
+// maxBackoffRateLimiter is a rate limiter that caps the delays returned by the
+// wrapped rate limiter at maxBackoff.
+type maxBackoffRateLimiter struct {
+	ratelimiter.RateLimiter
+	maxBackoff time.Duration
+}
+
+var _ ratelimiter.RateLimiter = maxBackoffRateLimiter{}
+
+// newMaxBackoffRateLimiter returns the default controller rate limiter, with
+// its delays capped at maxBackoff. If maxBackoff is not positive, nil is
+// returned so that controller-runtime uses its default rate limiter.
+func newMaxBackoffRateLimiter(maxBackoff time.Duration) ratelimiter.RateLimiter {
+	if maxBackoff <= 0 {
+		return nil
+	}
+
+	return maxBackoffRateLimiter{
+		RateLimiter: workqueue.DefaultControllerRateLimiter(),
+		maxBackoff:  maxBackoff,
+	}
+}
+
+func (r maxBackoffRateLimiter) When(item interface{}) time.Duration {
+	if delay := r.RateLimiter.When(item); delay < r.maxBackoff {
+		return delay
+	}
+	return r.maxBackoff
+}
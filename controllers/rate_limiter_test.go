@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxBackoffRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, newMaxBackoffRateLimiter(0))
+
+	maxBackoff := 30 * time.Second
+	rateLimiter := newMaxBackoffRateLimiter(maxBackoff)
+	require.NotNil(t, rateLimiter)
+
+	// The default rate limiter backs off exponentially up to 1000 seconds,
+	// which it reaches after ~28 failures.
+	var delay time.Duration
+	for i := 0; i < 40; i++ {
+		delay = rateLimiter.When("item")
+		assert.LessOrEqual(t, delay, maxBackoff, "failure %d", i)
+	}
+	assert.Equal(t, maxBackoff, delay)
+	assert.Equal(t, 40, rateLimiter.NumRequeues("item"))
+
+	// Forgetting the item resets its backoff.
+	rateLimiter.Forget("item")
+	assert.Less(t, rateLimiter.When("item"), maxBackoff)
+}
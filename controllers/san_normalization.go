@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// normalizeSANs applies the DeduplicateSANs and RejectConflictingSANs options
+// to a request before it is signed. It returns the request that has to be
+// passed to the signer and the duplicate SANs that were removed from it. If
+// conflicting SANs are rejected and the request has any, a PermanentError
+// wrapping a ValidationError is returned.
+func normalizeSANs(
+	cr signer.CertificateRequestObject,
+	deduplicate bool,
+	rejectConflicting bool,
+) (signer.CertificateRequestObject, []string, error) {
+	if !deduplicate && !rejectConflicting {
+		return cr, nil, nil
+	}
+
+	template, _, _, err := cr.GetRequest()
+	if err != nil {
+		return cr, nil, signer.PermanentError{Err: fmt.Errorf("failed to parse request: %w", err)}
+	}
+
+	if rejectConflicting {
+		if violations := signer.ConflictingSANs(template); len(violations) > 0 {
+			return cr, nil, signer.PermanentError{Err: signer.ValidationError{Violations: violations}}
+		}
+	}
+
+	if !deduplicate {
+		return cr, nil, nil
+	}
+
+	removed := signer.DeduplicateSANs(template)
+	if len(removed) == 0 {
+		return cr, nil, nil
+	}
+
+	return signer.WithDeduplicatedSANs(cr), removed, nil
+}
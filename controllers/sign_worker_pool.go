@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// SignWorkerPool configures a bounded pool of workers that call the Sign
+// function. This keeps the memory usage predictable for CAs that return large
+// responses, because at most Size Sign calls run concurrently and at most
+// QueueDepth requests wait for a free worker. Requests that arrive while the
+// queue is full are rejected with a retryable error, which counts towards the
+// MaxRetryDuration like any other Sign error.
+//
+// Each controller calls Sign from at most MaxConcurrentReconciles reconciles
+// at the same time (1 by default, it can be changed using the
+// PreSetupWithManager hook). The pool is shared by the CertificateRequest and
+// Kubernetes CSR controllers, so requests are only queued or rejected if the
+// sum of their MaxConcurrentReconciles is larger than Size, or larger than
+// Size+QueueDepth respectively.
+type SignWorkerPool struct {
+	// Size is the maximum number of concurrent Sign calls. If zero, the
+	// number of concurrent Sign calls is not limited. It must not be negative.
+	Size int
+
+	// QueueDepth is the maximum number of requests that wait for a free worker.
+	// It must not be negative.
+	QueueDepth int
+}
+
+// signWorkerPool limits the number of concurrent and waiting Sign calls using
+// two semaphores: a request first takes a place in the pool (a worker or a
+// place in the queue) and then waits for a worker.
+type signWorkerPool struct {
+	places  chan struct{}
+	workers chan struct{}
+}
+
+func newSignWorkerPool(config SignWorkerPool) *signWorkerPool {
+	return &signWorkerPool{
+		places:  make(chan struct{}, config.Size+config.QueueDepth),
+		workers: make(chan struct{}, config.Size),
+	}
+}
+
+// wrap returns a Sign function that calls sign using a worker of the pool.
+// If all workers are busy and the queue is full, a retryable error is
+// returned, so that the request is retried with backoff until the
+// MaxRetryDuration has passed.
+func (p *signWorkerPool) wrap(sign signer.Sign) signer.Sign {
+	return func(ctx context.Context, cr signer.CertificateRequestObject, issuerObject v1alpha1.Issuer) (signer.PEMBundle, error) {
+		select {
+		case p.places <- struct{}{}:
+			defer func() { <-p.places }()
+		default:
+			return signer.PEMBundle{}, fmt.Errorf("the sign worker pool is full (%d workers, queue depth %d)", cap(p.workers), cap(p.places)-cap(p.workers))
+		}
+
+		select {
+		case p.workers <- struct{}{}:
+			defer func() { <-p.workers }()
+		case <-ctx.Done():
+			return signer.PEMBundle{}, fmt.Errorf("waiting for a sign worker: %w", ctx.Err())
+		}
+
+		return sign(ctx, cr, issuerObject)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+)
+
+func TestSignWorkerPool(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	blockingSign := func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+		started <- struct{}{}
+		<-release
+		return signer.PEMBundle{ChainPEM: []byte("a-signed-certificate")}, nil
+	}
+
+	pool := newSignWorkerPool(SignWorkerPool{Size: 1, QueueDepth: 1})
+	sign := pool.wrap(blockingSign)
+
+	type signResult struct {
+		bundle signer.PEMBundle
+		err    error
+	}
+	startSign := func() <-chan signResult {
+		results := make(chan signResult, 1)
+		go func() {
+			bundle, err := sign(context.TODO(), nil, nil)
+			results <- signResult{bundle, err}
+		}()
+		return results
+	}
+
+	// The first request takes the only worker.
+	first := startSign()
+	<-started
+
+	// The second request waits in the queue.
+	second := startSign()
+	require.Eventually(t, func() bool {
+		return len(pool.places) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Additional requests are rejected with a retryable error while the pool
+	// is saturated.
+	for i := 0; i < 3; i++ {
+		_, err := sign(context.TODO(), nil, nil)
+		(*errormatch.ErrorContains("the sign worker pool is full (1 workers, queue depth 1)"))(t, err)
+		assert.False(t, errors.As(err, &signer.PendingError{}))
+	}
+
+	select {
+	case <-started:
+		t.Fatal("the queued request should not have been started")
+	default:
+	}
+
+	// Once the first request is done, the queued request is signed.
+	release <- struct{}{}
+	require.NoError(t, (<-first).err)
+	<-started
+	release <- struct{}{}
+	result := <-second
+	require.NoError(t, result.err)
+	assert.Equal(t, []byte("a-signed-certificate"), result.bundle.ChainPEM)
+
+	// The pool is empty again.
+	assert.Len(t, pool.places, 0)
+	assert.Len(t, pool.workers, 0)
+}
+
+func TestSignWorkerPoolContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	defer close(release)
+	blockingSign := func(_ context.Context, _ signer.CertificateRequestObject, _ v1alpha1.Issuer) (signer.PEMBundle, error) {
+		<-release
+		return signer.PEMBundle{}, nil
+	}
+
+	pool := newSignWorkerPool(SignWorkerPool{Size: 1, QueueDepth: 1})
+	sign := pool.wrap(blockingSign)
+
+	// Take the only worker.
+	go func() {
+		_, _ = sign(context.TODO(), nil, nil)
+	}()
+	require.Eventually(t, func() bool {
+		return len(pool.workers) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A queued request stops waiting when its context is cancelled.
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	_, err := sign(ctx, nil, nil)
+	(*errormatch.ErrorContains("waiting for a sign worker: context deadline exceeded"))(t, err)
+	assert.Len(t, pool.places, 1)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"sync"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+)
+
+// CheckCondition is an additional issuer condition that is reported by the
+// Check function, eg. to report whether the CA endpoint is reachable or the
+// credentials are valid. The Type must be different from the Ready condition
+// type and from the types of the other reported conditions.
+//
+// If Gating is true, the condition is a dependency of the issuer: the Ready
+// condition is computed as the AND of the gating conditions and the result of
+// the Check function. So if Check succeeds, but a gating condition does not
+// have the True status, the issuer is not Ready and is checked again with
+// backoff. Non-gating conditions are informational and don't affect Ready.
+type CheckCondition struct {
+	Type    cmapi.IssuerConditionType
+	Status  cmmeta.ConditionStatus
+	Reason  string
+	Message string
+	Gating  bool
+}
+
+type checkConditionsContextKey struct{}
+
+type checkConditionsCollector struct {
+	mu         sync.Mutex
+	conditions []CheckCondition
+}
+
+// ContextWithCheckConditions returns a copy of ctx in which the Check function
+// can report additional conditions using ReportCheckConditions. The returned
+// function returns the conditions that were reported so far. The issuer
+// controller uses this function to collect the conditions of each Check call.
+func ContextWithCheckConditions(ctx context.Context) (context.Context, func() []CheckCondition) {
+	collector := &checkConditionsCollector{}
+	return context.WithValue(ctx, checkConditionsContextKey{}, collector), func() []CheckCondition {
+		collector.mu.Lock()
+		defer collector.mu.Unlock()
+		return append([]CheckCondition(nil), collector.conditions...)
+	}
+}
+
+// ReportCheckConditions reports additional conditions from the Check function.
+// The issuer controller sets these conditions on the issuer status, in addition
+// to the Ready condition, both if Check succeeds and if it returns an error.
+// The status is applied using server-side apply, so reported conditions that are
+// no longer reported by a later Check call are removed from the status.
+// If a condition with the same type is reported more than once, the last one
+// is used. If ctx was not created by the issuer controller, this is a no-op.
+func ReportCheckConditions(ctx context.Context, conditions ...CheckCondition) {
+	collector, ok := ctx.Value(checkConditionsContextKey{}).(*checkConditionsCollector)
+	if !ok {
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.conditions = append(collector.conditions, conditions...)
+}
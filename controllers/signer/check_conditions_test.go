@@ -0,0 +1,42 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"testing"
+
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportCheckConditions(t *testing.T) {
+	t.Parallel()
+
+	reachable := CheckCondition{Type: "EndpointReachable", Status: cmmeta.ConditionTrue, Reason: "Reachable", Message: "reachable"}
+	authValid := CheckCondition{Type: "AuthValid", Status: cmmeta.ConditionFalse, Reason: "Expired", Message: "expired"}
+
+	// Reporting conditions without a collector in the context is a no-op.
+	ReportCheckConditions(context.Background(), reachable)
+
+	ctx, reported := ContextWithCheckConditions(context.Background())
+	assert.Empty(t, reported())
+
+	ReportCheckConditions(ctx, reachable)
+	ReportCheckConditions(ctx, authValid)
+	assert.Equal(t, []CheckCondition{reachable, authValid}, reported())
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type checkRequeueContextKey struct{}
+
+type checkRequeueCollector struct {
+	mu           sync.Mutex
+	requeueAfter time.Duration
+}
+
+// ContextWithCheckRequeue returns a copy of ctx in which the Check function can
+// request the issuer to be checked again using RequeueCheckAfter. The returned
+// function returns the requested duration, or zero if none was requested. The
+// issuer controller uses this function for each Check call.
+func ContextWithCheckRequeue(ctx context.Context) (context.Context, func() time.Duration) {
+	collector := &checkRequeueCollector{}
+	return context.WithValue(ctx, checkRequeueContextKey{}, collector), func() time.Duration {
+		collector.mu.Lock()
+		defer collector.mu.Unlock()
+		return collector.requeueAfter
+	}
+}
+
+// RequeueCheckAfter requests the issuer controller to check the issuer again
+// after the given duration, eg. ahead of the expiry of the intermediate
+// certificate that the issuer signs with. The duration is only used if Check
+// succeeds; if Check returns an error, the issuer is retried with backoff.
+// If it is called more than once, the shortest positive duration is used.
+// If ctx was not created by the issuer controller, this is a no-op.
+func RequeueCheckAfter(ctx context.Context, requeueAfter time.Duration) {
+	collector, ok := ctx.Value(checkRequeueContextKey{}).(*checkRequeueCollector)
+	if !ok || requeueAfter <= 0 {
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if collector.requeueAfter == 0 || requeueAfter < collector.requeueAfter {
+		collector.requeueAfter = requeueAfter
+	}
+}
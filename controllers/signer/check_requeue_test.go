@@ -0,0 +1,42 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequeueCheckAfter(t *testing.T) {
+	t.Parallel()
+
+	// Requesting a requeue without a collector in the context is a no-op.
+	RequeueCheckAfter(context.Background(), time.Hour)
+
+	ctx, requeueAfter := ContextWithCheckRequeue(context.Background())
+	assert.Zero(t, requeueAfter())
+
+	RequeueCheckAfter(ctx, 2*time.Hour)
+	RequeueCheckAfter(ctx, time.Hour)
+	RequeueCheckAfter(ctx, 3*time.Hour)
+	RequeueCheckAfter(ctx, 0)
+	RequeueCheckAfter(ctx, -time.Minute)
+	assert.Equal(t, time.Hour, requeueAfter())
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type httpClientContextKey struct{}
+
+type apiReaderContextKey struct{}
+
+// ContextWithHTTPClient returns a copy of ctx that carries the HTTP client.
+// The controllers use this function to pass the HTTP client returned by their
+// HTTPClientProvider to the Check, Sign and AfterSign functions.
+func ContextWithHTTPClient(ctx context.Context, client *http.Client) context.Context {
+	return context.WithValue(ctx, httpClientContextKey{}, client)
+}
+
+// HTTPClientFromContext returns the pre-configured HTTP client (eg. with the
+// proxy, TLS trust and timeout settings of the deployment) that should be used
+// to connect to the CA. If the controller has no HTTPClientProvider configured,
+// http.DefaultClient is returned.
+func HTTPClientFromContext(ctx context.Context) *http.Client {
+	if client, ok := ctx.Value(httpClientContextKey{}).(*http.Client); ok && client != nil {
+		return client
+	}
+	return http.DefaultClient
+}
+
+// ContextWithAPIReader returns a copy of ctx that carries the Kubernetes API
+// reader. The controllers use this function to pass their APIReader to the
+// Check, Sign and AfterSign functions.
+func ContextWithAPIReader(ctx context.Context, reader client.Reader) context.Context {
+	return context.WithValue(ctx, apiReaderContextKey{}, reader)
+}
+
+// APIReaderFromContext returns the reader that can be used to read arbitrary
+// Kubernetes objects (eg. a CA certificate stored in a ConfigMap) using the
+// credentials of the controller, so the signer doesn't have to build its own
+// client. The CombinedController uses the manager's API reader, which is not
+// cached: every call is sent to the API server, so no informers are started
+// for the types that are read and only "get" (and "list") permissions are
+// needed. Signers that read objects on every call should keep this in mind.
+// If the controller has no APIReader configured, nil is returned.
+func APIReaderFromContext(ctx context.Context) client.Reader {
+	if reader, ok := ctx.Value(apiReaderContextKey{}).(client.Reader); ok && reader != nil {
+		return reader
+	}
+	return nil
+}
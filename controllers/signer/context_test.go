@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHTTPClientFromContext(t *testing.T) {
+	t.Parallel()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	assert.Same(t, http.DefaultClient, HTTPClientFromContext(context.Background()))
+	assert.Same(t, http.DefaultClient, HTTPClientFromContext(ContextWithHTTPClient(context.Background(), nil)))
+	assert.Same(t, client, HTTPClientFromContext(ContextWithHTTPClient(context.Background(), client)))
+}
+
+func TestAPIReaderFromContext(t *testing.T) {
+	t.Parallel()
+
+	reader := fake.NewClientBuilder().Build()
+
+	assert.Nil(t, APIReaderFromContext(context.Background()))
+	assert.Nil(t, APIReaderFromContext(ContextWithAPIReader(context.Background(), nil)))
+	assert.Same(t, reader, APIReaderFromContext(ContextWithAPIReader(context.Background(), reader)))
+}
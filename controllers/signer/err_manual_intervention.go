@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+// ManualInterventionError is returned if the request can only be signed after
+// an operator has looked at it, for example when the CA flagged the request
+// for a manual review. The request is parked: the NeedsManualReview condition
+// is set to True with the provided Reason and Message, and the request is not
+// retried automatically.
+//
+// Once the issue has been resolved, the operator can resume the request by
+// setting the "issuer-lib.cert-manager.io/resume" annotation to a new value
+// (eg. the current time). The Sign function is then called again.
+//
+// This error is only supported for CertificateRequest resources, the
+// CertificateSigningRequest controller treats it as a retryable error.
+//
+// > This error should be returned by the Sign function.
+type ManualInterventionError struct {
+	// Reason is the reason of the NeedsManualReview condition, it should be
+	// a CamelCase string.
+	Reason string
+
+	// Message is the message of the NeedsManualReview condition, it should
+	// explain what the operator has to do before resuming the request.
+	Message string
+}
+
+var _ error = ManualInterventionError{}
+
+func (ve ManualInterventionError) Error() string {
+	return ve.Message
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package signer
 
+import "time"
+
 // PendingError should be returned if we are certain that we will converge to a
 // successful result or another type of error in a finite amount of time by
 // just retrying the same operation.
@@ -25,9 +27,17 @@ package signer
 // answer from an external service that is indicating that the request is still
 // being processed.
 //
+// The optional RequeueAfter field can be set to control when the request is
+// reconciled again (eg. when the external service indicates when it should be
+// polled again), instead of requeueing the request with exponential backoff.
+//
 // > This error should be returned only by the Sign function.
 type PendingError struct {
 	Err error
+
+	// RequeueAfter is the duration after which the request is reconciled again.
+	// If zero, the request is requeued using exponential backoff.
+	RequeueAfter time.Duration
 }
 
 var _ error = PendingError{}
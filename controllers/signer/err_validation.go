@@ -0,0 +1,41 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError contains all the violations that were found while validating
+// a request (eg. against the name constraints of the issuer). All violations
+// are reported together, so that they can be fixed at once instead of one at a
+// time. It is returned wrapped in a PermanentError, because the request has to
+// be changed to fix the violations.
+type ValidationError struct {
+	Violations []string
+}
+
+var _ error = ValidationError{}
+
+func (ve ValidationError) Error() string {
+	if len(ve.Violations) == 1 {
+		return ve.Violations[0]
+	}
+
+	return fmt.Sprintf("%d validation errors: %s", len(ve.Violations), strings.Join(ve.Violations, "; "))
+}
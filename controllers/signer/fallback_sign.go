@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+)
+
+const (
+	// SignedByPrimary is the value of the v1alpha1.SignedByAnnotation if the
+	// certificate was signed by the primary Sign function of FallbackSign.
+	SignedByPrimary = "primary"
+	// SignedBySecondary is the value of the v1alpha1.SignedByAnnotation if the
+	// certificate was signed by the secondary Sign function of FallbackSign.
+	SignedBySecondary = "secondary"
+)
+
+// FallbackSign returns a Sign function that signs the request using primary
+// and, if primary returns a retryable error (eg. because the primary CA is
+// unavailable), using secondary (eg. a backup CA). The Sign function that was
+// used is recorded in the v1alpha1.SignedByAnnotation of the request.
+// If primary returns a PermanentError, the request can't be signed by any CA,
+// so secondary is not called and the error is returned as is. The same applies
+// to a PendingError, because the primary CA is still processing the request.
+// If secondary fails too, its error is returned (wrapped), so that its type
+// determines how the request is retried.
+func FallbackSign(primary Sign, secondary Sign) Sign {
+	return func(ctx context.Context, cr CertificateRequestObject, issuerObject v1alpha1.Issuer) (PEMBundle, error) {
+		bundle, primaryErr := primary(ctx, cr, issuerObject)
+		if primaryErr == nil {
+			return withSignedByAnnotation(bundle, SignedByPrimary), nil
+		}
+
+		if errors.As(primaryErr, &PermanentError{}) || errors.As(primaryErr, &PendingError{}) {
+			return bundle, primaryErr
+		}
+
+		bundle, secondaryErr := secondary(ctx, cr, issuerObject)
+		if secondaryErr != nil {
+			return bundle, fmt.Errorf("primary failed: %v, secondary failed: %w", primaryErr, secondaryErr)
+		}
+
+		return withSignedByAnnotation(bundle, SignedBySecondary), nil
+	}
+}
+
+func withSignedByAnnotation(bundle PEMBundle, signedBy string) PEMBundle {
+	annotations := make(map[string]string, len(bundle.Annotations)+1)
+	for key, value := range bundle.Annotations {
+		annotations[key] = value
+	}
+	annotations[v1alpha1.SignedByAnnotation] = signedBy
+	bundle.Annotations = annotations
+	return bundle
+}
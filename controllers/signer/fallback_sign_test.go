@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+)
+
+func TestFallbackSign(t *testing.T) {
+	t.Parallel()
+
+	signing := func(chain string) Sign {
+		return func(_ context.Context, _ CertificateRequestObject, _ v1alpha1.Issuer) (PEMBundle, error) {
+			return PEMBundle{
+				ChainPEM:    []byte(chain),
+				Annotations: map[string]string{"example.com/ca": chain},
+			}, nil
+		}
+	}
+	failing := func(err error) Sign {
+		return func(_ context.Context, _ CertificateRequestObject, _ v1alpha1.Issuer) (PEMBundle, error) {
+			return PEMBundle{}, err
+		}
+	}
+
+	type testCase struct {
+		name                  string
+		primary               Sign
+		secondary             Sign
+		expectedBundle        PEMBundle
+		validateError         *errormatch.Matcher
+		expectedPermanent     bool
+		expectSecondaryCalled bool
+	}
+
+	tests := []testCase{
+		{
+			name:      "primary-success",
+			primary:   signing("primary-chain"),
+			secondary: signing("secondary-chain"),
+			expectedBundle: PEMBundle{
+				ChainPEM: []byte("primary-chain"),
+				Annotations: map[string]string{
+					"example.com/ca":            "primary-chain",
+					v1alpha1.SignedByAnnotation: SignedByPrimary,
+				},
+			},
+		},
+		{
+			name:      "primary-failure-secondary-success",
+			primary:   failing(fmt.Errorf("[primary unavailable]")),
+			secondary: signing("secondary-chain"),
+			expectedBundle: PEMBundle{
+				ChainPEM: []byte("secondary-chain"),
+				Annotations: map[string]string{
+					"example.com/ca":            "secondary-chain",
+					v1alpha1.SignedByAnnotation: SignedBySecondary,
+				},
+			},
+			expectSecondaryCalled: true,
+		},
+		{
+			name:                  "primary-failure-secondary-failure",
+			primary:               failing(fmt.Errorf("[primary unavailable]")),
+			secondary:             failing(PermanentError{Err: fmt.Errorf("[secondary rejected]")}),
+			validateError:         errormatch.ErrorContains("primary failed: [primary unavailable], secondary failed: [secondary rejected]"),
+			expectedPermanent:     true,
+			expectSecondaryCalled: true,
+		},
+		{
+			name:              "primary-permanent-error",
+			primary:           failing(PermanentError{Err: fmt.Errorf("[primary rejected]")}),
+			secondary:         signing("secondary-chain"),
+			validateError:     errormatch.ErrorContains("[primary rejected]"),
+			expectedPermanent: true,
+		},
+		{
+			name:          "primary-pending-error",
+			primary:       failing(PendingError{Err: fmt.Errorf("[primary processing]")}),
+			secondary:     signing("secondary-chain"),
+			validateError: errormatch.ErrorContains("[primary processing]"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			secondaryCalled := false
+			secondary := func(ctx context.Context, cr CertificateRequestObject, issuerObject v1alpha1.Issuer) (PEMBundle, error) {
+				secondaryCalled = true
+				return tc.secondary(ctx, cr, issuerObject)
+			}
+
+			bundle, err := FallbackSign(tc.primary, secondary)(context.TODO(), nil, nil)
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+			assert.Equal(t, tc.expectedPermanent, errors.As(err, &PermanentError{}))
+			assert.Equal(t, tc.expectSecondaryCalled, secondaryCalled)
+			if err == nil {
+				assert.Equal(t, tc.expectedBundle, bundle)
+			}
+		})
+	}
+}
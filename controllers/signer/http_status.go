@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxHTTPErrorBodyLength is the maximum number of bytes of the response body
+// that are included in the error returned by FromHTTPStatus, to keep the
+// condition messages and events readable.
+const maxHTTPErrorBodyLength = 256
+
+// FromHTTPStatus maps the status code of a response from a backend API to the
+// error type that the Sign and Check functions should return:
+//   - 2xx responses return nil, except 202 (Accepted) which returns a
+//     PendingError because the backend has not finished processing the request;
+//   - 408 (Request Timeout), 425 (Too Early), 429 (Too Many Requests) and 5xx
+//     responses return a normal error, the request will be retried;
+//   - all other 4xx responses (eg. authentication and validation failures)
+//     return a PermanentError, retrying the same request won't help;
+//   - all other responses return a normal error.
+//
+// The body is included in the error message, truncated if it is too long.
+func FromHTTPStatus(code int, body string) error {
+	if code >= 200 && code < 300 && code != http.StatusAccepted {
+		return nil
+	}
+
+	err := httpStatusError(code, body)
+
+	switch {
+	case code == http.StatusAccepted:
+		return PendingError{Err: err}
+	case code == http.StatusRequestTimeout,
+		code == http.StatusTooEarly,
+		code == http.StatusTooManyRequests:
+		return err
+	case code >= 400 && code < 500:
+		return PermanentError{Err: err}
+	default:
+		return err
+	}
+}
+
+func httpStatusError(code int, body string) error {
+	status := fmt.Sprintf("%d", code)
+	if text := http.StatusText(code); text != "" {
+		status = fmt.Sprintf("%d %s", code, text)
+	}
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return fmt.Errorf("backend responded with HTTP status %s", status)
+	}
+	if len(body) > maxHTTPErrorBodyLength {
+		body = body[:maxHTTPErrorBodyLength] + "..."
+	}
+	return fmt.Errorf("backend responded with HTTP status %s: %s", status, body)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromHTTPStatus(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name              string
+		code              int
+		body              string
+		expectedNil       bool
+		expectedPending   bool
+		expectedPermanent bool
+		expectedMessage   string
+	}
+
+	tests := []testCase{
+		{
+			name:        "ok",
+			code:        200,
+			body:        "a-certificate",
+			expectedNil: true,
+		},
+		{
+			name:        "created",
+			code:        201,
+			expectedNil: true,
+		},
+		{
+			name:            "accepted",
+			code:            202,
+			body:            "order 42 is being processed",
+			expectedPending: true,
+			expectedMessage: "backend responded with HTTP status 202 Accepted: order 42 is being processed",
+		},
+		{
+			name:              "bad-request",
+			code:              400,
+			body:              "invalid CSR\n",
+			expectedPermanent: true,
+			expectedMessage:   "backend responded with HTTP status 400 Bad Request: invalid CSR",
+		},
+		{
+			name:              "unauthorized",
+			code:              401,
+			expectedPermanent: true,
+			expectedMessage:   "backend responded with HTTP status 401 Unauthorized",
+		},
+		{
+			name:              "forbidden",
+			code:              403,
+			body:              "not allowed",
+			expectedPermanent: true,
+			expectedMessage:   "backend responded with HTTP status 403 Forbidden: not allowed",
+		},
+		{
+			name:              "unprocessable-entity",
+			code:              422,
+			body:              "unknown profile",
+			expectedPermanent: true,
+			expectedMessage:   "backend responded with HTTP status 422 Unprocessable Entity: unknown profile",
+		},
+		{
+			name:            "request-timeout",
+			code:            408,
+			expectedMessage: "backend responded with HTTP status 408 Request Timeout",
+		},
+		{
+			name:            "too-many-requests",
+			code:            429,
+			body:            "slow down",
+			expectedMessage: "backend responded with HTTP status 429 Too Many Requests: slow down",
+		},
+		{
+			name:            "internal-server-error",
+			code:            500,
+			body:            "oops",
+			expectedMessage: "backend responded with HTTP status 500 Internal Server Error: oops",
+		},
+		{
+			name:            "service-unavailable",
+			code:            503,
+			expectedMessage: "backend responded with HTTP status 503 Service Unavailable",
+		},
+		{
+			name:            "unknown-status",
+			code:            599,
+			expectedMessage: "backend responded with HTTP status 599",
+		},
+		{
+			name:              "long-body-is-truncated",
+			code:              400,
+			body:              strings.Repeat("a", 300),
+			expectedPermanent: true,
+			expectedMessage:   "backend responded with HTTP status 400 Bad Request: " + strings.Repeat("a", 256) + "...",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := FromHTTPStatus(tc.code, tc.body)
+			if tc.expectedNil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+
+			assert.Equal(t, tc.expectedMessage, err.Error())
+			assert.Equal(t, tc.expectedPending, errors.As(err, &PendingError{}))
+			assert.Equal(t, tc.expectedPermanent, errors.As(err, &PermanentError{}))
+		})
+	}
+}
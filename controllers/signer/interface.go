@@ -22,7 +22,6 @@ import (
 	"time"
 
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
-	"github.com/cert-manager/cert-manager/pkg/util/pki"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -38,11 +37,106 @@ import (
 // option is enabled in the controller. This option is for backwards compatibility
 // only. The use of the CA field and the ca.crt field in the resulting Secret is
 // discouraged, instead the CA should be provisioned separately (e.g. using trust-manager).
-type PEMBundle pki.PEMBundle
+// The optional Annotations are added to the CertificateRequest or Kubernetes
+// CSR resource once the certificate is issued. This can be used to pass
+// information about the issued certificate (eg. that it is a one-time
+// certificate that should not be renewed early) to downstream tooling that
+// watches these resources. Existing annotations are never removed, and
+// annotations with the "cert-manager.io/" prefix are ignored so that the
+// annotations used by cert-manager (eg. for renewal) are never overwritten.
+// The optional NotAfter is the expiry time of the leaf certificate. It only has
+// to be set if the ChainPEM can't be parsed by issuer-lib (eg. because the CA
+// returns an opaque token), see the CertificateNotAfter method.
+// The optional AdditionalArtifacts are issued alongside the certificate (eg. a
+// TLS delegated credential). They are stored base64 encoded in annotations on
+// the CertificateRequest or Kubernetes CSR resource, see the
+// v1alpha1.ArtifactAnnotationPrefix. The artifact names must be valid
+// annotation names, and the artifacts must be small enough to fit in the
+// annotations of the resource.
+// The optional Warnings describe caveats of a successful signing (eg. that the
+// CA clamped the requested duration, or that a deprecated profile was used).
+// Each warning is emitted as a Warning event on the CertificateRequest or
+// Kubernetes CSR resource, the request is still marked as issued.
+// The optional RenewalTime is the renewal time recommended by the CA. It is
+// stored in the v1alpha1.RenewalTimeAnnotation annotation on the
+// CertificateRequest or Kubernetes CSR resource, so that external automation
+// can renew the certificate on time (Kubernetes CSRs are not renewed by
+// cert-manager).
+type PEMBundle struct {
+	ChainPEM            []byte
+	CAPEM               []byte
+	Annotations         map[string]string
+	NotAfter            time.Time
+	AdditionalArtifacts map[string][]byte
+	Warnings            []string
+	RenewalTime         time.Time
+}
 
 type Sign func(ctx context.Context, cr CertificateRequestObject, issuerObject v1alpha1.Issuer) (PEMBundle, error)
+
+// Check is used by the issuer controller to check the health of an issuer, the
+// result is reflected by the Ready condition of the issuer. Additional
+// diagnostic conditions (eg. whether the CA endpoint is reachable) can be
+// reported using ReportCheckConditions, gating conditions are also taken into
+// account to compute the Ready condition. To check the issuer again after a
+// certain duration (eg. ahead of the expiry of its intermediate certificate),
+// use RequeueCheckAfter.
 type Check func(ctx context.Context, issuerObject v1alpha1.Issuer) error
 
+// AfterSign is an optional function that is called after the Sign function
+// succeeded and before the CertificateRequest is marked as Ready. It can be
+// used to eg. publish the issued certificate to an external inventory.
+// If the function returns an error, the signed certificate is stored in the
+// CertificateRequest status without marking it as Ready, and AfterSign is
+// retried with backoff using the stored certificate. The certificate is not
+// signed again. On retries, the CAPEM field of the bundle is only set if the
+// SetCAOnCertificateRequest option is enabled. Like Sign errors, AfterSign
+// errors are retried until the MaxRetryDuration has passed (or are permanent,
+// see PermanentError), after which the CertificateRequest is marked as Failed
+// and the certificate is removed from its status.
+// For Kubernetes CSRs, the certificate is considered issued as soon as it is
+// stored in the status. An AfterSign error sets the AfterSignPending condition
+// and AfterSign is retried using the stored certificate, but the certificate
+// is kept in the status once the retries stop.
+type AfterSign func(ctx context.Context, cr CertificateRequestObject, issuerObject v1alpha1.Issuer, bundle PEMBundle) error
+
+// ApproveCertificateRequest is an optional function that can be used to approve
+// or deny cert-manager CertificateRequests that have not been Approved or Denied
+// yet, instead of relying on an external approval controller. It is only called
+// for CertificateRequests that reference one of the controller's issuer types,
+// once the referenced issuer exists. If approve is true, the Approved condition
+// is set, otherwise the Denied condition is set. The reason and message are
+// used for that condition. If an error is returned, the function is retried with
+// backoff. The function is never called for Kubernetes CSRs.
+// IMPORTANT: the controller needs "approve" permissions for the "signers"
+// resource in the "cert-manager.io" API group to update the approval conditions.
+type ApproveCertificateRequest func(ctx context.Context, cr CertificateRequestObject, issuerObject v1alpha1.Issuer) (approve bool, reason, message string, err error)
+
+// GetNameConstraints is an optional function that returns the name constraints
+// of an issuer. The constraints are validated before calling the Sign function,
+// a request with a subjectAltName that is not allowed is failed permanently.
+// If nil is returned, the names are not constrained. If an error is returned,
+// the request is retried with backoff.
+type GetNameConstraints func(ctx context.Context, issuerObject v1alpha1.Issuer) (*NameConstraints, error)
+
+// QuotaCheck is an optional function that is called before the Sign function
+// to enforce a quota, eg. a maximum number of certificates that a namespace
+// can get from an issuer per day. The counting is implemented by the function.
+// If the quota is exceeded, an error must be returned. The QuotaExceeded
+// condition is then set on the request and the request is retried, without
+// being failed once the MaxRetryDuration is exceeded. To control when the
+// request is retried (eg. at the start of the next quota window), return a
+// PendingError with a RequeueAfter duration.
+type QuotaCheck func(ctx context.Context, cr CertificateRequestObject, issuerObject v1alpha1.Issuer) error
+
+// DefaultUsages is an optional function that returns the key usages and
+// extended key usages that an issuer applies to CertificateRequests that don't
+// specify any usages (neither in spec.usages nor encoded in the CSR), instead of
+// the cert-manager defaults ("digital signature" and "key encipherment").
+// The returned usages are reflected by the template returned by GetRequest.
+// Tests can call the same function to know which usages to expect.
+type DefaultUsages func(issuerObject v1alpha1.Issuer) (x509.KeyUsage, []x509.ExtKeyUsage)
+
 // CertificateRequestObject is an interface that represents either a
 // cert-manager CertificateRequest or a Kubernetes CertificateSigningRequest
 // resource. This interface hides the spec fields of the underlying resource
@@ -51,16 +145,58 @@ type Check func(ctx context.Context, issuerObject v1alpha1.Issuer) error
 // agnostic of the way the spec fields should be interpreted, such as the
 // defaulting logic that is applied to it. It is still possible to access the
 // labels and annotations of the underlying resource or any other metadata
-// fields that might be useful to the signer. Also, the signer can use the
+// fields that might be useful to the signer. cert-manager copies the labels and
+// annotations of a Certificate to the CertificateRequests that it creates for
+// it (filtered by its --copied-annotation-prefixes flag), also on renewal. Also, the signer can use the
 // GetConditions method to retrieve the conditions of the underlying resource.
 // To update the conditions, the special error "SetCertificateRequestConditionError"
 // can be returned from the Sign method.
 type CertificateRequestObject interface {
 	metav1.Object
 
+	// GetRequest returns a certificate template that is generated from the CSR,
+	// the requested duration and the raw PEM encoded CSR. The subject of the
+	// template contains all the subject RDNs of the CSR (eg. the organizations,
+	// countries and organizational units set in the spec.subject field of a
+	// cert-manager Certificate), both as parsed fields and as RawSubject.
+	// The RawSubject is the DER encoded subject of the CSR as is, so a literal
+	// subject (the spec.literalSubject field of a cert-manager Certificate,
+	// an RFC 4514 DN) keeps the exact order of its RDNs. x509.CreateCertificate
+	// uses the RawSubject instead of the Subject if it is set, so a Sign
+	// function that changes the Subject must clear the RawSubject.
+	// The key usages of the template are taken from the spec.usages field of
+	// the request resource (cert-manager copies them from the Certificate).
+	// If a CertificateRequest has no spec.usages, the key usages encoded in the
+	// CSR extensions are used, and if the CSR has none either, the defaults
+	// ("digital signature" and "key encipherment", or the DefaultUsages of the
+	// issuer) are used.
 	GetRequest() (template *x509.Certificate, duration time.Duration, csr []byte, err error)
 
+	// GetPublicKeyAlgorithm returns the algorithm and size in bits of the public
+	// key in the CSR. For ECDSA keys, the size is the bit size of the curve.
+	GetPublicKeyAlgorithm() (algorithm x509.PublicKeyAlgorithm, keyBits int, err error)
+
+	// GetCommonName returns the common name of the subject of the CSR, or an
+	// empty string if the CSR has no common name. The common name is not derived
+	// from the subjectAltNames, a request with only subjectAltNames (as is
+	// recommended) has an empty common name. If the CSR can't be parsed, an
+	// empty string is returned too; GetRequest returns the parse error.
+	GetCommonName() string
+
+	// GetX509CertificateRequest returns the parsed CSR, which gives access to
+	// the attributes and extensions of the CSR that are not reflected by the
+	// template returned by GetRequest (eg. custom requested extensions). The
+	// CSR is parsed on every call, so the returned object can be modified.
+	// Its signature is not checked, use CheckSignature to verify it.
+	GetX509CertificateRequest() (*x509.CertificateRequest, error)
+
 	GetConditions() []cmapi.CertificateRequestCondition
+
+	// GetRequestingIdentity returns the identity of the user that created the
+	// request, as recorded by the API server in the spec.username, spec.uid,
+	// spec.groups and spec.extra fields. The identity can be used to make policy
+	// decisions in the Sign function. The returned values must not be modified.
+	GetRequestingIdentity() (username string, uid string, groups []string, extra map[string][]string)
 }
 
 // IgnoreIssuer is an optional function that can prevent the issuer controllers from
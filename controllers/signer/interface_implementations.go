@@ -1,7 +1,11 @@
 package signer
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"crypto/x509"
+	"fmt"
 	"time"
 
 	apiutil "github.com/cert-manager/cert-manager/pkg/api/util"
@@ -13,12 +17,41 @@ import (
 
 type certificateRequestImpl struct {
 	*cmapi.CertificateRequest
+
+	// defaultUsages are the usages of the issuer that are used if the request
+	// doesn't specify any usages, see WithDefaultUsages.
+	defaultUsages *defaultUsages
+}
+
+type defaultUsages struct {
+	keyUsage     x509.KeyUsage
+	extKeyUsages []x509.ExtKeyUsage
 }
 
 var _ CertificateRequestObject = &certificateRequestImpl{}
 
 func CertificateRequestObjectFromCertificateRequest(cr *cmapi.CertificateRequest) CertificateRequestObject {
-	return &certificateRequestImpl{cr}
+	return &certificateRequestImpl{CertificateRequest: cr}
+}
+
+// WithDefaultUsages returns a CertificateRequestObject that uses the given key
+// usages and extended key usages instead of the cert-manager defaults if the
+// request doesn't specify any usages (see GetRequest). Only cert-manager
+// CertificateRequests can omit the usages, Kubernetes CSRs always specify them,
+// so other objects are returned unchanged.
+func WithDefaultUsages(cr CertificateRequestObject, keyUsage x509.KeyUsage, extKeyUsages []x509.ExtKeyUsage) CertificateRequestObject {
+	impl, ok := cr.(*certificateRequestImpl)
+	if !ok {
+		return cr
+	}
+
+	return &certificateRequestImpl{
+		CertificateRequest: impl.CertificateRequest,
+		defaultUsages: &defaultUsages{
+			keyUsage:     keyUsage,
+			extKeyUsages: extKeyUsages,
+		},
+	}
 }
 
 func (c *certificateRequestImpl) GetRequest() (*x509.Certificate, time.Duration, []byte, error) {
@@ -29,13 +62,40 @@ func (c *certificateRequestImpl) GetRequest() (*x509.Certificate, time.Duration,
 		return nil, 0, nil, err
 	}
 
+	// The usages in the CertificateRequest spec take precedence. If they are
+	// not set, the usages encoded in the CSR are used instead of the defaults.
+	if len(c.Spec.Usages) == 0 {
+		if c.defaultUsages != nil {
+			applyDefaultUsages(template, c.defaultUsages.keyUsage, c.defaultUsages.extKeyUsages)
+		}
+		if err := applyCSRKeyUsages(template, c.Spec.Request); err != nil {
+			return nil, 0, nil, err
+		}
+	}
+
 	return template, duration, c.Spec.Request, nil
 }
 
+func (c *certificateRequestImpl) GetPublicKeyAlgorithm() (x509.PublicKeyAlgorithm, int, error) {
+	return publicKeyAlgorithm(c.Spec.Request)
+}
+
+func (c *certificateRequestImpl) GetCommonName() string {
+	return commonName(c.Spec.Request)
+}
+
+func (c *certificateRequestImpl) GetX509CertificateRequest() (*x509.CertificateRequest, error) {
+	return pki.DecodeX509CertificateRequestBytes(c.Spec.Request)
+}
+
 func (c *certificateRequestImpl) GetConditions() []cmapi.CertificateRequestCondition {
 	return c.Status.Conditions
 }
 
+func (c *certificateRequestImpl) GetRequestingIdentity() (string, string, []string, map[string][]string) {
+	return c.Spec.Username, c.Spec.UID, c.Spec.Groups, c.Spec.Extra
+}
+
 type certificateSigningRequestImpl struct {
 	*certificatesv1.CertificateSigningRequest
 }
@@ -60,6 +120,18 @@ func (c *certificateSigningRequestImpl) GetRequest() (*x509.Certificate, time.Du
 	return template, duration, c.Spec.Request, nil
 }
 
+func (c *certificateSigningRequestImpl) GetPublicKeyAlgorithm() (x509.PublicKeyAlgorithm, int, error) {
+	return publicKeyAlgorithm(c.Spec.Request)
+}
+
+func (c *certificateSigningRequestImpl) GetCommonName() string {
+	return commonName(c.Spec.Request)
+}
+
+func (c *certificateSigningRequestImpl) GetX509CertificateRequest() (*x509.CertificateRequest, error) {
+	return pki.DecodeX509CertificateRequestBytes(c.Spec.Request)
+}
+
 func (c *certificateSigningRequestImpl) GetConditions() []cmapi.CertificateRequestCondition {
 	conditions := make([]cmapi.CertificateRequestCondition, 0, len(c.Status.Conditions))
 	for _, condition := range c.Status.Conditions {
@@ -74,3 +146,41 @@ func (c *certificateSigningRequestImpl) GetConditions() []cmapi.CertificateReque
 	}
 	return conditions
 }
+
+func (c *certificateSigningRequestImpl) GetRequestingIdentity() (string, string, []string, map[string][]string) {
+	var extra map[string][]string
+	if c.Spec.Extra != nil {
+		extra = make(map[string][]string, len(c.Spec.Extra))
+		for key, value := range c.Spec.Extra {
+			extra[key] = value
+		}
+	}
+	return c.Spec.Username, c.Spec.UID, c.Spec.Groups, extra
+}
+
+func commonName(csrPEM []byte) string {
+	csr, err := pki.DecodeX509CertificateRequestBytes(csrPEM)
+	if err != nil {
+		return ""
+	}
+
+	return csr.Subject.CommonName
+}
+
+func publicKeyAlgorithm(csrPEM []byte) (x509.PublicKeyAlgorithm, int, error) {
+	csr, err := pki.DecodeX509CertificateRequestBytes(csrPEM)
+	if err != nil {
+		return x509.UnknownPublicKeyAlgorithm, 0, err
+	}
+
+	switch publicKey := csr.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return x509.RSA, publicKey.N.BitLen(), nil
+	case *ecdsa.PublicKey:
+		return x509.ECDSA, publicKey.Curve.Params().BitSize, nil
+	case ed25519.PublicKey:
+		return x509.Ed25519, ed25519.PublicKeySize * 8, nil
+	default:
+		return x509.UnknownPublicKeyAlgorithm, 0, fmt.Errorf("unsupported public key type: %T", csr.PublicKey)
+	}
+}
@@ -0,0 +1,508 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/cert-manager/cert-manager/pkg/util/pki"
+	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+)
+
+func TestGetPublicKeyAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name              string
+		generateKey       func() (crypto.Signer, error)
+		csr               []byte
+		expectedAlgorithm x509.PublicKeyAlgorithm
+		expectedKeyBits   int
+		validateError     *errormatch.Matcher
+	}
+
+	tests := []testCase{
+		{
+			name:              "rsa-2048",
+			generateKey:       func() (crypto.Signer, error) { return pki.GenerateRSAPrivateKey(2048) },
+			expectedAlgorithm: x509.RSA,
+			expectedKeyBits:   2048,
+		},
+		{
+			name:              "rsa-4096",
+			generateKey:       func() (crypto.Signer, error) { return pki.GenerateRSAPrivateKey(4096) },
+			expectedAlgorithm: x509.RSA,
+			expectedKeyBits:   4096,
+		},
+		{
+			name:              "ecdsa-p256",
+			generateKey:       func() (crypto.Signer, error) { return pki.GenerateECPrivateKey(pki.ECCurve256) },
+			expectedAlgorithm: x509.ECDSA,
+			expectedKeyBits:   256,
+		},
+		{
+			name:              "ecdsa-p384",
+			generateKey:       func() (crypto.Signer, error) { return pki.GenerateECPrivateKey(pki.ECCurve384) },
+			expectedAlgorithm: x509.ECDSA,
+			expectedKeyBits:   384,
+		},
+		{
+			name:              "ecdsa-p521",
+			generateKey:       func() (crypto.Signer, error) { return pki.GenerateECPrivateKey(pki.ECCurve521) },
+			expectedAlgorithm: x509.ECDSA,
+			expectedKeyBits:   521,
+		},
+		{
+			name:              "ed25519",
+			generateKey:       func() (crypto.Signer, error) { return pki.GenerateEd25519PrivateKey() },
+			expectedAlgorithm: x509.Ed25519,
+			expectedKeyBits:   256,
+		},
+		{
+			name:              "invalid-csr",
+			csr:               []byte("invalid"),
+			expectedAlgorithm: x509.UnknownPublicKeyAlgorithm,
+			validateError:     errormatch.ErrorContains("error decoding certificate request PEM block"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			csrPEM := tc.csr
+			if tc.generateKey != nil {
+				sk, err := tc.generateKey()
+				require.NoError(t, err)
+
+				csrPEM, err = cmgen.CSRWithSigner(sk, cmgen.SetCSRCommonName("test"))
+				require.NoError(t, err)
+			}
+
+			objects := map[string]CertificateRequestObject{
+				"CertificateRequest": CertificateRequestObjectFromCertificateRequest(
+					cmgen.CertificateRequest("cr1", cmgen.SetCertificateRequestCSR(csrPEM)),
+				),
+				"CertificateSigningRequest": CertificateRequestObjectFromCertificateSigningRequest(
+					cmgen.CertificateSigningRequest("csr1", cmgen.SetCertificateSigningRequestRequest(csrPEM)),
+				),
+			}
+
+			for kind, object := range objects {
+				algorithm, keyBits, err := object.GetPublicKeyAlgorithm()
+
+				assert.Equal(t, tc.expectedAlgorithm, algorithm, kind)
+				assert.Equal(t, tc.expectedKeyBits, keyBits, kind)
+				ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+			}
+		})
+	}
+}
+
+// TestGetRequestSubject verifies that the subject fields of a cert-manager
+// Certificate (spec.subject and spec.commonName) are present in the template
+// returned by GetRequest, for the CSR that cert-manager generates for it.
+func TestGetRequestSubject(t *testing.T) {
+	t.Parallel()
+
+	certificate := &cmapi.Certificate{
+		Spec: cmapi.CertificateSpec{
+			CommonName: "test.example.com",
+			DNSNames:   []string{"test.example.com"},
+			Subject: &cmapi.X509Subject{
+				Organizations:       []string{"Example Org", "Other Org"},
+				Countries:           []string{"NL"},
+				OrganizationalUnits: []string{"Security"},
+				Localities:          []string{"Amsterdam"},
+				Provinces:           []string{"Noord-Holland"},
+				StreetAddresses:     []string{"Example Street 1"},
+				PostalCodes:         []string{"1000 AA"},
+				SerialNumber:        "12345",
+			},
+			PrivateKey: &cmapi.CertificatePrivateKey{
+				Algorithm: cmapi.ECDSAKeyAlgorithm,
+			},
+		},
+	}
+
+	csrTemplate, err := pki.GenerateCSR(certificate)
+	require.NoError(t, err)
+
+	sk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	require.NoError(t, err)
+
+	csrDER, err := pki.EncodeCSR(csrTemplate, sk)
+	require.NoError(t, err)
+
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	expectedSubject := pkix.Name{
+		CommonName:         "test.example.com",
+		Organization:       []string{"Example Org", "Other Org"},
+		Country:            []string{"NL"},
+		OrganizationalUnit: []string{"Security"},
+		Locality:           []string{"Amsterdam"},
+		Province:           []string{"Noord-Holland"},
+		StreetAddress:      []string{"Example Street 1"},
+		PostalCode:         []string{"1000 AA"},
+		SerialNumber:       "12345",
+	}
+
+	objects := map[string]CertificateRequestObject{
+		"CertificateRequest": CertificateRequestObjectFromCertificateRequest(
+			cmgen.CertificateRequest("cr1", cmgen.SetCertificateRequestCSR(csrPEM)),
+		),
+		"CertificateSigningRequest": CertificateRequestObjectFromCertificateSigningRequest(
+			cmgen.CertificateSigningRequest("csr1", cmgen.SetCertificateSigningRequestRequest(csrPEM)),
+		),
+	}
+
+	for kind, object := range objects {
+		template, _, _, err := object.GetRequest()
+		require.NoError(t, err, kind)
+
+		subject := template.Subject
+		subject.Names = nil // contains the parsed RDNs, which are compared using the fields above
+
+		// The values of a multi-valued RDN are sorted when the subject is DER encoded.
+		assert.ElementsMatch(t, expectedSubject.Organization, subject.Organization, kind)
+		subject.Organization = expectedSubject.Organization
+
+		assert.Equal(t, expectedSubject, subject, kind)
+		assert.Equal(t, []string{"test.example.com"}, template.DNSNames, kind)
+		assert.NotEmpty(t, template.RawSubject, kind)
+	}
+}
+
+// TestGetRequestLiteralSubject verifies that the literal subject of a CSR
+// (eg. the spec.literalSubject of a cert-manager Certificate) is preserved by
+// GetRequest and ends up unchanged in the signed certificate, without the RDNs
+// being re-ordered.
+func TestGetRequestLiteralSubject(t *testing.T) {
+	t.Parallel()
+
+	// The RDNs are not in the order that Go uses to encode a pkix.Name.
+	literalSubject := "CN=test-user, OU=Users, DC=example, DC=com, O=Example Org, C=NL"
+	rawSubject, err := pki.ParseSubjectStringToRawDERBytes(literalSubject)
+	require.NoError(t, err)
+
+	sk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	require.NoError(t, err)
+
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{RawSubject: rawSubject}, sk)
+	require.NoError(t, err)
+
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	ca, err := NewSelfSignedCA("test-ca", time.Hour)
+	require.NoError(t, err)
+
+	objects := map[string]CertificateRequestObject{
+		"CertificateRequest": CertificateRequestObjectFromCertificateRequest(
+			cmgen.CertificateRequest("cr1", cmgen.SetCertificateRequestCSR(csrPEM)),
+		),
+		"CertificateSigningRequest": CertificateRequestObjectFromCertificateSigningRequest(
+			cmgen.CertificateSigningRequest("csr1", cmgen.SetCertificateSigningRequestRequest(csrPEM)),
+		),
+	}
+
+	for kind, object := range objects {
+		template, _, _, err := object.GetRequest()
+		require.NoError(t, err, kind)
+		assert.Equal(t, rawSubject, template.RawSubject, kind)
+
+		bundle, err := ca.Sign(context.TODO(), object, nil)
+		require.NoError(t, err, kind)
+
+		leaf, err := pki.DecodeX509CertificateBytes(bundle.ChainPEM)
+		require.NoError(t, err, kind)
+		assert.Equal(t, rawSubject, leaf.RawSubject, kind)
+	}
+}
+
+func TestGetCommonName(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name               string
+		csrModifiers       []cmgen.CSRModifier
+		csr                []byte
+		expectedCommonName string
+	}
+
+	tests := []testCase{
+		{
+			name: "common-name",
+			csrModifiers: []cmgen.CSRModifier{
+				cmgen.SetCSRCommonName("test.example.com"),
+				cmgen.SetCSRDNSNames("other.example.com"),
+			},
+			expectedCommonName: "test.example.com",
+		},
+		{
+			// The common name is not derived from the subjectAltNames.
+			name: "no-common-name-with-sans",
+			csrModifiers: []cmgen.CSRModifier{
+				cmgen.SetCSRDNSNames("test.example.com"),
+			},
+			expectedCommonName: "",
+		},
+		{
+			name:               "invalid-csr",
+			csr:                []byte("invalid"),
+			expectedCommonName: "",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			csrPEM := tc.csr
+			if csrPEM == nil {
+				sk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+				require.NoError(t, err)
+
+				csrPEM, err = cmgen.CSRWithSigner(sk, tc.csrModifiers...)
+				require.NoError(t, err)
+			}
+
+			objects := map[string]CertificateRequestObject{
+				"CertificateRequest": CertificateRequestObjectFromCertificateRequest(
+					cmgen.CertificateRequest("cr1", cmgen.SetCertificateRequestCSR(csrPEM)),
+				),
+				"CertificateSigningRequest": CertificateRequestObjectFromCertificateSigningRequest(
+					cmgen.CertificateSigningRequest("csr1", cmgen.SetCertificateSigningRequestRequest(csrPEM)),
+				),
+			}
+
+			for kind, object := range objects {
+				assert.Equal(t, tc.expectedCommonName, object.GetCommonName(), kind)
+			}
+		})
+	}
+}
+
+func TestGetRequestKeyUsages(t *testing.T) {
+	t.Parallel()
+
+	serverAuthDefaultUsages := func(_ v1alpha1.Issuer) (x509.KeyUsage, []x509.ExtKeyUsage) {
+		return x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	}
+
+	type testCase struct {
+		name                  string
+		certificateUsages     []cmapi.KeyUsage
+		encodeUsagesInRequest bool
+		requestUsages         []cmapi.KeyUsage
+		defaultUsages         DefaultUsages
+		expectedKeyUsage      x509.KeyUsage
+		expectedExtKeyUsage   []x509.ExtKeyUsage
+	}
+
+	tests := []testCase{
+		{
+			name:                  "usages-in-csr",
+			certificateUsages:     []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageServerAuth, cmapi.UsageClientAuth},
+			encodeUsagesInRequest: true,
+			expectedKeyUsage:      x509.KeyUsageDigitalSignature,
+			expectedExtKeyUsage:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		},
+		{
+			name:                  "usages-in-csr-and-request",
+			certificateUsages:     []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageServerAuth, cmapi.UsageClientAuth},
+			encodeUsagesInRequest: true,
+			requestUsages:         []cmapi.KeyUsage{cmapi.UsageKeyEncipherment, cmapi.UsageServerAuth},
+			expectedKeyUsage:      x509.KeyUsageKeyEncipherment,
+			expectedExtKeyUsage:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		},
+		{
+			name:                  "usages-not-in-csr",
+			certificateUsages:     []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageServerAuth, cmapi.UsageClientAuth},
+			encodeUsagesInRequest: false,
+			expectedKeyUsage:      x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		},
+		{
+			name:                  "default-usages-not-in-csr",
+			certificateUsages:     []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageServerAuth, cmapi.UsageClientAuth},
+			encodeUsagesInRequest: false,
+			defaultUsages:         serverAuthDefaultUsages,
+			expectedKeyUsage:      x509.KeyUsageDigitalSignature,
+			expectedExtKeyUsage:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		},
+		{
+			name:                  "default-usages-overridden-by-csr",
+			certificateUsages:     []cmapi.KeyUsage{cmapi.UsageKeyEncipherment, cmapi.UsageClientAuth},
+			encodeUsagesInRequest: true,
+			defaultUsages:         serverAuthDefaultUsages,
+			expectedKeyUsage:      x509.KeyUsageKeyEncipherment,
+			expectedExtKeyUsage:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		},
+		{
+			name:                  "default-usages-overridden-by-request",
+			certificateUsages:     []cmapi.KeyUsage{cmapi.UsageDigitalSignature},
+			encodeUsagesInRequest: false,
+			requestUsages:         []cmapi.KeyUsage{cmapi.UsageKeyEncipherment, cmapi.UsageCodeSigning},
+			defaultUsages:         serverAuthDefaultUsages,
+			expectedKeyUsage:      x509.KeyUsageKeyEncipherment,
+			expectedExtKeyUsage:   []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			csrTemplate, err := pki.GenerateCSR(&cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					CommonName:            "test.example.com",
+					Usages:                tc.certificateUsages,
+					EncodeUsagesInRequest: ptr.To(tc.encodeUsagesInRequest),
+					PrivateKey: &cmapi.CertificatePrivateKey{
+						Algorithm: cmapi.ECDSAKeyAlgorithm,
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			sk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+			require.NoError(t, err)
+
+			csrDER, err := pki.EncodeCSR(csrTemplate, sk)
+			require.NoError(t, err)
+
+			csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+			object := CertificateRequestObjectFromCertificateRequest(
+				cmgen.CertificateRequest("cr1",
+					cmgen.SetCertificateRequestCSR(csrPEM),
+					cmgen.SetCertificateRequestKeyUsages(tc.requestUsages...),
+				),
+			)
+
+			if tc.defaultUsages != nil {
+				keyUsage, extKeyUsages := tc.defaultUsages(nil)
+				object = WithDefaultUsages(object, keyUsage, extKeyUsages)
+			}
+
+			template, _, _, err := object.GetRequest()
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedKeyUsage, template.KeyUsage)
+			assert.Equal(t, tc.expectedExtKeyUsage, template.ExtKeyUsage)
+		})
+	}
+}
+
+func TestGetRequestingIdentity(t *testing.T) {
+	t.Parallel()
+
+	cr := cmgen.CertificateRequest("cr1")
+	cr.Spec.Username = "user-1"
+	cr.Spec.UID = "uid-1"
+	cr.Spec.Groups = []string{"group-1", "group-2"}
+	cr.Spec.Extra = map[string][]string{"example.com/scope": {"a", "b"}}
+
+	csr := cmgen.CertificateSigningRequest("csr1")
+	csr.Spec.Username = "user-1"
+	csr.Spec.UID = "uid-1"
+	csr.Spec.Groups = []string{"group-1", "group-2"}
+	csr.Spec.Extra = map[string]certificatesv1.ExtraValue{"example.com/scope": {"a", "b"}}
+
+	for name, request := range map[string]CertificateRequestObject{
+		"certificate-request":         CertificateRequestObjectFromCertificateRequest(cr),
+		"certificate-signing-request": CertificateRequestObjectFromCertificateSigningRequest(csr),
+	} {
+		username, uid, groups, extra := request.GetRequestingIdentity()
+		assert.Equal(t, "user-1", username, name)
+		assert.Equal(t, "uid-1", uid, name)
+		assert.Equal(t, []string{"group-1", "group-2"}, groups, name)
+		assert.Equal(t, map[string][]string{"example.com/scope": {"a", "b"}}, extra, name)
+	}
+}
+
+func TestGetX509CertificateRequest(t *testing.T) {
+	t.Parallel()
+
+	sk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	require.NoError(t, err)
+
+	// A custom extension that is not reflected by the certificate template.
+	customExtension := pkix.Extension{
+		Id:    asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1},
+		Value: []byte{0x04, 0x03, 'f', 'o', 'o'},
+	}
+
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:         pkix.Name{CommonName: "test.example.com"},
+		DNSNames:        []string{"test.example.com"},
+		ExtraExtensions: []pkix.Extension{customExtension},
+	}, sk)
+	require.NoError(t, err)
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	objects := map[string]CertificateRequestObject{
+		"CertificateRequest": CertificateRequestObjectFromCertificateRequest(
+			cmgen.CertificateRequest("cr1", cmgen.SetCertificateRequestCSR(csrPEM)),
+		),
+		"CertificateSigningRequest": CertificateRequestObjectFromCertificateSigningRequest(
+			cmgen.CertificateSigningRequest("csr1", cmgen.SetCertificateSigningRequestRequest(csrPEM)),
+		),
+	}
+
+	for kind, object := range objects {
+		csr, err := object.GetX509CertificateRequest()
+		require.NoError(t, err, kind)
+
+		require.NoError(t, csr.CheckSignature(), kind)
+		assert.Equal(t, "test.example.com", csr.Subject.CommonName, kind)
+		assert.Contains(t, csr.Extensions, customExtension, kind)
+	}
+
+	invalidObjects := map[string]CertificateRequestObject{
+		"CertificateRequest": CertificateRequestObjectFromCertificateRequest(
+			cmgen.CertificateRequest("cr1", cmgen.SetCertificateRequestCSR([]byte("invalid"))),
+		),
+		"CertificateSigningRequest": CertificateRequestObjectFromCertificateSigningRequest(
+			cmgen.CertificateSigningRequest("csr1", cmgen.SetCertificateSigningRequestRequest([]byte("invalid"))),
+		),
+	}
+
+	for kind, object := range invalidObjects {
+		_, err := object.GetX509CertificateRequest()
+		assert.ErrorContains(t, err, "error decoding certificate request PEM block", kind)
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+)
+
+// IssuerOwnedBy returns an IgnoreIssuer function that ignores all issuers that
+// don't have a label or an annotation with the given key and value. This can be
+// used to split the issuers of the same type between multiple controllers (eg.
+// during a blue/green deployment), each configured with a different value.
+// The requests that reference an ignored issuer are ignored too.
+func IssuerOwnedBy(key, value string) IgnoreIssuer {
+	return func(_ context.Context, issuerObject v1alpha1.Issuer) (bool, error) {
+		if labelValue, ok := issuerObject.GetLabels()[key]; ok && labelValue == value {
+			return false, nil
+		}
+		if annotationValue, ok := issuerObject.GetAnnotations()[key]; ok && annotationValue == value {
+			return false, nil
+		}
+		return true, nil
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/testutil"
+)
+
+func TestIssuerOwnedBy(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name           string
+		labels         map[string]string
+		annotations    map[string]string
+		expectedIgnore bool
+	}
+
+	tests := []testCase{
+		{
+			name:           "matching-label",
+			labels:         map[string]string{"example.com/controller": "blue"},
+			expectedIgnore: false,
+		},
+		{
+			name:           "matching-annotation",
+			annotations:    map[string]string{"example.com/controller": "blue"},
+			expectedIgnore: false,
+		},
+		{
+			name:           "different-value",
+			labels:         map[string]string{"example.com/controller": "green"},
+			expectedIgnore: true,
+		},
+		{
+			name:           "missing-key",
+			labels:         map[string]string{"example.com/other": "blue"},
+			expectedIgnore: true,
+		},
+		{
+			name:           "no-labels-or-annotations",
+			expectedIgnore: true,
+		},
+	}
+
+	ignoreIssuer := IssuerOwnedBy("example.com/controller", "blue")
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			issuer := testutil.SimpleIssuer("issuer-1", func(si *api.SimpleIssuer) {
+				si.Labels = tc.labels
+				si.Annotations = tc.annotations
+			})
+
+			ignore, err := ignoreIssuer(context.TODO(), issuer)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedIgnore, ignore)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+
+	"github.com/cert-manager/cert-manager/pkg/util/pki"
+)
+
+// applyCSRKeyUsages overwrites the key usages of the template with the key
+// usages that are encoded in the extensions of the CSR (cert-manager encodes
+// them if spec.encodeUsagesInRequest is not set to false on the Certificate).
+// Only the usages of the extensions that are present in the CSR are applied.
+func applyCSRKeyUsages(template *x509.Certificate, csrPEM []byte) error {
+	csr, err := pki.DecodeX509CertificateRequestBytes(csrPEM)
+	if err != nil {
+		return err
+	}
+
+	for _, extension := range csr.Extensions {
+		switch {
+		case extension.Id.Equal(pki.OIDExtensionKeyUsage):
+			var bitString asn1.BitString
+			if _, err := asn1.Unmarshal(extension.Value, &bitString); err != nil {
+				return fmt.Errorf("failed to parse the key usage extension: %w", err)
+			}
+
+			var keyUsage x509.KeyUsage
+			for i := 0; i < 9; i++ {
+				if bitString.At(i) != 0 {
+					keyUsage |= 1 << uint(i)
+				}
+			}
+			if template.IsCA {
+				keyUsage |= x509.KeyUsageCertSign
+			}
+			template.KeyUsage = keyUsage
+
+		case extension.Id.Equal(pki.OIDExtensionExtendedKeyUsage):
+			var oids []asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(extension.Value, &oids); err != nil {
+				return fmt.Errorf("failed to parse the extended key usage extension: %w", err)
+			}
+
+			template.ExtKeyUsage = nil
+			template.UnknownExtKeyUsage = nil
+			for _, oid := range oids {
+				if extKeyUsage, ok := pki.ExtKeyUsageFromOID(oid); ok {
+					template.ExtKeyUsage = append(template.ExtKeyUsage, extKeyUsage)
+				} else {
+					template.UnknownExtKeyUsage = append(template.UnknownExtKeyUsage, oid)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyDefaultUsages overwrites the key usages of the template with the
+// default usages of the issuer.
+func applyDefaultUsages(template *x509.Certificate, keyUsage x509.KeyUsage, extKeyUsages []x509.ExtKeyUsage) {
+	if template.IsCA {
+		keyUsage |= x509.KeyUsageCertSign
+	}
+	template.KeyUsage = keyUsage
+	template.ExtKeyUsage = append([]x509.ExtKeyUsage(nil), extKeyUsages...)
+	template.UnknownExtKeyUsage = nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"regexp"
+)
+
+// NameConstraints restricts the subjectAltNames that an issuer is allowed to
+// sign. The DNS and URI patterns are regular expressions that must match the
+// complete name (they are implicitly anchored), eg. `.*\.internal\.example\.com`.
+// The IP ranges are CIDRs, eg. "10.0.0.0/8".
+//
+// If a Permitted list is not empty, every name of that type must match at
+// least one of its entries. A name that matches an entry of an Excluded list
+// is never allowed, even if it is also permitted. Empty lists don't constrain
+// the names.
+type NameConstraints struct {
+	PermittedDNSNames []string
+	ExcludedDNSNames  []string
+
+	PermittedIPRanges []string
+	ExcludedIPRanges  []string
+
+	PermittedURIs []string
+	ExcludedURIs  []string
+}
+
+// Validate checks that all the subjectAltNames of the certificate template
+// are allowed by the name constraints. If subjectAltNames are not allowed, a
+// PermanentError that wraps a ValidationError is returned, which names every
+// subjectAltName that is not allowed. A normal error is returned if one of the
+// patterns or CIDRs is invalid.
+func (nc *NameConstraints) Validate(template *x509.Certificate) error {
+	permittedDNSNames, err := compilePatterns(nc.PermittedDNSNames)
+	if err != nil {
+		return fmt.Errorf("invalid permitted DNS name pattern: %w", err)
+	}
+	excludedDNSNames, err := compilePatterns(nc.ExcludedDNSNames)
+	if err != nil {
+		return fmt.Errorf("invalid excluded DNS name pattern: %w", err)
+	}
+	permittedIPRanges, err := parseCIDRs(nc.PermittedIPRanges)
+	if err != nil {
+		return fmt.Errorf("invalid permitted IP range: %w", err)
+	}
+	excludedIPRanges, err := parseCIDRs(nc.ExcludedIPRanges)
+	if err != nil {
+		return fmt.Errorf("invalid excluded IP range: %w", err)
+	}
+	permittedURIs, err := compilePatterns(nc.PermittedURIs)
+	if err != nil {
+		return fmt.Errorf("invalid permitted URI pattern: %w", err)
+	}
+	excludedURIs, err := compilePatterns(nc.ExcludedURIs)
+	if err != nil {
+		return fmt.Errorf("invalid excluded URI pattern: %w", err)
+	}
+
+	var violations []string
+
+	for _, dnsName := range template.DNSNames {
+		if err := checkName("DNS name", dnsName, permittedDNSNames, excludedDNSNames); err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+
+	for _, ip := range template.IPAddresses {
+		if err := checkIP(ip, permittedIPRanges, excludedIPRanges); err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+
+	for _, uri := range template.URIs {
+		if err := checkName("URI", uri.String(), permittedURIs, excludedURIs); err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+
+	if len(violations) > 0 {
+		return PermanentError{Err: ValidationError{Violations: violations}}
+	}
+
+	return nil
+}
+
+func checkName(nameType string, name string, permitted []*regexp.Regexp, excluded []*regexp.Regexp) error {
+	if pattern := matchingPattern(excluded, name); pattern != "" {
+		return fmt.Errorf("%s %q is excluded by the name constraints (matches %q)", nameType, name, pattern)
+	}
+	if len(permitted) > 0 && matchingPattern(permitted, name) == "" {
+		return fmt.Errorf("%s %q is not permitted by the name constraints", nameType, name)
+	}
+	return nil
+}
+
+func checkIP(ip net.IP, permitted []*net.IPNet, excluded []*net.IPNet) error {
+	if cidr := matchingCIDR(excluded, ip); cidr != "" {
+		return fmt.Errorf("IP address %q is excluded by the name constraints (matches %q)", ip, cidr)
+	}
+	if len(permitted) > 0 && matchingCIDR(permitted, ip) == "" {
+		return fmt.Errorf("IP address %q is not permitted by the name constraints", ip)
+	}
+	return nil
+}
+
+// compilePatterns compiles the patterns so that they only match complete names.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	regexps := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, err
+		}
+		regexps = append(regexps, re)
+	}
+	return regexps, nil
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	ipNets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	return ipNets, nil
+}
+
+// matchingPattern returns the original pattern of the first regexp that
+// matches the name, or an empty string if none matches.
+func matchingPattern(regexps []*regexp.Regexp, name string) string {
+	for _, re := range regexps {
+		if re.MatchString(name) {
+			pattern := re.String()
+			return pattern[len("^(?:") : len(pattern)-len(")$")]
+		}
+	}
+	return ""
+}
+
+// matchingCIDR returns the first CIDR that contains the IP address, or an
+// empty string if none contains it.
+func matchingCIDR(ipNets []*net.IPNet, ip net.IP) string {
+	for _, ipNet := range ipNets {
+		if ipNet.Contains(ip) {
+			return ipNet.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+)
+
+func TestNameConstraintsValidate(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name            string
+		constraints     NameConstraints
+		dnsNames        []string
+		ipAddresses     []string
+		uris            []string
+		validateError   *errormatch.Matcher
+		expectPermanent bool
+	}
+
+	tests := []testCase{
+		{
+			name:        "no-constraints",
+			dnsNames:    []string{"foo.example.com"},
+			ipAddresses: []string{"10.0.0.1"},
+			uris:        []string{"spiffe://example.com/foo"},
+		},
+
+		// allow
+		{
+			name: "allow-permitted-names",
+			constraints: NameConstraints{
+				PermittedDNSNames: []string{`.*\.internal\.example\.com`},
+				PermittedIPRanges: []string{"10.0.0.0/8"},
+				PermittedURIs:     []string{`spiffe://example\.com/.*`},
+			},
+			dnsNames:    []string{"foo.internal.example.com", "bar.internal.example.com"},
+			ipAddresses: []string{"10.1.2.3"},
+			uris:        []string{"spiffe://example.com/foo"},
+		},
+		{
+			name: "allow-dns-name-not-permitted",
+			constraints: NameConstraints{
+				PermittedDNSNames: []string{`.*\.internal\.example\.com`},
+			},
+			dnsNames:        []string{"foo.internal.example.com", "foo.example.com"},
+			validateError:   errormatch.ErrorContains(`DNS name "foo.example.com" is not permitted by the name constraints`),
+			expectPermanent: true,
+		},
+		{
+			name: "allow-patterns-match-complete-names",
+			constraints: NameConstraints{
+				PermittedDNSNames: []string{`.*\.internal\.example\.com`},
+			},
+			dnsNames:        []string{"foo.internal.example.com.evil.com"},
+			validateError:   errormatch.ErrorContains(`DNS name "foo.internal.example.com.evil.com" is not permitted by the name constraints`),
+			expectPermanent: true,
+		},
+		{
+			name: "allow-ip-address-not-permitted",
+			constraints: NameConstraints{
+				PermittedIPRanges: []string{"10.0.0.0/8"},
+			},
+			ipAddresses:     []string{"192.168.0.1"},
+			validateError:   errormatch.ErrorContains(`IP address "192.168.0.1" is not permitted by the name constraints`),
+			expectPermanent: true,
+		},
+		{
+			name: "allow-uri-not-permitted",
+			constraints: NameConstraints{
+				PermittedURIs: []string{`spiffe://example\.com/.*`},
+			},
+			uris:            []string{"spiffe://other.com/foo"},
+			validateError:   errormatch.ErrorContains(`URI "spiffe://other.com/foo" is not permitted by the name constraints`),
+			expectPermanent: true,
+		},
+
+		// deny
+		{
+			name: "deny-names-not-excluded",
+			constraints: NameConstraints{
+				ExcludedDNSNames: []string{`.*\.prod\.example\.com`},
+				ExcludedIPRanges: []string{"10.0.0.0/8"},
+				ExcludedURIs:     []string{`spiffe://example\.com/admin/.*`},
+			},
+			dnsNames:    []string{"foo.dev.example.com"},
+			ipAddresses: []string{"192.168.0.1"},
+			uris:        []string{"spiffe://example.com/foo"},
+		},
+		{
+			name: "deny-dns-name-excluded",
+			constraints: NameConstraints{
+				ExcludedDNSNames: []string{`.*\.prod\.example\.com`},
+			},
+			dnsNames:        []string{"foo.dev.example.com", "foo.prod.example.com"},
+			validateError:   errormatch.ErrorContains(`DNS name "foo.prod.example.com" is excluded by the name constraints (matches ".*\\.prod\\.example\\.com")`),
+			expectPermanent: true,
+		},
+		{
+			name: "deny-ip-address-excluded",
+			constraints: NameConstraints{
+				ExcludedIPRanges: []string{"10.0.0.0/8"},
+			},
+			ipAddresses:     []string{"10.1.2.3"},
+			validateError:   errormatch.ErrorContains(`IP address "10.1.2.3" is excluded by the name constraints (matches "10.0.0.0/8")`),
+			expectPermanent: true,
+		},
+		{
+			name: "deny-uri-excluded",
+			constraints: NameConstraints{
+				ExcludedURIs: []string{`spiffe://example\.com/admin/.*`},
+			},
+			uris:            []string{"spiffe://example.com/admin/foo"},
+			validateError:   errormatch.ErrorContains(`URI "spiffe://example.com/admin/foo" is excluded by the name constraints (matches "spiffe://example\\.com/admin/.*")`),
+			expectPermanent: true,
+		},
+
+		// mixed
+		{
+			name: "mixed-permitted-and-not-excluded",
+			constraints: NameConstraints{
+				PermittedDNSNames: []string{`.*\.example\.com`},
+				ExcludedDNSNames:  []string{`.*\.prod\.example\.com`},
+				PermittedIPRanges: []string{"10.0.0.0/8"},
+				ExcludedIPRanges:  []string{"10.0.0.0/16"},
+			},
+			dnsNames:    []string{"foo.dev.example.com"},
+			ipAddresses: []string{"10.1.0.1"},
+		},
+		{
+			name: "mixed-excluded-wins-over-permitted",
+			constraints: NameConstraints{
+				PermittedDNSNames: []string{`.*\.example\.com`},
+				ExcludedDNSNames:  []string{`.*\.prod\.example\.com`},
+			},
+			dnsNames:        []string{"foo.prod.example.com"},
+			validateError:   errormatch.ErrorContains(`DNS name "foo.prod.example.com" is excluded by the name constraints`),
+			expectPermanent: true,
+		},
+		{
+			name: "mixed-ip-excluded-wins-over-permitted",
+			constraints: NameConstraints{
+				PermittedIPRanges: []string{"10.0.0.0/8"},
+				ExcludedIPRanges:  []string{"10.0.0.0/16"},
+			},
+			ipAddresses:     []string{"10.0.0.1"},
+			validateError:   errormatch.ErrorContains(`IP address "10.0.0.1" is excluded by the name constraints (matches "10.0.0.0/16")`),
+			expectPermanent: true,
+		},
+		{
+			name: "mixed-dns-constraints-dont-apply-to-ips",
+			constraints: NameConstraints{
+				PermittedDNSNames: []string{`.*\.example\.com`},
+			},
+			ipAddresses: []string{"192.168.0.1"},
+		},
+
+		// multiple violations are reported together
+		{
+			name: "multiple-violations",
+			constraints: NameConstraints{
+				PermittedDNSNames: []string{`.*\.internal\.example\.com`},
+				ExcludedIPRanges:  []string{"10.0.0.0/8"},
+			},
+			dnsNames:        []string{"foo.example.com", "foo.internal.example.com", "bar.example.com"},
+			ipAddresses:     []string{"10.0.0.1"},
+			validateError:   errormatch.ErrorContains(`3 validation errors: DNS name "foo.example.com" is not permitted by the name constraints; DNS name "bar.example.com" is not permitted by the name constraints; IP address "10.0.0.1" is excluded by the name constraints (matches "10.0.0.0/8")`),
+			expectPermanent: true,
+		},
+
+		// invalid constraints
+		{
+			name: "invalid-pattern",
+			constraints: NameConstraints{
+				PermittedDNSNames: []string{`(`},
+			},
+			dnsNames:      []string{"foo.example.com"},
+			validateError: errormatch.ErrorContains("invalid permitted DNS name pattern"),
+		},
+		{
+			name: "invalid-cidr",
+			constraints: NameConstraints{
+				ExcludedIPRanges: []string{"10.0.0.0"},
+			},
+			ipAddresses:   []string{"10.0.0.1"},
+			validateError: errormatch.ErrorContains("invalid excluded IP range"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			template := &x509.Certificate{DNSNames: tc.dnsNames}
+			for _, ip := range tc.ipAddresses {
+				template.IPAddresses = append(template.IPAddresses, net.ParseIP(ip))
+			}
+			for _, uri := range tc.uris {
+				parsed, err := url.Parse(uri)
+				assert.NoError(t, err)
+				template.URIs = append(template.URIs, parsed)
+			}
+
+			err := tc.constraints.Validate(template)
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+			assert.Equal(t, tc.expectPermanent, errors.As(err, &PermanentError{}))
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cert-manager/cert-manager/pkg/util/pki"
+)
+
+// NotAfterTolerance is the maximum difference that is allowed between the
+// NotAfter field of a PEMBundle and the NotAfter of the parsed leaf certificate.
+// X.509 certificates only have a precision of one second, and the CA might
+// return a slightly different time than the one that ends up in the certificate.
+const NotAfterTolerance = time.Minute
+
+// CertificateNotAfter returns the expiry time of the leaf certificate.
+// If the NotAfter field is not set, the leaf certificate is parsed.
+// If the NotAfter field is set and the leaf certificate can't be parsed, the
+// NotAfter field is returned. If both are available, an error is returned if
+// they differ by more than the NotAfterTolerance.
+func (b PEMBundle) CertificateNotAfter() (time.Time, error) {
+	leaf, parseErr := pki.DecodeX509CertificateBytes(b.ChainPEM)
+
+	if b.NotAfter.IsZero() {
+		if parseErr != nil {
+			return time.Time{}, fmt.Errorf("failed to parse the leaf certificate: %w", parseErr)
+		}
+
+		return leaf.NotAfter, nil
+	}
+
+	if parseErr != nil {
+		return b.NotAfter, nil
+	}
+
+	difference := b.NotAfter.Sub(leaf.NotAfter)
+	if difference < 0 {
+		difference = -difference
+	}
+	if difference > NotAfterTolerance {
+		return time.Time{}, fmt.Errorf(
+			"the NotAfter of the bundle (%s) does not match the NotAfter of the leaf certificate (%s)",
+			b.NotAfter.UTC().Format(time.RFC3339), leaf.NotAfter.UTC().Format(time.RFC3339),
+		)
+	}
+
+	return leaf.NotAfter, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cert-manager/cert-manager/pkg/util/pki"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+)
+
+func TestCertificateNotAfter(t *testing.T) {
+	t.Parallel()
+
+	notAfter := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    notAfter.Add(-2 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	certPEM, _, err := pki.SignCertificate(template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	type testCase struct {
+		name             string
+		bundle           PEMBundle
+		expectedNotAfter time.Time
+		validateError    *errormatch.Matcher
+	}
+
+	tests := []testCase{
+		{
+			name:             "parsed-from-certificate",
+			bundle:           PEMBundle{ChainPEM: certPEM},
+			expectedNotAfter: notAfter,
+		},
+		{
+			name:          "unparseable-certificate",
+			bundle:        PEMBundle{ChainPEM: []byte("opaque-token")},
+			validateError: errormatch.ErrorContains("failed to parse the leaf certificate"),
+		},
+		{
+			name:             "not-after-of-unparseable-certificate",
+			bundle:           PEMBundle{ChainPEM: []byte("opaque-token"), NotAfter: notAfter.Add(time.Hour)},
+			expectedNotAfter: notAfter.Add(time.Hour),
+		},
+		{
+			name:             "not-after-matches-certificate",
+			bundle:           PEMBundle{ChainPEM: certPEM, NotAfter: notAfter.Add(500 * time.Millisecond)},
+			expectedNotAfter: notAfter,
+		},
+		{
+			name:          "not-after-does-not-match-certificate",
+			bundle:        PEMBundle{ChainPEM: certPEM, NotAfter: notAfter.Add(-NotAfterTolerance - time.Second)},
+			validateError: errormatch.ErrorContains("does not match the NotAfter of the leaf certificate"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			notAfter, err := tc.bundle.CertificateNotAfter()
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+			assert.True(t, tc.expectedNotAfter.Equal(notAfter), "expected %s, got %s", tc.expectedNotAfter, notAfter)
+		})
+	}
+}
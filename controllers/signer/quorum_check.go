@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+)
+
+// QuorumCheck returns a Check that runs all the checks concurrently (eg. one
+// for each regional endpoint of a CA) and succeeds if at least quorum of them
+// succeed. Otherwise, an error that aggregates the errors of the failed checks
+// is returned. This error is always retryable, even if one of the failed checks
+// returned a PermanentError. If quorum is not between 1 and the number of
+// checks, the returned Check always fails.
+func QuorumCheck(checks []Check, quorum int) Check {
+	return func(ctx context.Context, issuerObject v1alpha1.Issuer) error {
+		if quorum < 1 || quorum > len(checks) {
+			return fmt.Errorf("invalid quorum %d, must be between 1 and the number of checks (%d)", quorum, len(checks))
+		}
+
+		errs := make([]error, len(checks))
+
+		var wg sync.WaitGroup
+		wg.Add(len(checks))
+		for i, check := range checks {
+			go func(i int, check Check) {
+				defer wg.Done()
+				errs[i] = check(ctx, issuerObject)
+			}(i, check)
+		}
+		wg.Wait()
+
+		var failed []error
+		for _, err := range errs {
+			if err != nil {
+				failed = append(failed, err)
+			}
+		}
+
+		succeeded := len(checks) - len(failed)
+		if succeeded >= quorum {
+			return nil
+		}
+
+		return fmt.Errorf("%d of %d checks succeeded, but a quorum of %d is required: %w", succeeded, len(checks), quorum, utilerrors.NewAggregate(failed))
+	}
+}
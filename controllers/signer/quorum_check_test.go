@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+)
+
+func TestQuorumCheck(t *testing.T) {
+	t.Parallel()
+
+	succeeding := func(_ context.Context, _ v1alpha1.Issuer) error { return nil }
+	failing := func(name string) Check {
+		return func(_ context.Context, _ v1alpha1.Issuer) error { return fmt.Errorf("[%s unavailable]", name) }
+	}
+	failingPermanently := func(_ context.Context, _ v1alpha1.Issuer) error {
+		return PermanentError{Err: fmt.Errorf("[invalid config]")}
+	}
+
+	type testCase struct {
+		name          string
+		checks        []Check
+		quorum        int
+		validateError *errormatch.Matcher
+	}
+
+	tests := []testCase{
+		{
+			name:   "all-succeed",
+			checks: []Check{succeeding, succeeding, succeeding},
+			quorum: 3,
+		},
+		{
+			name:   "quorum-reached",
+			checks: []Check{succeeding, failing("eu"), succeeding},
+			quorum: 2,
+		},
+		{
+			name:          "quorum-not-reached",
+			checks:        []Check{failing("us"), failing("eu"), succeeding},
+			quorum:        2,
+			validateError: errormatch.ErrorContains("1 of 3 checks succeeded, but a quorum of 2 is required: [[us unavailable], [eu unavailable]]"),
+		},
+		{
+			name:          "permanent-error-is-retryable",
+			checks:        []Check{failingPermanently},
+			quorum:        1,
+			validateError: errormatch.ErrorContains("0 of 1 checks succeeded, but a quorum of 1 is required: [invalid config]"),
+		},
+		{
+			name:          "zero-quorum",
+			checks:        []Check{succeeding},
+			quorum:        0,
+			validateError: errormatch.ErrorContains("invalid quorum 0, must be between 1 and the number of checks (1)"),
+		},
+		{
+			name:          "quorum-larger-than-checks",
+			checks:        []Check{succeeding},
+			quorum:        2,
+			validateError: errormatch.ErrorContains("invalid quorum 2, must be between 1 and the number of checks (1)"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := QuorumCheck(tc.checks, tc.quorum)(context.TODO(), nil)
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+			if err != nil {
+				assert.False(t, errors.As(err, &PermanentError{}))
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DeduplicateSANs removes the duplicate subjectAltNames from the template and
+// returns the removed SANs (eg. "DNS:example.com"). The first occurrence of each
+// SAN is kept. DNS names are compared case-insensitively and without a trailing
+// dot, IP addresses are compared by value (so an IPv4 address and its
+// IPv4-mapped IPv6 form are duplicates).
+func DeduplicateSANs(template *x509.Certificate) []string {
+	var removed []string
+
+	var dnsNames []string
+	seenDNSNames := map[string]struct{}{}
+	for _, name := range template.DNSNames {
+		key := strings.TrimSuffix(strings.ToLower(name), ".")
+		if _, ok := seenDNSNames[key]; ok {
+			removed = append(removed, "DNS:"+name)
+			continue
+		}
+		seenDNSNames[key] = struct{}{}
+		dnsNames = append(dnsNames, name)
+	}
+	template.DNSNames = dnsNames
+
+	var ipAddresses []net.IP
+	seenIPAddresses := map[string]struct{}{}
+	for _, ip := range template.IPAddresses {
+		key := ip.String()
+		if _, ok := seenIPAddresses[key]; ok {
+			removed = append(removed, "IP:"+key)
+			continue
+		}
+		seenIPAddresses[key] = struct{}{}
+		ipAddresses = append(ipAddresses, ip)
+	}
+	template.IPAddresses = ipAddresses
+
+	var uris []*url.URL
+	seenURIs := map[string]struct{}{}
+	for _, uri := range template.URIs {
+		key := uri.String()
+		if _, ok := seenURIs[key]; ok {
+			removed = append(removed, "URI:"+key)
+			continue
+		}
+		seenURIs[key] = struct{}{}
+		uris = append(uris, uri)
+	}
+	template.URIs = uris
+
+	var emailAddresses []string
+	seenEmailAddresses := map[string]struct{}{}
+	for _, email := range template.EmailAddresses {
+		if _, ok := seenEmailAddresses[email]; ok {
+			removed = append(removed, "email:"+email)
+			continue
+		}
+		seenEmailAddresses[email] = struct{}{}
+		emailAddresses = append(emailAddresses, email)
+	}
+	template.EmailAddresses = emailAddresses
+
+	return removed
+}
+
+// ConflictingSANs returns a violation for every subjectAltName of the template
+// that conflicts with the way it is requested: a DNS name that is an IP address
+// (which clients don't match against IP addresses, it has to be requested as
+// an IP address SAN instead).
+func ConflictingSANs(template *x509.Certificate) []string {
+	var violations []string
+	for _, name := range template.DNSNames {
+		if ip := net.ParseIP(name); ip != nil {
+			violations = append(violations, fmt.Sprintf("DNS name %q is an IP address, request it as an IP address SAN instead", name))
+		}
+	}
+	return violations
+}
+
+type deduplicatedSANsRequest struct {
+	CertificateRequestObject
+}
+
+// WithDeduplicatedSANs returns a CertificateRequestObject whose GetRequest
+// returns a template without duplicate subjectAltNames, see DeduplicateSANs.
+// The raw CSR (and GetX509CertificateRequest) is returned unchanged.
+func WithDeduplicatedSANs(cr CertificateRequestObject) CertificateRequestObject {
+	return &deduplicatedSANsRequest{CertificateRequestObject: cr}
+}
+
+func (c *deduplicatedSANsRequest) GetRequest() (*x509.Certificate, time.Duration, []byte, error) {
+	template, duration, csr, err := c.CertificateRequestObject.GetRequest()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	DeduplicateSANs(template)
+	return template, duration, csr, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"crypto/x509"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicateSANs(t *testing.T) {
+	t.Parallel()
+
+	template := &x509.Certificate{
+		DNSNames:       []string{"foo.example.com", "FOO.example.com", "bar.example.com", "foo.example.com."},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::ffff:10.0.0.1"), net.ParseIP("10.0.0.2")},
+		URIs:           []*url.URL{{Scheme: "spiffe", Host: "example.com", Path: "/a"}, {Scheme: "spiffe", Host: "example.com", Path: "/a"}},
+		EmailAddresses: []string{"admin@example.com", "admin@example.com"},
+	}
+
+	removed := DeduplicateSANs(template)
+
+	assert.Equal(t, []string{
+		"DNS:FOO.example.com",
+		"DNS:foo.example.com.",
+		"IP:10.0.0.1",
+		"URI:spiffe://example.com/a",
+		"email:admin@example.com",
+	}, removed)
+	assert.Equal(t, []string{"foo.example.com", "bar.example.com"}, template.DNSNames)
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")}, template.IPAddresses)
+	assert.Equal(t, []*url.URL{{Scheme: "spiffe", Host: "example.com", Path: "/a"}}, template.URIs)
+	assert.Equal(t, []string{"admin@example.com"}, template.EmailAddresses)
+
+	assert.Empty(t, DeduplicateSANs(template))
+}
+
+func TestConflictingSANs(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, ConflictingSANs(&x509.Certificate{
+		DNSNames:    []string{"foo.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+	}))
+	assert.Equal(t, []string{
+		`DNS name "10.0.0.1" is an IP address, request it as an IP address SAN instead`,
+		`DNS name "::1" is an IP address, request it as an IP address SAN instead`,
+	}, ConflictingSANs(&x509.Certificate{
+		DNSNames: []string{"foo.example.com", "10.0.0.1", "::1"},
+	}))
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/cert-manager/cert-manager/pkg/util/pki"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+)
+
+// SelfSignedCA is a CA with a self-signed certificate that signs the requests
+// locally. It is meant for demos, e2e tests and local development, so that a
+// working issuer-lib controller can be set up without wiring a real CA:
+//
+//	ca, err := signer.NewSelfSignedCA("my-demo-ca", 365*24*time.Hour)
+//	...
+//	controller := &controllers.CombinedController{
+//		...
+//		Check: ca.Check,
+//		Sign:  ca.Sign,
+//	}
+//
+// The same CA is used for all issuers. To keep the CA across restarts of the
+// controller, store the CertificatePEM and PrivateKeyPEM (eg. in a Secret) and
+// use LoadSelfSignedCA.
+type SelfSignedCA struct {
+	// Certificate is the self-signed CA certificate.
+	Certificate *x509.Certificate
+	// PrivateKey is the private key of the CA.
+	PrivateKey crypto.Signer
+
+	// CertificatePEM is the PEM encoded CA certificate.
+	CertificatePEM []byte
+	// PrivateKeyPEM is the PKCS#8 PEM encoded private key of the CA.
+	PrivateKeyPEM []byte
+}
+
+// NewSelfSignedCA generates an ECDSA P-256 key pair and a self-signed CA
+// certificate with the given common name that is valid for the given duration.
+func NewSelfSignedCA(commonName string, validity time.Duration) (*SelfSignedCA, error) {
+	privateKey, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the CA private key: %w", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the CA serial number: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName: commonName,
+		},
+		NotBefore: now,
+		NotAfter:  now.Add(validity),
+
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certificatePEM, certificate, err := pki.SignCertificate(template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to self-sign the CA certificate: %w", err)
+	}
+
+	privateKeyPEM, err := pki.EncodePKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the CA private key: %w", err)
+	}
+
+	return &SelfSignedCA{
+		Certificate:    certificate,
+		PrivateKey:     privateKey,
+		CertificatePEM: certificatePEM,
+		PrivateKeyPEM:  privateKeyPEM,
+	}, nil
+}
+
+// LoadSelfSignedCA loads a CA from a PEM encoded CA certificate and private
+// key, eg. the CertificatePEM and PrivateKeyPEM of a CA that was generated
+// using NewSelfSignedCA. An error is returned if the certificate is not a CA
+// certificate or if the private key does not match the certificate.
+func LoadSelfSignedCA(certificatePEM []byte, privateKeyPEM []byte) (*SelfSignedCA, error) {
+	certificate, err := pki.DecodeX509CertificateBytes(certificatePEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the CA certificate: %w", err)
+	}
+	if !certificate.IsCA {
+		return nil, fmt.Errorf("the certificate is not a CA certificate")
+	}
+
+	privateKey, err := pki.DecodePrivateKeyBytes(privateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the CA private key: %w", err)
+	}
+
+	if matches, err := pki.PublicKeyMatchesCertificate(privateKey.Public(), certificate); err != nil {
+		return nil, fmt.Errorf("failed to compare the CA private key with the certificate: %w", err)
+	} else if !matches {
+		return nil, fmt.Errorf("the CA private key does not match the certificate")
+	}
+
+	return &SelfSignedCA{
+		Certificate:    certificate,
+		PrivateKey:     privateKey,
+		CertificatePEM: certificatePEM,
+		PrivateKeyPEM:  privateKeyPEM,
+	}, nil
+}
+
+// Check is a Check function that succeeds as long as the CA certificate is
+// valid. Once the CA certificate has expired, a PermanentError is returned,
+// because the CA has to be replaced.
+func (ca *SelfSignedCA) Check(_ context.Context, _ v1alpha1.Issuer) error {
+	if now := time.Now(); now.After(ca.Certificate.NotAfter) {
+		return PermanentError{Err: fmt.Errorf("the CA certificate expired at %s", ca.Certificate.NotAfter.UTC().Format(time.RFC3339))}
+	}
+
+	return nil
+}
+
+// Sign is a Sign function that signs the request using the CA. The NotAfter
+// of the signed certificate is capped at the NotAfter of the CA certificate.
+// The CA certificate is returned as the CAPEM of the bundle.
+func (ca *SelfSignedCA) Sign(_ context.Context, cr CertificateRequestObject, _ v1alpha1.Issuer) (PEMBundle, error) {
+	template, _, _, err := cr.GetRequest()
+	if err != nil {
+		// The request is invalid, retrying won't help.
+		return PEMBundle{}, PermanentError{Err: fmt.Errorf("failed to parse the request: %w", err)}
+	}
+
+	if template.NotAfter.After(ca.Certificate.NotAfter) {
+		template.NotAfter = ca.Certificate.NotAfter
+	}
+
+	certificatePEM, certificate, err := pki.SignCertificate(template, ca.Certificate, template.PublicKey, ca.PrivateKey)
+	if err != nil {
+		return PEMBundle{}, err
+	}
+
+	return PEMBundle{
+		ChainPEM: certificatePEM,
+		CAPEM:    ca.CertificatePEM,
+		NotAfter: certificate.NotAfter,
+	}, nil
+}
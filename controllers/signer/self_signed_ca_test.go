@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/cert-manager/cert-manager/pkg/util/pki"
+	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+)
+
+func TestSelfSignedCA(t *testing.T) {
+	t.Parallel()
+
+	ca, err := NewSelfSignedCA("test-ca", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, ca.Check(context.TODO(), nil))
+
+	// The CA can be stored and loaded again.
+	loaded, err := LoadSelfSignedCA(ca.CertificatePEM, ca.PrivateKeyPEM)
+	require.NoError(t, err)
+	assert.Equal(t, ca.Certificate.Raw, loaded.Certificate.Raw)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Certificate)
+
+	sk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	require.NoError(t, err)
+	csrPEM, err := cmgen.CSRWithSigner(sk, cmgen.SetCSRCommonName("test"), cmgen.SetCSRDNSNames("example.com"))
+	require.NoError(t, err)
+
+	objects := map[string]CertificateRequestObject{
+		"CertificateRequest": CertificateRequestObjectFromCertificateRequest(
+			cmgen.CertificateRequest("cr1", cmgen.SetCertificateRequestCSR(csrPEM)),
+		),
+		"CertificateSigningRequest": CertificateRequestObjectFromCertificateSigningRequest(
+			cmgen.CertificateSigningRequest("csr1",
+				cmgen.SetCertificateSigningRequestRequest(csrPEM),
+				cmgen.SetCertificateSigningRequestUsages([]certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageServerAuth}),
+			),
+		),
+	}
+
+	for kind, object := range objects {
+		bundle, err := loaded.Sign(context.TODO(), object, nil)
+		require.NoError(t, err, kind)
+		assert.Equal(t, ca.CertificatePEM, bundle.CAPEM, kind)
+
+		leaf, err := pki.DecodeX509CertificateBytes(bundle.ChainPEM)
+		require.NoError(t, err, kind)
+		assert.Equal(t, []string{"example.com"}, leaf.DNSNames, kind)
+		assert.Equal(t, leaf.NotAfter, bundle.NotAfter, kind)
+		assert.False(t, leaf.NotAfter.After(ca.Certificate.NotAfter), "%s: the NotAfter must be capped at the NotAfter of the CA", kind)
+
+		matches, err := pki.PublicKeyMatchesCertificate(sk.Public(), leaf)
+		require.NoError(t, err, kind)
+		assert.True(t, matches, kind)
+
+		_, err = leaf.Verify(x509.VerifyOptions{
+			DNSName:   "example.com",
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		assert.NoError(t, err, kind)
+	}
+}
+
+func TestLoadSelfSignedCAKeyMismatch(t *testing.T) {
+	t.Parallel()
+
+	ca1, err := NewSelfSignedCA("test-ca-1", time.Hour)
+	require.NoError(t, err)
+	ca2, err := NewSelfSignedCA("test-ca-2", time.Hour)
+	require.NoError(t, err)
+
+	_, err = LoadSelfSignedCA(ca1.CertificatePEM, ca2.PrivateKeyPEM)
+	assert.EqualError(t, err, "the CA private key does not match the certificate")
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// joinValidationErrors combines the results of multiple request validations
+// into a single PermanentError that wraps a ValidationError with all the
+// violations, so that they are reported together. Permanent errors that are
+// not ValidationErrors are added as a single violation. If one of the
+// validations returned a non-permanent error (eg. because the validation
+// itself failed), that error is returned instead, so that it is retried.
+func joinValidationErrors(errs ...error) error {
+	var violations []string
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		var validationError signer.ValidationError
+		switch {
+		case errors.As(err, &validationError):
+			violations = append(violations, validationError.Violations...)
+		case errors.As(err, &signer.PermanentError{}):
+			violations = append(violations, err.Error())
+		default:
+			return err
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return signer.PermanentError{Err: signer.ValidationError{Violations: violations}}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+)
+
+func TestJoinValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name            string
+		errs            []error
+		validateError   *errormatch.Matcher
+		expectPermanent bool
+	}
+
+	tests := []testCase{
+		{
+			name: "no-errors",
+			errs: []error{nil, nil},
+		},
+		{
+			name: "single-violation",
+			errs: []error{
+				signer.ValidationError{Violations: []string{"[violation 1]"}},
+				nil,
+			},
+			validateError:   errormatch.ErrorContains("[violation 1]"),
+			expectPermanent: true,
+		},
+		{
+			name: "multiple-simultaneous-violations",
+			errs: []error{
+				signer.ValidationError{Violations: []string{"[violation 1]", "[violation 2]"}},
+				signer.PermanentError{Err: signer.ValidationError{Violations: []string{"[violation 3]"}}},
+				signer.PermanentError{Err: fmt.Errorf("[violation 4]")},
+			},
+			validateError:   errormatch.ErrorContains("4 validation errors: [violation 1]; [violation 2]; [violation 3]; [violation 4]"),
+			expectPermanent: true,
+		},
+		{
+			name: "retryable-error-is-returned",
+			errs: []error{
+				signer.ValidationError{Violations: []string{"[violation 1]"}},
+				fmt.Errorf("[retryable error]"),
+			},
+			validateError:   errormatch.ErrorContains("[retryable error]"),
+			expectPermanent: false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := joinValidationErrors(tc.errs...)
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+			assert.Equal(t, tc.expectPermanent, errors.As(err, &signer.PermanentError{}))
+		})
+	}
+}
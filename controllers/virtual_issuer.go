@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/controllers/signer"
+)
+
+// VirtualIssuer is a signer for Kubernetes CSRs that is not backed by an issuer
+// resource. The Kubernetes CSRs are matched using only their signerName and
+// are signed using the static configuration of the VirtualIssuer.
+type VirtualIssuer struct {
+	// SignerNames are the signerNames of the Kubernetes CSRs that are signed by
+	// this VirtualIssuer, eg. "example.com/internal-ca". A VirtualIssuer takes
+	// precedence over the issuer types for these signerNames.
+	SignerNames []string
+
+	// Issuer is the static configuration that is passed to Check and Sign. It
+	// is never read from or written to the API server, so its status is not
+	// used. Its type must be registered in the scheme of the manager.
+	Issuer v1alpha1.Issuer
+
+	// Check is an optional function that is called before each Sign call. If
+	// it returns an error, the request is not signed and is retried later.
+	Check signer.Check
+
+	// Sign signs the Kubernetes CSRs that are addressed to this VirtualIssuer.
+	Sign signer.Sign
+}
+
+func (vi *VirtualIssuer) validate(scheme *runtime.Scheme) error {
+	if len(vi.SignerNames) == 0 {
+		return fmt.Errorf("the virtual issuer must have at least one signer name")
+	}
+	if vi.Sign == nil {
+		return fmt.Errorf("the virtual issuer for %q must have a Sign function", vi.SignerNames)
+	}
+	if err := ValidateIssuerType(scheme, vi.Issuer); err != nil {
+		return fmt.Errorf("the virtual issuer for %q has an invalid Issuer: %w", vi.SignerNames, err)
+	}
+	return nil
+}
@@ -22,24 +22,41 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cert-manager/issuer-lib/api/v1alpha1"
 )
 
+// GVKForIssuer returns the GroupVersionKind that is registered in the scheme
+// for the issuer type. This is the GVK that issuer-lib uses for the issuer.
+// It is exported for downstream code by controllers.GVKForIssuer.
+func GVKForIssuer(scheme *runtime.Scheme, issuer v1alpha1.Issuer) (schema.GroupVersionKind, error) {
+	return gvkForObject(scheme, issuer)
+}
+
 // setGroupVersionKind populates the Group and Kind fields of obj using the
 // scheme type registry.
 // Inspired by https://github.com/kubernetes-sigs/controller-runtime/issues/1735#issuecomment-984763173
 func SetGroupVersionKind(scheme *runtime.Scheme, obj client.Object) error {
-	gvks, unversioned, err := scheme.ObjectKinds(obj)
+	gvk, err := gvkForObject(scheme, obj)
 	if err != nil {
 		return err
 	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	return nil
+}
+
+func gvkForObject(scheme *runtime.Scheme, obj runtime.Object) (schema.GroupVersionKind, error) {
+	gvks, unversioned, err := scheme.ObjectKinds(obj)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
 	if unversioned {
-		return fmt.Errorf("ObjectKinds unexpectedly returned unversioned: %#v", unversioned)
+		return schema.GroupVersionKind{}, fmt.Errorf("ObjectKinds unexpectedly returned unversioned: %#v", unversioned)
 	}
 	if len(gvks) != 1 {
-		return fmt.Errorf("ObjectKinds unexpectedly returned zero or multiple gvks: %#v", gvks)
+		return schema.GroupVersionKind{}, fmt.Errorf("ObjectKinds unexpectedly returned zero or multiple gvks: %#v", gvks)
 	}
-	obj.GetObjectKind().SetGroupVersionKind(gvks[0])
-	return nil
+	return gvks[0], nil
 }
 
 func NewListObject(scheme *runtime.Scheme, gvk schema.GroupVersionKind) (client.ObjectList, error) {
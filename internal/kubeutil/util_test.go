@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	v1alpha1 "github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/internal/tests/errormatch"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+)
+
+func TestGVKForIssuer(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+
+	type testCase struct {
+		name          string
+		scheme        *runtime.Scheme
+		issuer        v1alpha1.Issuer
+		expectedGVK   schema.GroupVersionKind
+		validateError *errormatch.Matcher
+	}
+
+	tests := []testCase{
+		{
+			name:        "issuer",
+			scheme:      scheme,
+			issuer:      &api.SimpleIssuer{},
+			expectedGVK: api.SchemeGroupVersion.WithKind("SimpleIssuer"),
+		},
+		{
+			name:        "cluster-issuer",
+			scheme:      scheme,
+			issuer:      &api.SimpleClusterIssuer{},
+			expectedGVK: api.SchemeGroupVersion.WithKind("SimpleClusterIssuer"),
+		},
+		{
+			name:          "not-registered",
+			scheme:        runtime.NewScheme(),
+			issuer:        &api.SimpleIssuer{},
+			validateError: errormatch.ErrorContains("no kind is registered for the type"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gvk, err := GVKForIssuer(tc.scheme, tc.issuer)
+			ptr.Deref(tc.validateError, *errormatch.NoError())(t, err)
+			assert.Equal(t, tc.expectedGVK, gvk)
+		})
+	}
+}
@@ -31,6 +31,12 @@ type issuerApplyConfiguration struct {
 	Status                           *v1alpha1.IssuerStatus `json:"status,omitempty"`
 }
 
+// GenerateIssuerStatusPatch generates a server-side apply patch for the status
+// of an issuer. The patch only contains the fields of the v1alpha1.IssuerStatus
+// (the conditions and the last check error), independent of the status type of
+// the issuer. This way, the field manager only takes ownership of these fields,
+// and custom status fields that are set by another field manager (or using an
+// update) are never removed by the patch.
 func GenerateIssuerStatusPatch(
 	issuerType v1alpha1.Issuer,
 	name string,
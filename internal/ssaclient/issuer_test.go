@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssaclient
+
+import (
+	"testing"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/cert-manager/issuer-lib/api/v1alpha1"
+	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
+)
+
+func TestGenerateIssuerStatusPatch(t *testing.T) {
+	t.Parallel()
+
+	issuerType := &api.SimpleIssuer{}
+	issuerType.SetGroupVersionKind(api.SchemeGroupVersion.WithKind("SimpleIssuer"))
+
+	transitionTime := metav1.NewTime(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	issuerObject, patch, err := GenerateIssuerStatusPatch(issuerType, "issuer-1", "ns1", &v1alpha1.IssuerStatus{
+		Conditions: []cmapi.IssuerCondition{
+			{
+				Type:               cmapi.IssuerConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             v1alpha1.IssuerConditionReasonChecked,
+				Message:            "Succeeded checking the issuer",
+				LastTransitionTime: &transitionTime,
+				ObservedGeneration: 2,
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "issuer-1", issuerObject.GetName())
+	assert.Equal(t, "ns1", issuerObject.GetNamespace())
+	assert.Equal(t, types.ApplyPatchType, patch.Type())
+
+	data, err := patch.Data(issuerObject)
+	require.NoError(t, err)
+
+	// The patch must only contain the fields that are owned by issuer-lib, so
+	// that server-side apply does not remove the custom status fields that
+	// are set by another field manager.
+	var applied unstructured.Unstructured
+	require.NoError(t, applied.UnmarshalJSON(data))
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": api.SchemeGroupVersion.Identifier(),
+		"kind":       "SimpleIssuer",
+		"metadata": map[string]interface{}{
+			"name":      "issuer-1",
+			"namespace": "ns1",
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":               "Ready",
+					"status":             "True",
+					"reason":             "Checked",
+					"message":            "Succeeded checking the issuer",
+					"lastTransitionTime": "2023-01-02T03:04:05Z",
+					"observedGeneration": int64(2),
+				},
+			},
+		},
+	}, applied.Object)
+}
@@ -35,10 +35,10 @@ import (
 	"github.com/cert-manager/issuer-lib/internal/testsetups/simple/api"
 )
 
-// +kubebuilder:rbac:groups=cert-manager.io,resources=certificaterequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificaterequests,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=cert-manager.io,resources=certificaterequests/status,verbs=patch
 
-// +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests/status,verbs=patch
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=signers,verbs=sign,resourceNames=simpleissuers.issuer.cert-manager.io/*;simpleclusterissuers.issuer.cert-manager.io/*
 
@@ -57,9 +57,8 @@ func (s Signer) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 		FieldOwner:       "simpleissuer.testing.cert-manager.io",
 		MaxRetryDuration: 1 * time.Minute,
 
-		Sign:          s.Sign,
-		Check:         s.Check,
-		EventRecorder: mgr.GetEventRecorderFor("simpleissuer.testing.cert-manager.io"),
+		Sign:  s.Sign,
+		Check: s.Check,
 	}).SetupWithManager(ctx, mgr)
 }
 
@@ -20,14 +20,20 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/x509"
 	"fmt"
 	mathrand "math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	cmutil "github.com/cert-manager/cert-manager/pkg/api/util"
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	v1 "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"github.com/cert-manager/cert-manager/pkg/util/pki"
 	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
 	"github.com/stretchr/testify/require"
 	certificatesv1 "k8s.io/api/certificates/v1"
@@ -37,6 +43,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/cert-manager/issuer-lib/internal/tests/testcontext"
 	"github.com/cert-manager/issuer-lib/internal/tests/testresource"
@@ -88,6 +95,267 @@ func TestSimpleCertificate(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// defaultManySANsCount is the default number of DNS SANs that is requested by
+// TestSimpleCertificateManySANs, it can be overridden using the E2E_SAN_COUNT
+// environment variable.
+const defaultManySANsCount = 100
+
+// TestSimpleCertificateManySANs verifies that a Certificate with a long list of
+// DNS SANs (and thus a large CSR) is issued and that none of the SANs is lost
+// in the issuer-lib pipeline or the signer interface.
+func TestSimpleCertificateManySANs(t *testing.T) {
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.EndToEndTest)
+
+	sanCount := defaultManySANsCount
+	if value := os.Getenv("E2E_SAN_COUNT"); value != "" {
+		var err error
+		sanCount, err = strconv.Atoi(value)
+		require.NoError(t, err, "invalid E2E_SAN_COUNT")
+	}
+
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer("issuer-test",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	dnsNames := make([]string, 0, sanCount)
+	for i := 0; i < sanCount; i++ {
+		dnsNames = append(dnsNames, fmt.Sprintf("san-%d.test.com", i))
+	}
+
+	certificate := cmgen.Certificate(
+		"test-cert",
+		cmgen.SetCertificateNamespace(namespace),
+		cmgen.SetCertificateDNSNames(dnsNames...),
+		cmgen.SetCertificateSecretName("many-sans"),
+		cmgen.SetCertificateIssuer(v1.ObjectReference{
+			Group: issuer.GroupVersionKind().Group,
+			Kind:  issuer.Kind,
+			Name:  issuer.Name,
+		}),
+	)
+
+	err := kubeClients.Client.Create(ctx, issuer)
+	require.NoError(t, err)
+
+	complete := kubeClients.StartObjectWatch(t, ctx, certificate)
+
+	err = kubeClients.Client.Create(ctx, certificate)
+	require.NoError(t, err)
+
+	err = complete(func(cert runtime.Object) error {
+		condition := cmutil.GetCertificateCondition(cert.(*cmapi.Certificate), cmapi.CertificateConditionReady)
+
+		if (condition == nil) ||
+			(condition.Status != v1.ConditionTrue) {
+			return fmt.Errorf("ready condition is not correct (yet): %v", condition)
+		}
+
+		return nil
+	}, watch.Added, watch.Modified)
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	err = kubeClients.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "many-sans"}, &secret)
+	require.NoError(t, err)
+
+	certs, err := pki.DecodeX509CertificateChainBytes(secret.Data[corev1.TLSCertKey])
+	require.NoError(t, err)
+	require.NotEmpty(t, certs)
+
+	require.ElementsMatch(t, dnsNames, certs[0].DNSNames)
+}
+
+// TestSimpleCertificateKeyAlgorithms verifies that Certificates with non-RSA
+// private keys are issued and that the issued certificate contains the public
+// key of the generated private key. Key algorithms that are not supported by
+// the issuer under test can be skipped by listing them in the comma-separated
+// E2E_SKIP_KEY_ALGORITHMS environment variable, eg. "ECDSA-384,Ed25519".
+func TestSimpleCertificateKeyAlgorithms(t *testing.T) {
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.EndToEndTest)
+
+	skipped := map[string]bool{}
+	for _, name := range strings.Split(os.Getenv("E2E_SKIP_KEY_ALGORITHMS"), ",") {
+		skipped[strings.TrimSpace(name)] = true
+	}
+
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer("issuer-test",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	err := kubeClients.Client.Create(ctx, issuer)
+	require.NoError(t, err)
+
+	type testCase struct {
+		name      string
+		algorithm cmapi.PrivateKeyAlgorithm
+		size      int
+	}
+
+	tests := []testCase{
+		{name: "ECDSA-256", algorithm: cmapi.ECDSAKeyAlgorithm, size: 256},
+		{name: "ECDSA-384", algorithm: cmapi.ECDSAKeyAlgorithm, size: 384},
+		{name: "Ed25519", algorithm: cmapi.Ed25519KeyAlgorithm},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if skipped[tc.name] {
+				t.Skipf("key algorithm %s is skipped using E2E_SKIP_KEY_ALGORITHMS", tc.name)
+			}
+
+			secretName := "key-" + strings.ToLower(tc.name)
+
+			certificate := cmgen.Certificate(
+				"test-cert-"+strings.ToLower(tc.name),
+				cmgen.SetCertificateNamespace(namespace),
+				cmgen.SetCertificateCommonName("test.com"),
+				cmgen.SetCertificateSecretName(secretName),
+				cmgen.SetCertificateKeyAlgorithm(tc.algorithm),
+				cmgen.SetCertificateKeySize(tc.size),
+				cmgen.SetCertificateIssuer(v1.ObjectReference{
+					Group: issuer.GroupVersionKind().Group,
+					Kind:  issuer.Kind,
+					Name:  issuer.Name,
+				}),
+			)
+
+			complete := kubeClients.StartObjectWatch(t, ctx, certificate)
+
+			err := kubeClients.Client.Create(ctx, certificate)
+			require.NoError(t, err)
+
+			err = complete(func(cert runtime.Object) error {
+				condition := cmutil.GetCertificateCondition(cert.(*cmapi.Certificate), cmapi.CertificateConditionReady)
+
+				if (condition == nil) ||
+					(condition.Status != v1.ConditionTrue) {
+					return fmt.Errorf("ready condition is not correct (yet): %v", condition)
+				}
+
+				return nil
+			}, watch.Added, watch.Modified)
+			require.NoError(t, err)
+
+			var secret corev1.Secret
+			err = kubeClients.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretName}, &secret)
+			require.NoError(t, err)
+
+			privateKey, err := pki.DecodePrivateKeyBytes(secret.Data[corev1.TLSPrivateKeyKey])
+			require.NoError(t, err)
+
+			leaf, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+			require.NoError(t, err)
+
+			matches, err := pki.PublicKeyMatchesCertificate(privateKey.Public(), leaf)
+			require.NoError(t, err)
+			require.True(t, matches, "the public key of the issued certificate does not match the private key")
+		})
+	}
+}
+
+// TestSimpleCertificateExtendedKeyUsages verifies that the extended key usages
+// requested by a Certificate are honored by the issuer, instead of a fixed set
+// of extended key usages being applied. The extended key usages that are
+// supported by the issuer under test can be declared using the comma-separated
+// E2E_SUPPORTED_EXT_KEY_USAGES environment variable, eg. "code signing", the
+// other extended key usages are skipped. By default, all are tested.
+func TestSimpleCertificateExtendedKeyUsages(t *testing.T) {
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.EndToEndTest)
+
+	var supported map[cmapi.KeyUsage]bool
+	if value := os.Getenv("E2E_SUPPORTED_EXT_KEY_USAGES"); value != "" {
+		supported = map[cmapi.KeyUsage]bool{}
+		for _, name := range strings.Split(value, ",") {
+			supported[cmapi.KeyUsage(strings.TrimSpace(name))] = true
+		}
+	}
+
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer("issuer-test",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	err := kubeClients.Client.Create(ctx, issuer)
+	require.NoError(t, err)
+
+	type testCase struct {
+		name        string
+		usage       cmapi.KeyUsage
+		extKeyUsage x509.ExtKeyUsage
+	}
+
+	tests := []testCase{
+		{name: "code-signing", usage: cmapi.UsageCodeSigning, extKeyUsage: x509.ExtKeyUsageCodeSigning},
+		{name: "ocsp-signing", usage: cmapi.UsageOCSPSigning, extKeyUsage: x509.ExtKeyUsageOCSPSigning},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if supported != nil && !supported[tc.usage] {
+				t.Skipf("extended key usage %q is not listed in E2E_SUPPORTED_EXT_KEY_USAGES", tc.usage)
+			}
+
+			secretName := "eku-" + tc.name
+
+			certificate := cmgen.Certificate(
+				"test-cert-"+tc.name,
+				cmgen.SetCertificateNamespace(namespace),
+				cmgen.SetCertificateCommonName("test.com"),
+				cmgen.SetCertificateSecretName(secretName),
+				cmgen.SetCertificateKeyUsages(cmapi.UsageDigitalSignature, tc.usage),
+				cmgen.SetCertificateIssuer(v1.ObjectReference{
+					Group: issuer.GroupVersionKind().Group,
+					Kind:  issuer.Kind,
+					Name:  issuer.Name,
+				}),
+			)
+
+			complete := kubeClients.StartObjectWatch(t, ctx, certificate)
+
+			err := kubeClients.Client.Create(ctx, certificate)
+			require.NoError(t, err)
+
+			err = complete(func(cert runtime.Object) error {
+				condition := cmutil.GetCertificateCondition(cert.(*cmapi.Certificate), cmapi.CertificateConditionReady)
+
+				if (condition == nil) ||
+					(condition.Status != v1.ConditionTrue) {
+					return fmt.Errorf("ready condition is not correct (yet): %v", condition)
+				}
+
+				return nil
+			}, watch.Added, watch.Modified)
+			require.NoError(t, err)
+
+			var secret corev1.Secret
+			err = kubeClients.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretName}, &secret)
+			require.NoError(t, err)
+
+			leaf, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+			require.NoError(t, err)
+
+			require.Equal(t, []x509.ExtKeyUsage{tc.extKeyUsage}, leaf.ExtKeyUsage)
+			require.Empty(t, leaf.UnknownExtKeyUsage)
+		})
+	}
+}
+
 func TestSimpleCertificateSigningRequest(t *testing.T) {
 	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.EndToEndTest)
 
@@ -152,6 +420,212 @@ func TestSimpleCertificateSigningRequest(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestSimpleCertificateRequestEvents verifies that events with recognizable
+// reasons are recorded: a Normal "Checked" event on the issuer, a Normal
+// "Issued" event on a CertificateRequest that is signed and a Warning event on
+// a CertificateRequest that fails (because its CSR is too large).
+func TestSimpleCertificateRequestEvents(t *testing.T) {
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.EndToEndTest)
+
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer("issuer-test",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	newCertificateRequest := func(name string, dnsNames ...string) *cmapi.CertificateRequest {
+		csrBlob, err := cmgen.CSRWithSigner(privateKey,
+			cmgen.SetCSRCommonName("test.com"),
+			cmgen.SetCSRDNSNames(dnsNames...),
+		)
+		require.NoError(t, err)
+
+		return cmgen.CertificateRequest(
+			name,
+			cmgen.SetCertificateRequestNamespace(namespace),
+			cmgen.SetCertificateRequestCSR(csrBlob),
+			cmgen.SetCertificateRequestIssuer(v1.ObjectReference{
+				Group: issuer.GroupVersionKind().Group,
+				Kind:  issuer.Kind,
+				Name:  issuer.Name,
+			}),
+		)
+	}
+
+	// The CSR of this request is larger than the default maximum CSR size of
+	// 256KiB, so it is failed permanently without being signed.
+	tooManyDNSNames := make([]string, 0, 6000)
+	for i := 0; i < cap(tooManyDNSNames); i++ {
+		tooManyDNSNames = append(tooManyDNSNames, fmt.Sprintf("san-%d.%s.test.com", i, randStringRunes(20)))
+	}
+
+	issuedRequest := newCertificateRequest("issued")
+	failedRequest := newCertificateRequest("failed", tooManyDNSNames...)
+
+	err = kubeClients.Client.Create(ctx, issuer)
+	require.NoError(t, err)
+
+	for _, cr := range []*cmapi.CertificateRequest{issuedRequest, failedRequest} {
+		err = kubeClients.Client.Create(ctx, cr)
+		require.NoError(t, err)
+	}
+
+	// hasEvent checks if an event with the given type and reason was recorded
+	// for the object with the given kind and name.
+	hasEvent := func(kind string, name string, eventType string, reason string) bool {
+		var events corev1.EventList
+		if err := kubeClients.Client.List(ctx, &events,
+			client.InNamespace(namespace),
+			client.MatchingFields{
+				"involvedObject.kind": kind,
+				"involvedObject.name": name,
+			},
+		); err != nil {
+			t.Logf("failed to list events: %v", err)
+			return false
+		}
+
+		for _, event := range events.Items {
+			if event.Type == eventType && event.Reason == reason {
+				return true
+			}
+		}
+		return false
+	}
+
+	require.Eventually(t, func() bool {
+		return hasEvent(issuer.Kind, issuer.Name, corev1.EventTypeNormal, "Checked")
+	}, 2*time.Minute, time.Second, "expected a Normal Checked event on the issuer")
+
+	require.Eventually(t, func() bool {
+		return hasEvent("CertificateRequest", issuedRequest.Name, corev1.EventTypeNormal, "Issued")
+	}, 2*time.Minute, time.Second, "expected a Normal Issued event on the issued CertificateRequest")
+
+	require.Eventually(t, func() bool {
+		return hasEvent("CertificateRequest", failedRequest.Name, corev1.EventTypeWarning, "RequestTooLarge")
+	}, 2*time.Minute, time.Second, "expected a Warning RequestTooLarge event on the failed CertificateRequest")
+}
+
+// defaultConcurrentCertificates and defaultConcurrentTimeout are the default
+// number of Certificates that are created by TestSimpleCertificateConcurrent
+// and the time they get to become Ready, they can be overridden using the
+// E2E_CONCURRENT_CERTIFICATES and E2E_CONCURRENT_TIMEOUT environment variables.
+const (
+	defaultConcurrentCertificates = 20
+	defaultConcurrentTimeout      = 5 * time.Minute
+)
+
+// TestSimpleCertificateConcurrent verifies that many Certificates that are
+// created at the same time all become Ready and that each of them gets its own
+// certificate: with the public key of its own private key and with its own
+// SANs. This surfaces race conditions in the signer and in the reconcile
+// pipeline that mix up requests.
+func TestSimpleCertificateConcurrent(t *testing.T) {
+	ctx := testresource.EnsureTestDependencies(t, testcontext.ForTest(t), testresource.EndToEndTest)
+
+	count := defaultConcurrentCertificates
+	if value := os.Getenv("E2E_CONCURRENT_CERTIFICATES"); value != "" {
+		var err error
+		count, err = strconv.Atoi(value)
+		require.NoError(t, err, "invalid E2E_CONCURRENT_CERTIFICATES")
+	}
+
+	timeout := defaultConcurrentTimeout
+	if value := os.Getenv("E2E_CONCURRENT_TIMEOUT"); value != "" {
+		var err error
+		timeout, err = time.ParseDuration(value)
+		require.NoError(t, err, "invalid E2E_CONCURRENT_TIMEOUT")
+	}
+
+	kubeClients := testresource.KubeClients(t, ctx)
+
+	namespace, cleanup := kubeClients.SetupNamespace(t, ctx)
+	defer cleanup()
+
+	issuer := testutil.SimpleIssuer("issuer-test",
+		testutil.SetSimpleIssuerNamespace(namespace),
+	)
+
+	err := kubeClients.Client.Create(ctx, issuer)
+	require.NoError(t, err)
+
+	certificates := make([]*cmapi.Certificate, 0, count)
+	for i := 0; i < count; i++ {
+		certificates = append(certificates, cmgen.Certificate(
+			fmt.Sprintf("test-cert-%d", i),
+			cmgen.SetCertificateNamespace(namespace),
+			cmgen.SetCertificateDNSNames(fmt.Sprintf("concurrent-%d.test.com", i)),
+			cmgen.SetCertificateSecretName(fmt.Sprintf("concurrent-%d", i)),
+			cmgen.SetCertificateIssuer(v1.ObjectReference{
+				Group: issuer.GroupVersionKind().Group,
+				Kind:  issuer.Kind,
+				Name:  issuer.Name,
+			}),
+		))
+	}
+
+	var wg sync.WaitGroup
+	createErrors := make([]error, count)
+	for i, certificate := range certificates {
+		i, certificate := i, certificate
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			createErrors[i] = kubeClients.Client.Create(ctx, certificate)
+		}()
+	}
+	wg.Wait()
+	for _, err := range createErrors {
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		var list cmapi.CertificateList
+		if err := kubeClients.Client.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+			t.Logf("failed to list certificates: %v", err)
+			return false
+		}
+
+		ready := 0
+		for i := range list.Items {
+			condition := cmutil.GetCertificateCondition(&list.Items[i], cmapi.CertificateConditionReady)
+			if condition != nil && condition.Status == v1.ConditionTrue {
+				ready++
+			}
+		}
+		return ready == count
+	}, timeout, time.Second, "expected all %d certificates to become Ready", count)
+
+	serialNumbers := map[string]string{}
+	for i, certificate := range certificates {
+		var secret corev1.Secret
+		err := kubeClients.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: certificate.Spec.SecretName}, &secret)
+		require.NoError(t, err)
+
+		privateKey, err := pki.DecodePrivateKeyBytes(secret.Data[corev1.TLSPrivateKeyKey])
+		require.NoError(t, err)
+
+		leaf, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+		require.NoError(t, err)
+
+		matches, err := pki.PublicKeyMatchesCertificate(privateKey.Public(), leaf)
+		require.NoError(t, err)
+		require.True(t, matches, "the public key of the certificate of %s does not match its private key", certificate.Name)
+
+		require.Equal(t, []string{fmt.Sprintf("concurrent-%d.test.com", i)}, leaf.DNSNames, "the certificate of %s has the wrong SANs", certificate.Name)
+
+		serialNumber := leaf.SerialNumber.String()
+		require.NotContains(t, serialNumbers, serialNumber, "the certificates of %s and %s have the same serial number", certificate.Name, serialNumbers[serialNumber])
+		serialNumbers[serialNumber] = certificate.Name
+	}
+}
+
 var letterRunes = []rune("abcdefghijklmnopqrstuvwxyz")
 
 // RandStringRunes - generate random string using random int
@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testutil contains helpers for testing issuers that are built
+// using issuer-lib.
+package testutil
+
+import (
+	"context"
+	"fmt"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cert-manager/issuer-lib/conditions"
+)
+
+// CreateApprovedCertificateRequest creates the provided CertificateRequest
+// and sets its Approved condition, like an approval controller would do.
+// The clock is used to set the LastTransitionTime of the Approved condition.
+// The provided CertificateRequest is updated with the state returned by the
+// API server.
+func CreateApprovedCertificateRequest(ctx context.Context, cl client.Client, clock clock.PassiveClock, cr *cmapi.CertificateRequest) error {
+	if err := cl.Create(ctx, cr); err != nil {
+		return fmt.Errorf("failed to create CertificateRequest: %w", err)
+	}
+
+	conditions.SetCertificateRequestStatusCondition(
+		clock,
+		cr.Status.Conditions,
+		&cr.Status.Conditions,
+		cmapi.CertificateRequestConditionApproved,
+		cmmeta.ConditionTrue,
+		"ApprovedReason",
+		"ApprovedMessage",
+	)
+
+	if err := cl.Status().Update(ctx, cr); err != nil {
+		return fmt.Errorf("failed to approve CertificateRequest: %w", err)
+	}
+
+	return nil
+}
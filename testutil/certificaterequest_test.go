@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	cmgen "github.com/cert-manager/cert-manager/test/unit/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCreateApprovedCertificateRequest(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cmapi.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&cmapi.CertificateRequest{}).
+		Build()
+
+	fakeTime := time.Now().Truncate(time.Second)
+	fakeTimeObj := metav1.NewTime(fakeTime)
+
+	cr := cmgen.CertificateRequest(
+		"cr1",
+		cmgen.SetCertificateRequestNamespace("ns1"),
+	)
+
+	require.NoError(t, CreateApprovedCertificateRequest(context.TODO(), fakeClient, clocktesting.NewFakeClock(fakeTime), cr))
+
+	var current cmapi.CertificateRequest
+	require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(cr), &current))
+	assert.Equal(t, []cmapi.CertificateRequestCondition{
+		{
+			Type:               cmapi.CertificateRequestConditionApproved,
+			Status:             cmmeta.ConditionTrue,
+			Reason:             "ApprovedReason",
+			Message:            "ApprovedMessage",
+			LastTransitionTime: &fakeTimeObj,
+		},
+	}, current.Status.Conditions)
+
+	err := CreateApprovedCertificateRequest(context.TODO(), fakeClient, clocktesting.NewFakeClock(fakeTime), cmgen.CertificateRequest(
+		"cr1",
+		cmgen.SetCertificateRequestNamespace("ns1"),
+	))
+	assert.ErrorContains(t, err, "failed to create CertificateRequest")
+}